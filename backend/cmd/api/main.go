@@ -5,13 +5,20 @@ import (
 	"os"
 	"time"
 
+	"github.com/Athooh/social-network/internal/analytics"
 	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/internal/bookmark"
 	"github.com/Athooh/social-network/internal/config"
+	"github.com/Athooh/social-network/internal/contentfilter"
 	"github.com/Athooh/social-network/internal/follow"
 	"github.com/Athooh/social-network/internal/group"
+	"github.com/Athooh/social-network/internal/handle"
+	"github.com/Athooh/social-network/internal/mute"
 	notifications "github.com/Athooh/social-network/internal/notifcations"
 	"github.com/Athooh/social-network/internal/post"
+	"github.com/Athooh/social-network/internal/privacy"
 	"github.com/Athooh/social-network/internal/profile"
+	"github.com/Athooh/social-network/internal/report"
 	"github.com/Athooh/social-network/internal/server"
 	wsHandler "github.com/Athooh/social-network/internal/websocket"
 	"github.com/Athooh/social-network/pkg/db/sqlite"
@@ -91,6 +98,12 @@ func main() {
 	chatRepo := chat.NewSQLiteRepository(db.DB)
 	profileRepo := profile.NewSQLiteRepository(db.DB)
 	notificationsRepo := notifications.NewSQLiteRepository(db.DB)
+	bookmarkRepo := bookmark.NewSQLiteRepository(db.DB)
+	reportRepo := report.NewSQLiteRepository(db.DB)
+	analyticsRepo := analytics.NewSQLiteRepository(db.DB)
+	muteRepo := mute.NewSQLiteRepository(db.DB)
+	privacyRepo := privacy.NewSQLiteRepository(db.DB)
+	handleRepo := handle.NewSQLiteRepository(db.DB)
 
 	// Set up session manager
 	sessionManager := session.NewSessionManager(
@@ -114,28 +127,80 @@ func main() {
 		log.Fatal("Failed to create file store: %v", err)
 	}
 
+	// Set up the content filtering pipeline
+	contentFilter, err := contentfilter.NewPipelineFromConfig(cfg.ContentFilter, log)
+	if err != nil {
+		log.Fatal("Failed to create content filter: %v", err)
+	}
+
+	// Set up batched impression and profile visit recording
+	analyticsRecorder := analytics.NewRecorder(analyticsRepo, log, cfg.Analytics.ImpressionWindow, cfg.Analytics.FlushInterval)
+	go analyticsRecorder.Run()
+
 	// Set up WebSocket hub
 	wsHub := websocket.NewHub(log)
 	go wsHub.Run()
 
 	// Set up services
 	notificationsService := notifications.NewService(notificationsRepo, userRepo, log, wsHub)
-	authService := auth.NewService(userRepo, sessionManager, jwtConfig, statusRepo)
-	postNotificationSvc := post.NewNotificationService(wsHub, userRepo, notificationsService, log)
-	postService := post.NewService(postRepo, fileStore, log, postNotificationSvc)
-	statusService := userHandler.NewStatusService(statusRepo, sessionRepo, wsHub, log)
-	eventService := event.NewService(eventRepo, fileStore, log, notificationsService, wsHub)
-	groupService := group.NewService(groupRepo, fileStore, log, wsHub, notificationsService)
-	chatService := chat.NewService(chatRepo, log, wsHub)
-	followService := follow.NewService(followRepo, userRepo, statusRepo, notificationsService, log, wsHub)
-	profileService := profile.NewService(profileRepo, "./data/uploads")
+	handleService := handle.NewService(handleRepo, log)
+	authService := auth.NewService(userRepo, sessionManager, jwtConfig, statusRepo, handleService)
+	muteService := mute.NewService(muteRepo, userRepo, log)
+	privacyService := privacy.NewService(privacyRepo, log)
+	postNotificationSvc := post.NewNotificationService(wsHub, userRepo, notificationsService, muteService, log)
+	postService := post.NewService(postRepo, fileStore, log, postNotificationSvc, contentFilter, analyticsRecorder)
+	statusService := userHandler.NewStatusService(statusRepo, sessionRepo, privacyService, wsHub, log, cfg.Presence.IdleAfter, cfg.Presence.UpdateDebounce)
+	groupService := group.NewService(groupRepo, fileStore, log, wsHub, notificationsService, muteService, privacyService, handleService, contentFilter, cfg.Group.ArchiveRetention)
+	eventService := event.NewService(eventRepo, fileStore, log, notificationsService, wsHub, groupService, groupService)
+	chatService := chat.NewService(chatRepo, log, wsHub, contentFilter)
+	followService := follow.NewService(followRepo, userRepo, statusRepo, notificationsService, muteService, privacyService, log, wsHub)
+	profileService := profile.NewService(profileRepo, "./data/uploads", analyticsRecorder, followService)
+	bookmarkService := bookmark.NewService(bookmarkRepo, postRepo, log)
+	reportNotificationSvc := report.NewNotificationService(reportRepo, wsHub, notificationsService, log)
+	reportService := report.NewService(reportRepo, log, reportNotificationSvc, postService, groupService, cfg.Moderation.AutoHideThreshold)
+	analyticsService := analytics.NewService(analyticsRepo, log)
+
+	// Send flagged content to the moderation queue
+	contentFilter.SetFlagger(reportService)
+
+	// Let group moderators see hidden posts in their groups
+	postRepo.SetPermissionChecker(groupService)
 
 	// Connect the Hub to the StatusService
 	wsHub.SetStatusUpdater(statusService)
 
+	// Give users registered before handles existed a handle
+	if err := handleService.BackfillHandles(); err != nil {
+		log.Error("Failed to backfill user handles: %v", err)
+	}
+
+	// Move skill lists saved before they had their own table
+	if count, err := profileService.MigrateLegacySkills(); err != nil {
+		log.Error("Failed to migrate legacy profile skills: %v", err)
+	} else if count > 0 {
+		log.Info("Moved the skills of %d profiles to their own table", count)
+	}
+
 	// Run status cleanup to ensure consistency between sessions and online status
 	go statusService.CleanupUserStatuses()
 
+	// Move users without recent activity to idle
+	go statusService.RunIdleSweep(cfg.Presence.IdleSweepInterval)
+
+	// Clear custom status messages once they expire
+	go statusService.RunCustomStatusSweeper(cfg.Presence.StatusSweepInterval)
+
+	// Keep the daily analytics rollups up to date
+	go analyticsService.RunRollups(cfg.Analytics.RollupInterval)
+
+	// Expire follow requests left pending for too long
+	if cfg.Follow.RequestExpiry > 0 {
+		go followService.RunRequestExpirySweeper(cfg.Follow.RequestExpiry, cfg.Follow.RequestSweepInterval)
+	}
+
+	// Purge deleted groups once they can no longer be restored
+	go groupService.RunArchivePurgeSweeper(cfg.Group.PurgeSweepInterval)
+
 	// Set up handlers
 	authHandler := auth.NewHandler(authService, fileStore)
 	postHandler := post.NewHandler(postService, log)
@@ -146,6 +211,13 @@ func main() {
 	chatHandler := chat.NewHandler(chatService, log)
 	notificationHanler := notifications.NewHandler(notificationsService, log)
 	profileHandler := profile.NewHandler(profileService, log)
+	bookmarkHandler := bookmark.NewHandler(bookmarkService, log)
+	reportHandler := report.NewHandler(reportService, log)
+	analyticsHandler := analytics.NewHandler(analyticsService, log)
+	muteHandler := mute.NewHandler(muteService, log)
+	privacyHandler := privacy.NewHandler(privacyService, log)
+	handleHandler := handle.NewHandler(handleService, profileService, log)
+	statusHandler := userHandler.NewStatusHandler(statusService, log)
 
 	// Set up router with both session and JWT middleware
 	router := server.Router(server.RouterConfig{
@@ -162,6 +234,13 @@ func main() {
 		Logger:              log,
 		UploadDir:           cfg.FileStore.UploadDir,
 		ProfileHandler:      profileHandler,
+		BookmarkHandler:     bookmarkHandler,
+		ReportHandler:       reportHandler,
+		AnalyticsHandler:    analyticsHandler,
+		MuteHandler:         muteHandler,
+		PrivacyHandler:      privacyHandler,
+		StatusHandler:       statusHandler,
+		HandleHandler:       handleHandler,
 	})
 
 	// Set up server
@@ -175,6 +254,9 @@ func main() {
 
 	srv := server.New(serverConfig, router, log)
 
+	// Save buffered impressions and profile visits before exiting
+	srv.OnShutdown(analyticsRecorder.Flush)
+
 	// Start server
 	if err := srv.Start(); err != nil {
 		log.Fatal("Server error: %v", err)
@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/pkg/httputil"
+	"github.com/Athooh/social-network/pkg/logger"
+)
+
+// Handler handles HTTP requests for analytics
+type Handler struct {
+	service Service
+	log     *logger.Logger
+}
+
+// NewHandler creates a new analytics handler
+func NewHandler(service Service, log *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		log:     log,
+	}
+}
+
+// GetMyAnalytics handles getting the current user's daily analytics. The range
+// is given by the optional "from" and "to" query parameters (YYYY-MM-DD).
+func (h *Handler) GetMyAnalytics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed %s", r.Method))
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	summary, err := h.service.GetUserAnalytics(userID, query.Get("from"), query.Get("to"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, summary)
+}
+
+// Helper method to send JSON responses
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	httputil.SendJSON(w, status, data)
+}
+
+// Helper method to send error responses
+func (h *Handler) sendError(w http.ResponseWriter, status int, message string) {
+	var isWarning bool = false
+	if status >= 500 {
+		isWarning = true
+	}
+	httputil.SendError(w, status, message, isWarning)
+}
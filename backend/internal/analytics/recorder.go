@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// maxBufferedEvents triggers an early flush when this many events are waiting
+const maxBufferedEvents = 1000
+
+// Recorder buffers post impressions and profile visits in memory and writes them
+// in batches, so listing posts doesn't cost a write per row. A viewer is counted
+// once per post, and a visitor once per profile, within each impression window.
+// A nil Recorder records nothing.
+type Recorder struct {
+	repo          Repository
+	log           *logger.Logger
+	window        time.Duration
+	flushInterval time.Duration
+
+	mu          sync.Mutex
+	seen        map[string]time.Time // dedup key -> window start
+	impressions []*models.PostImpression
+	visits      []*models.ProfileVisit
+	flushCh     chan struct{}
+}
+
+// NewRecorder creates a new impression recorder
+func NewRecorder(repo Repository, log *logger.Logger, window, flushInterval time.Duration) *Recorder {
+	return &Recorder{
+		repo:          repo,
+		log:           log,
+		window:        window,
+		flushInterval: flushInterval,
+		seen:          make(map[string]time.Time),
+		flushCh:       make(chan struct{}, 1),
+	}
+}
+
+// RecordImpressions records the viewer seeing each of the posts. Authors viewing
+// their own posts are not counted.
+func (r *Recorder) RecordImpressions(viewerID string, posts []*models.Post) {
+	if r == nil || viewerID == "" || len(posts) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	windowStart := now.Truncate(r.window)
+
+	r.mu.Lock()
+	for _, post := range posts {
+		if post == nil || post.UserID == viewerID {
+			continue
+		}
+
+		key := fmt.Sprintf("%d:%s:%d", post.ID, viewerID, windowStart.Unix())
+		if _, ok := r.seen[key]; ok {
+			continue
+		}
+		r.seen[key] = windowStart
+
+		r.impressions = append(r.impressions, &models.PostImpression{
+			ID:       key,
+			PostID:   post.ID,
+			AuthorID: post.UserID,
+			ViewerID: viewerID,
+			ViewedAt: now,
+		})
+	}
+	buffered := len(r.impressions) + len(r.visits)
+	r.mu.Unlock()
+
+	r.requestFlushIfFull(buffered)
+}
+
+// RecordProfileVisit records the visitor viewing a profile. Users visiting their
+// own profile are not counted.
+func (r *Recorder) RecordProfileVisit(visitorID, profileID string) {
+	if r == nil || visitorID == "" || visitorID == profileID {
+		return
+	}
+
+	now := time.Now().UTC()
+	windowStart := now.Truncate(r.window)
+	key := fmt.Sprintf("%s:%s:%d", profileID, visitorID, windowStart.Unix())
+
+	r.mu.Lock()
+	if _, ok := r.seen[key]; ok {
+		r.mu.Unlock()
+		return
+	}
+	r.seen[key] = windowStart
+
+	r.visits = append(r.visits, &models.ProfileVisit{
+		ID:        key,
+		ProfileID: profileID,
+		VisitorID: visitorID,
+		VisitedAt: now,
+	})
+	buffered := len(r.impressions) + len(r.visits)
+	r.mu.Unlock()
+
+	r.requestFlushIfFull(buffered)
+}
+
+// requestFlushIfFull wakes the flush loop once the buffer is full
+func (r *Recorder) requestFlushIfFull(buffered int) {
+	if buffered < maxBufferedEvents {
+		return
+	}
+	select {
+	case r.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// Run flushes buffered events every flush interval, or sooner when the buffer
+// fills up. It blocks, so start it in its own goroutine.
+func (r *Recorder) Run() {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.flushCh:
+		}
+		r.Flush()
+	}
+}
+
+// Flush writes all buffered events to the database
+func (r *Recorder) Flush() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	impressions, visits := r.impressions, r.visits
+	r.impressions, r.visits = nil, nil
+
+	// Forget dedup keys from past windows; the database ignores any repeats
+	currentWindow := time.Now().UTC().Truncate(r.window)
+	for key, windowStart := range r.seen {
+		if windowStart.Before(currentWindow) {
+			delete(r.seen, key)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(impressions) > 0 {
+		if err := r.repo.SaveImpressions(impressions); err != nil {
+			r.log.Error("Failed to save %d impressions: %v", len(impressions), err)
+		}
+	}
+
+	if len(visits) > 0 {
+		if err := r.repo.SaveProfileVisits(visits); err != nil {
+			r.log.Error("Failed to save %d profile visits: %v", len(visits), err)
+		}
+	}
+}
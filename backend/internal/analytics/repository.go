@@ -0,0 +1,209 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Repository defines the interface for analytics data access
+type Repository interface {
+	// Raw events
+	SaveImpressions(impressions []*models.PostImpression) error
+	SaveProfileVisits(visits []*models.ProfileVisit) error
+
+	// Rollups
+	GetLatestRollupDay() (string, error)
+	RefreshDailyStats(sinceDay string) error
+	GetDailyStats(userID, fromDay, toDay string) ([]*models.UserDailyStat, error)
+}
+
+// SQLiteRepository implements Repository interface for SQLite
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite repository
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// SaveImpressions writes a batch of impressions in one transaction. Impressions
+// already recorded for the same window are ignored; new ones increment the
+// post's view count.
+func (r *SQLiteRepository) SaveImpressions(impressions []*models.PostImpression) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertStmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO post_impressions (id, post_id, author_id, viewer_id, viewed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare impression insert: %w", err)
+	}
+	defer insertStmt.Close()
+
+	newViews := make(map[int64]int64)
+	for _, impression := range impressions {
+		result, err := insertStmt.Exec(impression.ID, impression.PostID, impression.AuthorID, impression.ViewerID, impression.ViewedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save impression: %w", err)
+		}
+		if inserted, err := result.RowsAffected(); err == nil && inserted > 0 {
+			newViews[impression.PostID]++
+		}
+	}
+
+	for postID, count := range newViews {
+		if _, err := tx.Exec("UPDATE posts SET views_count = views_count + ? WHERE id = ?", count, postID); err != nil {
+			return fmt.Errorf("failed to update view count: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SaveProfileVisits writes a batch of profile visits in one transaction,
+// ignoring visits already recorded for the same window
+func (r *SQLiteRepository) SaveProfileVisits(visits []*models.ProfileVisit) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertStmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO profile_visits (id, profile_id, visitor_id, visited_at)
+		VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare profile visit insert: %w", err)
+	}
+	defer insertStmt.Close()
+
+	for _, visit := range visits {
+		if _, err := insertStmt.Exec(visit.ID, visit.ProfileID, visit.VisitorID, visit.VisitedAt); err != nil {
+			return fmt.Errorf("failed to save profile visit: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLatestRollupDay returns the most recent day with a rollup, or "" if none
+func (r *SQLiteRepository) GetLatestRollupDay() (string, error) {
+	var day sql.NullString
+	if err := r.db.QueryRow("SELECT MAX(day) FROM user_daily_stats").Scan(&day); err != nil {
+		return "", fmt.Errorf("failed to get latest rollup day: %w", err)
+	}
+	return day.String, nil
+}
+
+// rollupMetrics maps each rollup column to the query counting its events as
+// (user_id, day, total) rows. Each query takes the first day to count from.
+var rollupMetrics = map[string]string{
+	"impressions": `
+		SELECT author_id AS user_id, date(viewed_at) AS day, COUNT(*) AS total
+		FROM post_impressions
+		WHERE date(viewed_at) >= ?
+		GROUP BY author_id, date(viewed_at)`,
+	"likes": `
+		SELECT p.user_id AS user_id, date(pl.created_at) AS day, COUNT(*) AS total
+		FROM post_likes pl
+		JOIN (SELECT id, user_id FROM posts UNION ALL SELECT id, user_id FROM group_posts) p ON p.id = pl.post_id
+		WHERE date(pl.created_at) >= ? AND pl.user_id != p.user_id
+		GROUP BY p.user_id, date(pl.created_at)`,
+	"comments": `
+		SELECT p.user_id AS user_id, date(c.created_at) AS day, COUNT(*) AS total
+		FROM comments c
+		JOIN (SELECT id, user_id FROM posts UNION ALL SELECT id, user_id FROM group_posts) p ON p.id = c.post_id
+		WHERE date(c.created_at) >= ? AND c.user_id != p.user_id
+		GROUP BY p.user_id, date(c.created_at)`,
+	"new_followers": `
+		SELECT following_id AS user_id, date(created_at) AS day, COUNT(*) AS total
+		FROM followers
+		WHERE date(created_at) >= ?
+		GROUP BY following_id, date(created_at)`,
+	"profile_visits": `
+		SELECT profile_id AS user_id, date(visited_at) AS day, COUNT(*) AS total
+		FROM profile_visits
+		WHERE date(visited_at) >= ?
+		GROUP BY profile_id, date(visited_at)`,
+}
+
+// RefreshDailyStats recomputes every rollup from sinceDay (YYYY-MM-DD)
+// onwards. An empty sinceDay recomputes everything.
+func (r *SQLiteRepository) RefreshDailyStats(sinceDay string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Reset the range first so days whose events were removed drop back to zero
+	if _, err := tx.Exec(`
+		UPDATE user_daily_stats
+		SET impressions = 0, likes = 0, comments = 0, new_followers = 0, profile_visits = 0
+		WHERE day >= ?
+	`, sinceDay); err != nil {
+		return fmt.Errorf("failed to reset daily stats: %w", err)
+	}
+
+	for column, countQuery := range rollupMetrics {
+		query := fmt.Sprintf(`
+			INSERT INTO user_daily_stats (id, user_id, day, %[1]s, updated_at)
+			SELECT user_id || ':' || day, user_id, day, total, CURRENT_TIMESTAMP
+			FROM (%[2]s)
+			WHERE day IS NOT NULL
+			ON CONFLICT(id) DO UPDATE SET %[1]s = excluded.%[1]s, updated_at = excluded.updated_at
+		`, column, countQuery)
+
+		if _, err := tx.Exec(query, sinceDay); err != nil {
+			return fmt.Errorf("failed to refresh %s rollup: %w", column, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetDailyStats gets a user's rollups between two days inclusive, oldest first
+func (r *SQLiteRepository) GetDailyStats(userID, fromDay, toDay string) ([]*models.UserDailyStat, error) {
+	query := `
+		SELECT id, user_id, day, impressions, likes, comments, new_followers, profile_visits, updated_at
+		FROM user_daily_stats
+		WHERE user_id = ? AND day >= ? AND day <= ?
+		ORDER BY day ASC
+	`
+
+	rows, err := r.db.Query(query, userID, fromDay, toDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.UserDailyStat
+	for rows.Next() {
+		stat := &models.UserDailyStat{}
+		if err := rows.Scan(
+			&stat.ID,
+			&stat.UserID,
+			&stat.Day,
+			&stat.Impressions,
+			&stat.Likes,
+			&stat.Comments,
+			&stat.NewFollowers,
+			&stat.ProfileVisits,
+			&stat.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan daily stat: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
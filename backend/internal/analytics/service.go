@@ -0,0 +1,158 @@
+package analytics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+const (
+	dayFormat        = "2006-01-02"
+	defaultRangeDays = 30
+	maxRangeDays     = 366
+)
+
+// Totals sums each metric over an analytics range
+type Totals struct {
+	Impressions   int64 `json:"impressions"`
+	Likes         int64 `json:"likes"`
+	Comments      int64 `json:"comments"`
+	NewFollowers  int64 `json:"newFollowers"`
+	ProfileVisits int64 `json:"profileVisits"`
+}
+
+// Summary is a user's analytics over a range of days
+type Summary struct {
+	From   string                  `json:"from"`
+	To     string                  `json:"to"`
+	Totals Totals                  `json:"totals"`
+	Series []*models.UserDailyStat `json:"series"` // one entry per day, oldest first
+}
+
+// Service defines the analytics service interface
+type Service interface {
+	GetUserAnalytics(userID, from, to string) (*Summary, error)
+	RefreshRollups() error
+	RunRollups(interval time.Duration)
+}
+
+// AnalyticsService implements the Service interface
+type AnalyticsService struct {
+	repo Repository
+	log  *logger.Logger
+}
+
+// NewService creates a new analytics service
+func NewService(repo Repository, log *logger.Logger) Service {
+	return &AnalyticsService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// GetUserAnalytics gets the user's daily metrics between two days (YYYY-MM-DD,
+// inclusive, UTC). Without a range it covers the last 30 days; days without any
+// activity are filled with zeros.
+func (s *AnalyticsService) GetUserAnalytics(userID, from, to string) (*Summary, error) {
+	toDay := time.Now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		parsed, err := time.Parse(dayFormat, to)
+		if err != nil {
+			return nil, errors.New("invalid end date, expected YYYY-MM-DD")
+		}
+		toDay = parsed
+	}
+
+	fromDay := toDay.AddDate(0, 0, -(defaultRangeDays - 1))
+	if from != "" {
+		parsed, err := time.Parse(dayFormat, from)
+		if err != nil {
+			return nil, errors.New("invalid start date, expected YYYY-MM-DD")
+		}
+		fromDay = parsed
+	}
+
+	if fromDay.After(toDay) {
+		return nil, errors.New("start date must not be after end date")
+	}
+	if toDay.Sub(fromDay) >= maxRangeDays*24*time.Hour {
+		return nil, errors.New("date range is too long")
+	}
+
+	stats, err := s.repo.GetDailyStats(userID, fromDay.Format(dayFormat), toDay.Format(dayFormat))
+	if err != nil {
+		s.log.Error("Failed to get daily stats: %v", err)
+		return nil, err
+	}
+
+	byDay := make(map[string]*models.UserDailyStat, len(stats))
+	for _, stat := range stats {
+		byDay[stat.Day] = stat
+	}
+
+	summary := &Summary{
+		From:   fromDay.Format(dayFormat),
+		To:     toDay.Format(dayFormat),
+		Series: []*models.UserDailyStat{},
+	}
+
+	for day := fromDay; !day.After(toDay); day = day.AddDate(0, 0, 1) {
+		key := day.Format(dayFormat)
+		stat, ok := byDay[key]
+		if !ok {
+			stat = &models.UserDailyStat{UserID: userID, Day: key}
+		}
+
+		summary.Totals.Impressions += stat.Impressions
+		summary.Totals.Likes += stat.Likes
+		summary.Totals.Comments += stat.Comments
+		summary.Totals.NewFollowers += stat.NewFollowers
+		summary.Totals.ProfileVisits += stat.ProfileVisits
+		summary.Series = append(summary.Series, stat)
+	}
+
+	return summary, nil
+}
+
+// RefreshRollups recomputes the daily rollups from the day before the latest
+// rollup, so late events and removals near the boundary are picked up
+func (s *AnalyticsService) RefreshRollups() error {
+	latestDay, err := s.repo.GetLatestRollupDay()
+	if err != nil {
+		s.log.Error("Failed to get latest rollup day: %v", err)
+		return err
+	}
+
+	sinceDay := ""
+	if latestDay != "" {
+		latest, err := time.Parse(dayFormat, latestDay)
+		if err != nil {
+			s.log.Error("Invalid rollup day %s: %v", latestDay, err)
+			return err
+		}
+		sinceDay = latest.AddDate(0, 0, -1).Format(dayFormat)
+	}
+
+	if err := s.repo.RefreshDailyStats(sinceDay); err != nil {
+		s.log.Error("Failed to refresh daily stats: %v", err)
+		return err
+	}
+
+	s.log.Debug("Refreshed analytics rollups since %q", sinceDay)
+	return nil
+}
+
+// RunRollups refreshes the rollups immediately and then on every interval. It
+// blocks, so start it in its own goroutine.
+func (s *AnalyticsService) RunRollups(interval time.Duration) {
+	s.RefreshRollups()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.RefreshRollups()
+	}
+}
@@ -10,21 +10,28 @@ import (
 	"github.com/Athooh/social-network/pkg/user"
 )
 
+// HandleAssigner gives newly registered users a handle
+type HandleAssigner interface {
+	AssignHandle(userID string) error
+}
+
 // Service provides authentication functionality
 type Service struct {
 	userRepo       user.Repository
 	sessionManager *session.SessionManager
 	jwtConfig      JWTConfig
 	statusRepo     user.StatusRepository
+	handles        HandleAssigner
 }
 
 // NewService creates a new authentication service
-func NewService(userRepo user.Repository, sessionManager *session.SessionManager, jwtConfig JWTConfig, statusRepo user.StatusRepository) *Service {
+func NewService(userRepo user.Repository, sessionManager *session.SessionManager, jwtConfig JWTConfig, statusRepo user.StatusRepository, handles HandleAssigner) *Service {
 	return &Service{
 		userRepo:       userRepo,
 		sessionManager: sessionManager,
 		jwtConfig:      jwtConfig,
 		statusRepo:     statusRepo,
+		handles:        handles,
 	}
 }
 
@@ -59,6 +66,13 @@ func (s *Service) Register(req models.RegisterRequest) (*models.TokenResponse, e
 		return nil, err
 	}
 
+	// Give the user a handle derived from their nickname or name. The account
+	// already exists, so a failure here doesn't fail the registration; users
+	// left without a handle get one when handles are backfilled at startup.
+	if err := s.handles.AssignHandle(newUser.ID); err != nil {
+		logger.Error("Failed to assign handle to user %s: %v", newUser.ID, err)
+	}
+
 	// Generate JWT token
 	token, err := GenerateToken(newUser.ID, s.jwtConfig)
 	if err != nil {
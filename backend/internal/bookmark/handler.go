@@ -0,0 +1,280 @@
+package bookmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/internal/post"
+	"github.com/Athooh/social-network/pkg/httputil"
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Handler handles HTTP requests for bookmarks
+type Handler struct {
+	service Service
+	log     *logger.Logger
+}
+
+// NewHandler creates a new bookmark handler
+func NewHandler(service Service, log *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		log:     log,
+	}
+}
+
+// BookmarkResponse represents a saved post in API responses
+type BookmarkResponse struct {
+	ID           int64              `json:"id"`
+	PostID       int64              `json:"postId"`
+	CollectionID string             `json:"collectionId"`
+	CreatedAt    string             `json:"createdAt"`
+	Unavailable  bool               `json:"unavailable"`
+	Post         *post.PostResponse `json:"post,omitempty"`
+}
+
+// BookmarkPageResponse represents a page of saved posts
+type BookmarkPageResponse struct {
+	Items      []BookmarkResponse `json:"items"`
+	NextCursor int64              `json:"nextCursor"`
+}
+
+// HandleBookmarks handles listing, saving, moving and removing bookmarks
+func (h *Handler) HandleBookmarks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetBookmarks(w, r)
+	case http.MethodPost, http.MethodPut:
+		h.SaveBookmark(w, r)
+	case http.MethodDelete:
+		h.RemoveBookmark(w, r)
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// GetBookmarks handles listing the user's saved posts with cursor pagination
+func (h *Handler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	collectionID := r.URL.Query().Get("collectionId")
+
+	var cursor int64
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		parsedCursor, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || parsedCursor < 0 {
+			h.sendError(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		cursor = parsedCursor
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultPageSize
+	}
+
+	page, err := h.service.GetBookmarks(userID, collectionID, cursor, limit)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := BookmarkPageResponse{
+		Items:      make([]BookmarkResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, bookmark := range page.Items {
+		response.Items = append(response.Items, toBookmarkResponse(bookmark))
+	}
+
+	h.sendJSON(w, http.StatusOK, response)
+}
+
+// SaveBookmark handles saving a post, or moving a saved post to another collection
+func (h *Handler) SaveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		PostID       int64  `json:"postId"`
+		CollectionID string `json:"collectionId"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.PostID == 0 {
+		h.sendError(w, http.StatusBadRequest, "Post ID is required")
+		return
+	}
+
+	bookmark, err := h.service.SaveBookmark(userID, request.PostID, request.CollectionID)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := http.StatusCreated
+	if r.Method == http.MethodPut {
+		status = http.StatusOK
+	}
+	h.sendJSON(w, status, toBookmarkResponse(bookmark))
+}
+
+// RemoveBookmark handles removing a saved post
+func (h *Handler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	postID, err := strconv.ParseInt(r.URL.Query().Get("postId"), 10, 64)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid post ID")
+		return
+	}
+
+	if err := h.service.RemoveBookmark(userID, postID); err != nil {
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleCollections handles listing, creating, renaming and deleting collections
+func (h *Handler) HandleCollections(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		collections, err := h.service.GetCollections(userID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, collections)
+
+	case http.MethodPost:
+		var request struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		collection, err := h.service.CreateCollection(userID, request.Name)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusCreated, collection)
+
+	case http.MethodPut:
+		collectionID := r.URL.Query().Get("id")
+		if collectionID == "" {
+			h.sendError(w, http.StatusBadRequest, "Collection ID is required")
+			return
+		}
+
+		var request struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		collection, err := h.service.RenameCollection(collectionID, userID, request.Name)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, collection)
+
+	case http.MethodDelete:
+		collectionID := r.URL.Query().Get("id")
+		if collectionID == "" {
+			h.sendError(w, http.StatusBadRequest, "Collection ID is required")
+			return
+		}
+
+		if err := h.service.DeleteCollection(collectionID, userID); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// toBookmarkResponse converts a bookmark to its API representation
+func toBookmarkResponse(bookmark *models.Bookmark) BookmarkResponse {
+	response := BookmarkResponse{
+		ID:           bookmark.ID,
+		PostID:       bookmark.PostID,
+		CollectionID: bookmark.CollectionID,
+		CreatedAt:    bookmark.CreatedAt.Format(time.RFC3339),
+		Unavailable:  bookmark.Unavailable,
+	}
+
+	if bookmark.Post != nil {
+		postResponse := &post.PostResponse{
+			ID:         bookmark.Post.ID,
+			UserID:     bookmark.Post.UserID,
+			Content:    bookmark.Post.Content,
+			Privacy:    bookmark.Post.Privacy,
+			LikesCount: int(bookmark.Post.LikesCount),
+			ViewsCount: int(bookmark.Post.ViewsCount),
+			CreatedAt:  bookmark.Post.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:  bookmark.Post.UpdatedAt.Format(time.RFC3339),
+			UserData:   bookmark.Post.UserData,
+		}
+		if bookmark.Post.ImagePath.String != "" {
+			postResponse.ImageURL = "/uploads/" + bookmark.Post.ImagePath.String
+		}
+		if bookmark.Post.VideoPath.String != "" {
+			postResponse.VideoURL = "/uploads/" + bookmark.Post.VideoPath.String
+		}
+		response.Post = postResponse
+	}
+
+	return response
+}
+
+// Helper method to send JSON responses
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	httputil.SendJSON(w, status, data)
+}
+
+// Helper method to send error responses
+func (h *Handler) sendError(w http.ResponseWriter, status int, message string) {
+	var isWarning bool = false
+	if status >= 500 {
+		isWarning = true
+	}
+	httputil.SendError(w, status, message, isWarning)
+}
@@ -0,0 +1,270 @@
+package bookmark
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for bookmark data access
+type Repository interface {
+	// Collection operations
+	CreateCollection(collection *models.BookmarkCollection) error
+	GetCollectionByID(id string) (*models.BookmarkCollection, error)
+	GetCollectionsByUserID(userID string) ([]*models.BookmarkCollection, error)
+	UpdateCollection(collection *models.BookmarkCollection) error
+	DeleteCollection(id string) error
+
+	// Bookmark operations
+	SaveBookmark(bookmark *models.Bookmark) error
+	GetBookmark(userID string, postID int64) (*models.Bookmark, error)
+	GetBookmarks(userID, collectionID string, cursor int64, limit int) ([]*models.Bookmark, error)
+	DeleteBookmark(userID string, postID int64) error
+	DeleteBookmarkByID(id int64) error
+}
+
+// SQLiteRepository implements Repository interface for SQLite
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite repository
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// CreateCollection creates a new bookmark collection
+func (r *SQLiteRepository) CreateCollection(collection *models.BookmarkCollection) error {
+	if collection.ID == "" {
+		collection.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	collection.CreatedAt = now
+	collection.UpdatedAt = now
+
+	query := `
+		INSERT INTO bookmark_collections (id, user_id, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, collection.ID, collection.UserID, collection.Name, collection.CreatedAt, collection.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	return nil
+}
+
+// GetCollectionByID retrieves a bookmark collection by ID
+func (r *SQLiteRepository) GetCollectionByID(id string) (*models.BookmarkCollection, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id)
+		FROM bookmark_collections c
+		WHERE c.id = ?
+	`
+
+	collection := &models.BookmarkCollection{}
+	err := r.db.QueryRow(query, id).Scan(
+		&collection.ID,
+		&collection.UserID,
+		&collection.Name,
+		&collection.CreatedAt,
+		&collection.UpdatedAt,
+		&collection.ItemCount,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// GetCollectionsByUserID retrieves all bookmark collections of a user
+func (r *SQLiteRepository) GetCollectionsByUserID(userID string) ([]*models.BookmarkCollection, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM bookmarks b WHERE b.collection_id = c.id)
+		FROM bookmark_collections c
+		WHERE c.user_id = ?
+		ORDER BY c.name ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collections: %w", err)
+	}
+	defer rows.Close()
+
+	var collections []*models.BookmarkCollection
+	for rows.Next() {
+		collection := &models.BookmarkCollection{}
+		if err := rows.Scan(
+			&collection.ID,
+			&collection.UserID,
+			&collection.Name,
+			&collection.CreatedAt,
+			&collection.UpdatedAt,
+			&collection.ItemCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, collection)
+	}
+
+	return collections, rows.Err()
+}
+
+// UpdateCollection updates a bookmark collection's name
+func (r *SQLiteRepository) UpdateCollection(collection *models.BookmarkCollection) error {
+	collection.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(
+		"UPDATE bookmark_collections SET name = ?, updated_at = ? WHERE id = ?",
+		collection.Name, collection.UpdatedAt, collection.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCollection deletes a collection and moves its bookmarks out of it
+func (r *SQLiteRepository) DeleteCollection(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE bookmarks SET collection_id = '' WHERE collection_id = ?", id); err != nil {
+		return fmt.Errorf("failed to detach bookmarks: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM bookmark_collections WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SaveBookmark saves a post for a user, moving it to the given collection if
+// it was already saved
+func (r *SQLiteRepository) SaveBookmark(bookmark *models.Bookmark) error {
+	bookmark.UserPostKey = fmt.Sprintf("%s:%d", bookmark.UserID, bookmark.PostID)
+	bookmark.CreatedAt = time.Now()
+
+	// Saving the same post twice only moves it, even when both saves race
+	query := `
+		INSERT INTO bookmarks (user_post_key, user_id, post_id, collection_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_post_key) DO UPDATE SET collection_id = excluded.collection_id
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query, bookmark.UserPostKey, bookmark.UserID, bookmark.PostID, bookmark.CollectionID, bookmark.CreatedAt,
+	).Scan(&bookmark.ID, &bookmark.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// GetBookmark retrieves a user's bookmark for a post
+func (r *SQLiteRepository) GetBookmark(userID string, postID int64) (*models.Bookmark, error) {
+	query := `
+		SELECT id, user_id, post_id, collection_id, created_at
+		FROM bookmarks
+		WHERE user_id = ? AND post_id = ?
+	`
+
+	bookmark := &models.Bookmark{}
+	err := r.db.QueryRow(query, userID, postID).Scan(
+		&bookmark.ID,
+		&bookmark.UserID,
+		&bookmark.PostID,
+		&bookmark.CollectionID,
+		&bookmark.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bookmark: %w", err)
+	}
+
+	return bookmark, nil
+}
+
+// GetBookmarks retrieves a page of a user's bookmarks, newest first. Only
+// bookmarks with an ID lower than cursor are returned; a cursor of 0 starts
+// from the newest. An empty collectionID returns bookmarks from all collections.
+func (r *SQLiteRepository) GetBookmarks(userID, collectionID string, cursor int64, limit int) ([]*models.Bookmark, error) {
+	query := `
+		SELECT id, user_id, post_id, collection_id, created_at
+		FROM bookmarks
+		WHERE user_id = ?
+			AND (? = '' OR collection_id = ?)
+			AND (? = 0 OR id < ?)
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, userID, collectionID, collectionID, cursor, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	var bookmarks []*models.Bookmark
+	for rows.Next() {
+		bookmark := &models.Bookmark{}
+		if err := rows.Scan(
+			&bookmark.ID,
+			&bookmark.UserID,
+			&bookmark.PostID,
+			&bookmark.CollectionID,
+			&bookmark.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bookmark: %w", err)
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	return bookmarks, rows.Err()
+}
+
+// DeleteBookmark removes a user's bookmark for a post
+func (r *SQLiteRepository) DeleteBookmark(userID string, postID int64) error {
+	_, err := r.db.Exec("DELETE FROM bookmarks WHERE user_id = ? AND post_id = ?", userID, postID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBookmarkByID removes a bookmark by ID
+func (r *SQLiteRepository) DeleteBookmarkByID(id int64) error {
+	_, err := r.db.Exec("DELETE FROM bookmarks WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package bookmark
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Athooh/social-network/internal/post"
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+const (
+	defaultPageSize         = 20
+	maxPageSize             = 50
+	maxCollectionNameLength = 50
+)
+
+// Page is a cursor-paginated list of bookmarks
+type Page struct {
+	Items      []*models.Bookmark `json:"items"`
+	NextCursor int64              `json:"nextCursor"` // 0 when there are no more items
+}
+
+// Service defines the bookmark service interface
+type Service interface {
+	// Collections
+	CreateCollection(userID, name string) (*models.BookmarkCollection, error)
+	GetCollections(userID string) ([]*models.BookmarkCollection, error)
+	RenameCollection(collectionID, userID, name string) (*models.BookmarkCollection, error)
+	DeleteCollection(collectionID, userID string) error
+
+	// Bookmarks
+	SaveBookmark(userID string, postID int64, collectionID string) (*models.Bookmark, error)
+	RemoveBookmark(userID string, postID int64) error
+	GetBookmarks(userID, collectionID string, cursor int64, limit int) (*Page, error)
+	IsBookmarked(userID string, postID int64) (bool, error)
+}
+
+// BookmarkService implements the Service interface
+type BookmarkService struct {
+	repo     Repository
+	postRepo post.Repository
+	log      *logger.Logger
+}
+
+// NewService creates a new bookmark service
+func NewService(repo Repository, postRepo post.Repository, log *logger.Logger) Service {
+	return &BookmarkService{
+		repo:     repo,
+		postRepo: postRepo,
+		log:      log,
+	}
+}
+
+// CreateCollection creates a new private collection for the user
+func (s *BookmarkService) CreateCollection(userID, name string) (*models.BookmarkCollection, error) {
+	name, err := validateCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.BookmarkCollection{
+		UserID: userID,
+		Name:   name,
+	}
+
+	if err := s.repo.CreateCollection(collection); err != nil {
+		s.log.Error("Failed to create bookmark collection: %v", err)
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// GetCollections gets all of the user's collections
+func (s *BookmarkService) GetCollections(userID string) ([]*models.BookmarkCollection, error) {
+	collections, err := s.repo.GetCollectionsByUserID(userID)
+	if err != nil {
+		s.log.Error("Failed to get bookmark collections: %v", err)
+		return nil, err
+	}
+
+	return collections, nil
+}
+
+// RenameCollection renames one of the user's collections
+func (s *BookmarkService) RenameCollection(collectionID, userID, name string) (*models.BookmarkCollection, error) {
+	collection, err := s.getOwnedCollection(collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err = validateCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.Name = name
+	if err := s.repo.UpdateCollection(collection); err != nil {
+		s.log.Error("Failed to rename bookmark collection: %v", err)
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// DeleteCollection deletes one of the user's collections. Its bookmarks stay
+// saved but no longer belong to a collection.
+func (s *BookmarkService) DeleteCollection(collectionID, userID string) error {
+	if _, err := s.getOwnedCollection(collectionID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteCollection(collectionID); err != nil {
+		s.log.Error("Failed to delete bookmark collection: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// SaveBookmark saves a post or group post the user can view, optionally into
+// one of their collections. Group posts can only be saved by members of the
+// group once they're approved. Saving an already saved post moves it.
+func (s *BookmarkService) SaveBookmark(userID string, postID int64, collectionID string) (*models.Bookmark, error) {
+	target, err := s.postRepo.GetPostByID(postID)
+	if err != nil {
+		s.log.Error("Failed to get post for bookmark: %v", err)
+		return nil, err
+	}
+	if target == nil {
+		return nil, errors.New("post not found")
+	}
+
+	canView, err := s.postRepo.CanViewPost(postID, userID)
+	if err != nil {
+		s.log.Error("Failed to check post view permission: %v", err)
+		return nil, err
+	}
+	if !canView {
+		// Don't give away that a private group's post exists
+		if target.GroupID != "" {
+			return nil, errors.New("post not found")
+		}
+		return nil, errors.New("you don't have permission to view this post")
+	}
+
+	if collectionID != "" {
+		if _, err := s.getOwnedCollection(collectionID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	bookmark := &models.Bookmark{
+		UserID:       userID,
+		PostID:       postID,
+		CollectionID: collectionID,
+	}
+
+	if err := s.repo.SaveBookmark(bookmark); err != nil {
+		s.log.Error("Failed to save bookmark: %v", err)
+		return nil, err
+	}
+
+	bookmark.Post = target
+	return bookmark, nil
+}
+
+// RemoveBookmark removes a saved post
+func (s *BookmarkService) RemoveBookmark(userID string, postID int64) error {
+	if err := s.repo.DeleteBookmark(userID, postID); err != nil {
+		s.log.Error("Failed to remove bookmark: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetBookmarks lists the user's saved posts newest first. Visibility is
+// re-checked for every item: bookmarks of deleted posts are removed, and posts
+// the user can no longer view (or that moderation has hidden) are returned as
+// tombstones without content. That includes group posts once the user leaves
+// or is banned from the group, and posts that go back to waiting for review.
+func (s *BookmarkService) GetBookmarks(userID, collectionID string, cursor int64, limit int) (*Page, error) {
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	if collectionID != "" {
+		if _, err := s.getOwnedCollection(collectionID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &Page{Items: []*models.Bookmark{}}
+
+	// Keep fetching until the page is full, since removed bookmarks leave gaps
+	for len(page.Items) < limit {
+		bookmarks, err := s.repo.GetBookmarks(userID, collectionID, cursor, limit-len(page.Items))
+		if err != nil {
+			s.log.Error("Failed to get bookmarks: %v", err)
+			return nil, err
+		}
+
+		for _, bookmark := range bookmarks {
+			cursor = bookmark.ID
+
+			target, err := s.postRepo.GetPostByID(bookmark.PostID)
+			if err != nil {
+				s.log.Error("Failed to get bookmarked post: %v", err)
+				return nil, err
+			}
+
+			if target == nil {
+				if err := s.repo.DeleteBookmarkByID(bookmark.ID); err != nil {
+					s.log.Warn("Failed to remove bookmark of deleted post %d: %v", bookmark.PostID, err)
+				}
+				continue
+			}
+
+			canView, err := s.postRepo.CanViewPost(bookmark.PostID, userID)
+			if err != nil {
+				s.log.Error("Failed to check post view permission: %v", err)
+				return nil, err
+			}
+
+			if canView && !target.IsHidden {
+				userData, err := s.postRepo.GetUserDataByID(target.UserID)
+				if err != nil {
+					s.log.Warn("Failed to get user data for post %d: %v", target.ID, err)
+				}
+				target.UserData = userData
+				bookmark.Post = target
+			} else {
+				bookmark.Unavailable = true
+			}
+
+			page.Items = append(page.Items, bookmark)
+		}
+
+		if len(bookmarks) == 0 || len(page.Items) >= limit {
+			if len(bookmarks) > 0 {
+				page.NextCursor = cursor
+			}
+			break
+		}
+	}
+
+	return page, nil
+}
+
+// IsBookmarked checks whether the user has saved a post
+func (s *BookmarkService) IsBookmarked(userID string, postID int64) (bool, error) {
+	bookmark, err := s.repo.GetBookmark(userID, postID)
+	if err != nil {
+		s.log.Error("Failed to get bookmark: %v", err)
+		return false, err
+	}
+
+	return bookmark != nil, nil
+}
+
+// getOwnedCollection loads a collection and checks it belongs to the user
+func (s *BookmarkService) getOwnedCollection(collectionID, userID string) (*models.BookmarkCollection, error) {
+	collection, err := s.repo.GetCollectionByID(collectionID)
+	if err != nil {
+		s.log.Error("Failed to get bookmark collection: %v", err)
+		return nil, err
+	}
+
+	// Collections are private, so report other users' collections as missing
+	if collection == nil || collection.UserID != userID {
+		return nil, errors.New("collection not found")
+	}
+
+	return collection, nil
+}
+
+// validateCollectionName trims and validates a collection name
+func validateCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("collection name is required")
+	}
+	if len(name) > maxCollectionNameLength {
+		return "", errors.New("collection name is too long")
+	}
+
+	return name, nil
+}
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"database/sql"
+	"time"
 
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
 )
@@ -17,6 +18,7 @@ type Repository interface {
 	// Contact operations
 	GetChatContacts(userID string) ([]*models.ChatContact, error)
 	CanSendMessage(senderID, receiverID string) (bool, error)
+	CanViewConversation(userID, otherUserID string) (bool, error)
 	GetUserBasicByID(userID string) (*models.UserBasic, error)
 
 	// Search operations
@@ -58,7 +60,8 @@ func (r *SQLiteRepository) GetMessagesBetweenUsers(userID1, userID2 string, limi
 	query := `
 		SELECT id, sender_id, receiver_id, content, created_at, read_at, is_read
 		FROM private_messages
-		WHERE (sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?)
+		WHERE ((sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?))
+			AND is_hidden = 0
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`
@@ -209,11 +212,21 @@ func (r *SQLiteRepository) GetChatContacts(userID string) ([]*models.ChatContact
 			u.first_name,
 			u.last_name,
 			u.avatar,
-			COALESCE(us.is_online, 0) as is_online,
+			COALESCE(us.is_online AND us.presence != 'invisible' AND CASE COALESCE(
+				(SELECT ps.who_can_see_online FROM privacy_settings ps WHERE ps.user_id = u.id), 'everyone')
+				WHEN 'everyone' THEN 1
+				WHEN 'followers' THEN EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = ? AND x.following_id = u.id)
+				WHEN 'mutuals' THEN EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = ? AND x.following_id = u.id)
+					AND EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = u.id AND x.following_id = ?)
+				ELSE 0
+				END, 0) as is_online,
+			COALESCE(cs.status_text, '') as status_text,
+			COALESCE(cs.status_emoji, '') as status_emoji,
 			(
 				SELECT content
 				FROM private_messages
-				WHERE (sender_id = ? AND receiver_id = u.id) OR (sender_id = u.id AND receiver_id = ?)
+				WHERE ((sender_id = ? AND receiver_id = u.id) OR (sender_id = u.id AND receiver_id = ?))
+					AND is_hidden = 0
 				ORDER BY created_at DESC
 				LIMIT 1
 			) as last_message,
@@ -239,6 +252,7 @@ func (r *SQLiteRepository) GetChatContacts(userID string) ([]*models.ChatContact
 		FROM chat_users cu
 		JOIN users u ON cu.contact_id = u.id
 		LEFT JOIN user_status us ON u.id = us.user_id
+		LEFT JOIN user_status cs ON u.id = cs.user_id AND (cs.status_expires_at IS NULL OR cs.status_expires_at > ?)
 		ORDER BY last_sent DESC NULLS LAST
 	`
 
@@ -247,10 +261,12 @@ func (r *SQLiteRepository) GetChatContacts(userID string) ([]*models.ChatContact
 		userID, userID, userID, // for private_messages
 		userID, userID, userID, // for followers union
 		userID, userID, // for follow-check condition
+		userID, userID, userID, // for online status visibility
 		userID, userID, // for last_message
 		userID, userID, // for last_message_sender_id
 		userID, userID, // for last_sent
-		userID, // for unread_count
+		userID,           // for unread_count
+		time.Now().UTC(), // for unexpired custom statuses
 	)
 	if err != nil {
 		return nil, err
@@ -270,6 +286,8 @@ func (r *SQLiteRepository) GetChatContacts(userID string) ([]*models.ChatContact
 			&contact.LastName,
 			&contact.Avatar,
 			&contact.IsOnline,
+			&contact.StatusText,
+			&contact.StatusEmoji,
 			&lastMessage,
 			&lastMessageSenderID,
 			&lastSent,
@@ -297,22 +315,43 @@ func (r *SQLiteRepository) GetChatContacts(userID string) ([]*models.ChatContact
 
 // CanSendMessage checks if a user can send a message to another user
 func (r *SQLiteRepository) CanSendMessage(senderID, receiverID string) (bool, error) {
-	// Check if the sender follows the receiver or vice versa
+	// Check if the sender follows the receiver or vice versa, and that the
+	// receiver's privacy settings accept messages from the sender
 	query := `
-		SELECT EXISTS (
+		SELECT (EXISTS (
 			SELECT 1 FROM followers 
-			WHERE (follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)
+			WHERE (follower_id = ?1 AND following_id = ?2) OR (follower_id = ?2 AND following_id = ?1)
 		) OR EXISTS (
 			SELECT 1 FROM users
-			WHERE id = ? AND is_public = 1
+			WHERE id = ?2 AND is_public = 1
+		)) AND (
+			CASE COALESCE((SELECT who_can_message FROM privacy_settings WHERE user_id = ?2), ?3)
+			WHEN ?3 THEN 1
+			WHEN ?4 THEN EXISTS (SELECT 1 FROM followers WHERE follower_id = ?1 AND following_id = ?2)
+			WHEN ?5 THEN EXISTS (SELECT 1 FROM followers WHERE follower_id = ?1 AND following_id = ?2)
+				AND EXISTS (SELECT 1 FROM followers WHERE follower_id = ?2 AND following_id = ?1)
+			ELSE 0
+			END
 		)
 	`
 
 	var canSend bool
-	err := r.db.QueryRow(query, senderID, receiverID, receiverID, senderID, receiverID).Scan(&canSend)
+	err := r.db.QueryRow(query, senderID, receiverID,
+		models.AudienceEveryone, models.AudienceFollowers, models.AudienceMutuals,
+	).Scan(&canSend)
 	return canSend, err
 }
 
+// CanViewConversation checks if a user can read their conversation with
+// another user: either of them must be able to message the other
+func (r *SQLiteRepository) CanViewConversation(userID, otherUserID string) (bool, error) {
+	canSend, err := r.CanSendMessage(userID, otherUserID)
+	if err != nil || canSend {
+		return canSend, err
+	}
+	return r.CanSendMessage(otherUserID, userID)
+}
+
 // GetUserBasicByID gets basic user information by ID
 func (r *SQLiteRepository) GetUserBasicByID(userID string) (*models.UserBasic, error) {
 	query := `
@@ -347,7 +386,7 @@ func (r *SQLiteRepository) SearchMessages(userID, query, otherUserID string, lim
 			SELECT id, sender_id, receiver_id, content, created_at, read_at, is_read
 			FROM private_messages
 			WHERE ((sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?))
-			AND content LIKE ?
+			AND content LIKE ? AND is_hidden = 0
 			ORDER BY created_at DESC
 			LIMIT ?
 		`
@@ -358,7 +397,7 @@ func (r *SQLiteRepository) SearchMessages(userID, query, otherUserID string, lim
 			SELECT id, sender_id, receiver_id, content, created_at, read_at, is_read
 			FROM private_messages
 			WHERE (sender_id = ? OR receiver_id = ?)
-			AND content LIKE ?
+			AND content LIKE ? AND is_hidden = 0
 			ORDER BY created_at DESC
 			LIMIT ?
 		`
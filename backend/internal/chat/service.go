@@ -2,8 +2,10 @@ package chat
 
 import (
 	"errors"
+	"strconv"
 	"time"
 
+	"github.com/Athooh/social-network/internal/contentfilter"
 	"github.com/Athooh/social-network/pkg/logger"
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
 	"github.com/Athooh/social-network/pkg/websocket"
@@ -31,16 +33,18 @@ type ChatService struct {
 	repo            Repository
 	log             *logger.Logger
 	notificationSvc *NotificationService
+	contentFilter   *contentfilter.Pipeline
 }
 
 // NewService creates a new chat service
-func NewService(repo Repository, log *logger.Logger, wsHub *websocket.Hub) Service {
+func NewService(repo Repository, log *logger.Logger, wsHub *websocket.Hub, contentFilter *contentfilter.Pipeline) Service {
 	notificationSvc := NewNotificationService(wsHub)
 
 	return &ChatService{
 		repo:            repo,
 		log:             log,
 		notificationSvc: notificationSvc,
+		contentFilter:   contentFilter,
 	}
 }
 
@@ -56,6 +60,13 @@ func (s *ChatService) SendMessage(senderID, receiverID, content string) (*models
 		return nil, errors.New("you cannot send messages to this user")
 	}
 
+	// Run the text through the content filters before saving anything
+	filterContent := contentfilter.Content{UserID: senderID, Kind: contentfilter.KindMessage, Text: content}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
 	// Create the message
 	message := &models.PrivateMessage{
 		SenderID:   senderID,
@@ -70,6 +81,8 @@ func (s *ChatService) SendMessage(senderID, receiverID, content string) (*models
 		return nil, err
 	}
 
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(message.ID, 10))
+
 	// Get sender and receiver info for the response
 	sender, err := s.repo.GetUserBasicByID(senderID)
 	if err == nil {
@@ -90,7 +103,7 @@ func (s *ChatService) SendMessage(senderID, receiverID, content string) (*models
 // GetMessages gets messages between two users with pagination
 func (s *ChatService) GetMessages(userID1, userID2 string, limit, offset int) ([]*models.PrivateMessage, error) {
 	// Check if users can view messages
-	canSend, err := s.repo.CanSendMessage(userID1, userID2)
+	canSend, err := s.repo.CanViewConversation(userID1, userID2)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +124,7 @@ func (s *ChatService) GetMessages(userID1, userID2 string, limit, offset int) ([
 // MarkAsRead marks messages from a sender to a receiver as read
 func (s *ChatService) MarkAsRead(senderID, receiverID string) error {
 	// Check if users can view messages
-	canSend, err := s.repo.CanSendMessage(receiverID, senderID)
+	canSend, err := s.repo.CanViewConversation(receiverID, senderID)
 	if err != nil {
 		return err
 	}
@@ -3,16 +3,23 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	Auth      AuthConfig
-	Log       LogConfig
-	FileStore FileStoreConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Auth          AuthConfig
+	Log           LogConfig
+	FileStore     FileStoreConfig
+	Moderation    ModerationConfig
+	ContentFilter ContentFilterConfig
+	Analytics     AnalyticsConfig
+	Follow        FollowConfig
+	Presence      PresenceConfig
+	Group         GroupConfig
 }
 
 // ServerConfig holds the server configuration
@@ -35,6 +42,50 @@ type FileStoreConfig struct {
 	UploadDir string
 }
 
+// ModerationConfig holds the content moderation configuration
+type ModerationConfig struct {
+	AutoHideThreshold int // open reports needed to auto-hide content, 0 disables
+}
+
+// ContentFilterConfig holds the user-generated text filtering configuration
+type ContentFilterConfig struct {
+	Enabled         bool
+	BlockedWords    []string // case-insensitive whole words
+	BlockedPattern  string   // regular expression, use alternation for several patterns
+	BlocklistAction string   // "reject" or "flag"
+	MaxLinks        int      // 0 disables the link limit
+	MaxLength       int      // in characters, 0 disables the length limit
+	SpamRepeatLimit int      // identical texts allowed per user within SpamWindow, 0 disables
+	SpamWindow      time.Duration
+}
+
+// AnalyticsConfig holds the impression tracking and analytics rollup configuration
+type AnalyticsConfig struct {
+	ImpressionWindow time.Duration // a viewer counts once per post within this window
+	FlushInterval    time.Duration // how often buffered impressions are written
+	RollupInterval   time.Duration // how often the daily rollups are refreshed
+}
+
+// FollowConfig holds the follow request configuration
+type FollowConfig struct {
+	RequestExpiry        time.Duration // pending requests older than this expire, 0 disables
+	RequestSweepInterval time.Duration // how often expired requests are swept
+}
+
+// PresenceConfig holds the user presence configuration
+type PresenceConfig struct {
+	IdleAfter           time.Duration // online users without activity for this long are idle
+	IdleSweepInterval   time.Duration // how often users are checked for going idle
+	UpdateDebounce      time.Duration // presence changes wait this long before being broadcast
+	StatusSweepInterval time.Duration // how often expired custom statuses are cleared
+}
+
+// GroupConfig holds the group lifecycle configuration
+type GroupConfig struct {
+	ArchiveRetention   time.Duration // deleted groups can be restored for this long before they're purged
+	PurgeSweepInterval time.Duration // how often archived groups are checked for purging
+}
+
 // AuthConfig holds the authentication configuration
 type AuthConfig struct {
 	SessionCookieName   string
@@ -79,6 +130,38 @@ func Load() Config {
 			JWTSecretKey:        getEnv("JWT_SECRET_KEY", "your-secret-key-change-in-production"),
 			JWTTokenDuration:    getEnvAsInt("JWT_TOKEN_DURATION", 86400), // 24 hours
 		},
+		Moderation: ModerationConfig{
+			AutoHideThreshold: getEnvAsInt("MODERATION_AUTO_HIDE_THRESHOLD", 5),
+		},
+		ContentFilter: ContentFilterConfig{
+			Enabled:         getEnvAsBool("CONTENT_FILTER_ENABLED", true),
+			BlockedWords:    getEnvAsSlice("CONTENT_FILTER_BLOCKED_WORDS", nil),
+			BlockedPattern:  getEnv("CONTENT_FILTER_BLOCKED_PATTERN", ""),
+			BlocklistAction: getEnv("CONTENT_FILTER_BLOCKLIST_ACTION", "reject"),
+			MaxLinks:        getEnvAsInt("CONTENT_FILTER_MAX_LINKS", 5),
+			MaxLength:       getEnvAsInt("CONTENT_FILTER_MAX_LENGTH", 5000),
+			SpamRepeatLimit: getEnvAsInt("CONTENT_FILTER_SPAM_REPEAT_LIMIT", 3),
+			SpamWindow:      getEnvAsDuration("CONTENT_FILTER_SPAM_WINDOW", 5*time.Minute),
+		},
+		Analytics: AnalyticsConfig{
+			ImpressionWindow: getEnvAsDuration("ANALYTICS_IMPRESSION_WINDOW", time.Hour),
+			FlushInterval:    getEnvAsDuration("ANALYTICS_FLUSH_INTERVAL", 10*time.Second),
+			RollupInterval:   getEnvAsDuration("ANALYTICS_ROLLUP_INTERVAL", 15*time.Minute),
+		},
+		Follow: FollowConfig{
+			RequestExpiry:        getEnvAsDuration("FOLLOW_REQUEST_EXPIRY", 30*24*time.Hour),
+			RequestSweepInterval: getEnvAsDuration("FOLLOW_REQUEST_SWEEP_INTERVAL", time.Hour),
+		},
+		Presence: PresenceConfig{
+			IdleAfter:           getEnvAsDuration("PRESENCE_IDLE_AFTER", 5*time.Minute),
+			IdleSweepInterval:   getEnvAsDuration("PRESENCE_IDLE_SWEEP_INTERVAL", time.Minute),
+			UpdateDebounce:      getEnvAsDuration("PRESENCE_UPDATE_DEBOUNCE", 3*time.Second),
+			StatusSweepInterval: getEnvAsDuration("PRESENCE_STATUS_SWEEP_INTERVAL", time.Minute),
+		},
+		Group: GroupConfig{
+			ArchiveRetention:   getEnvAsDuration("GROUP_ARCHIVE_RETENTION", 30*24*time.Hour),
+			PurgeSweepInterval: getEnvAsDuration("GROUP_PURGE_SWEEP_INTERVAL", time.Hour),
+		},
 		Log: LogConfig{
 			Level:      getEnv("LOG_LEVEL", "info"),
 			TimeFormat: getEnv("LOG_TIME_FORMAT", "2006-01-02 15:04:05"),
@@ -126,3 +209,17 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getEnvAsSlice gets a comma-separated environment variable as a slice or returns a default value
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	if value, exists := os.LookupEnv(key); exists {
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		return values
+	}
+	return defaultValue
+}
@@ -0,0 +1,189 @@
+package contentfilter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Athooh/social-network/internal/config"
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Action is the outcome of a content filter check
+type Action string
+
+// Filter actions, ordered from least to most severe
+const (
+	ActionAllow  Action = "allow"
+	ActionFlag   Action = "flag"
+	ActionReject Action = "reject"
+)
+
+// Content kinds checked by the pipeline. Kinds that can be reported reuse the
+// report target types so flagged content can be queued for moderation.
+const (
+	KindPost         = models.ReportTargetPost
+	KindComment      = models.ReportTargetComment
+	KindGroupPost    = models.ReportTargetGroupPost
+	KindMessage      = models.ReportTargetMessage
+	KindGroupMessage = models.ReportTargetGroupMessage
+)
+
+// Content is a piece of user-generated text about to be saved
+type Content struct {
+	UserID string
+	Kind   string
+	Text   string
+	IsEdit bool // the new text of something already saved
+}
+
+// Decision is the result of running one filter, or the whole pipeline
+type Decision struct {
+	Action Action
+	Filter string
+	Reason string
+}
+
+// Allowed reports whether the content may be saved
+func (d Decision) Allowed() bool {
+	return d.Action != ActionReject
+}
+
+// Flagged reports whether the content should be queued for moderation
+func (d Decision) Flagged() bool {
+	return d.Action == ActionFlag
+}
+
+// Err returns the user-facing error for a rejected decision
+func (d Decision) Err() error {
+	return fmt.Errorf("content rejected: %s", d.Reason)
+}
+
+// Allow returns a decision that lets content through
+func Allow() Decision {
+	return Decision{Action: ActionAllow}
+}
+
+// Reject returns a decision that blocks content with a user-facing reason
+func Reject(reason string) Decision {
+	return Decision{Action: ActionReject, Reason: reason}
+}
+
+// Flag returns a decision that saves content but queues it for moderation
+func Flag(reason string) Decision {
+	return Decision{Action: ActionFlag, Reason: reason}
+}
+
+// ContentFilter inspects user-generated text before it is persisted
+type ContentFilter interface {
+	Name() string
+	Check(content Content) Decision
+}
+
+// Flagger queues flagged content for moderation once it has been saved
+type Flagger interface {
+	FlagContent(targetType, targetID, reason string) error
+}
+
+// Pipeline runs content through a chain of filters. A nil Pipeline allows everything.
+type Pipeline struct {
+	filters []ContentFilter
+	flagger Flagger
+	log     *logger.Logger
+}
+
+// NewPipeline creates a pipeline with the given filters
+func NewPipeline(log *logger.Logger, filters ...ContentFilter) *Pipeline {
+	return &Pipeline{
+		filters: filters,
+		log:     log,
+	}
+}
+
+// NewPipelineFromConfig creates a pipeline with the built-in filters enabled by the configuration
+func NewPipelineFromConfig(cfg config.ContentFilterConfig, log *logger.Logger) (*Pipeline, error) {
+	if !cfg.Enabled {
+		return NewPipeline(log), nil
+	}
+
+	var filters []ContentFilter
+
+	if cfg.MaxLength > 0 {
+		filters = append(filters, NewMaxLengthFilter(cfg.MaxLength))
+	}
+
+	if cfg.MaxLinks > 0 {
+		filters = append(filters, NewLinkLimitFilter(cfg.MaxLinks))
+	}
+
+	if len(cfg.BlockedWords) > 0 || cfg.BlockedPattern != "" {
+		blocklist, err := NewBlocklistFilter(cfg.BlockedWords, cfg.BlockedPattern, Action(cfg.BlocklistAction))
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, blocklist)
+	}
+
+	// Spam detection runs last so rejected content is not counted as a repeat
+	if cfg.SpamRepeatLimit > 0 && cfg.SpamWindow > 0 {
+		filters = append(filters, NewSpamFilter(cfg.SpamRepeatLimit, cfg.SpamWindow))
+	}
+
+	return NewPipeline(log, filters...), nil
+}
+
+// SetFlagger sets where flagged content is sent for moderation
+func (p *Pipeline) SetFlagger(flagger Flagger) {
+	p.flagger = flagger
+}
+
+// Check runs the content through every filter. The first rejection stops the
+// pipeline; flags from several filters are combined into one decision.
+func (p *Pipeline) Check(content Content) Decision {
+	if p == nil {
+		return Allow()
+	}
+
+	decision := Allow()
+	var flagReasons []string
+
+	for _, filter := range p.filters {
+		result := filter.Check(content)
+		result.Filter = filter.Name()
+
+		switch result.Action {
+		case ActionReject:
+			p.log.Warn("Content filter %s rejected %s from user %s: %s", result.Filter, content.Kind, content.UserID, result.Reason)
+			return result
+		case ActionFlag:
+			p.log.Info("Content filter %s flagged %s from user %s: %s", result.Filter, content.Kind, content.UserID, result.Reason)
+			flagReasons = append(flagReasons, result.Reason)
+			decision.Action = ActionFlag
+			decision.Filter = result.Filter
+		}
+	}
+
+	if decision.Flagged() {
+		decision.Reason = strings.Join(flagReasons, "; ")
+	} else {
+		p.log.Debug("Content filters allowed %s from user %s", content.Kind, content.UserID)
+	}
+
+	return decision
+}
+
+// ReportFlagged queues saved content for moderation when its decision flagged it
+func (p *Pipeline) ReportFlagged(decision Decision, content Content, targetID string) {
+	if p == nil || !decision.Flagged() {
+		return
+	}
+
+	if p.flagger == nil {
+		p.log.Warn("No flagger configured, %s %s flagged but not queued for moderation", content.Kind, targetID)
+		return
+	}
+
+	if err := p.flagger.FlagContent(content.Kind, targetID, decision.Reason); err != nil {
+		p.log.Error("Failed to queue flagged %s %s for moderation: %v", content.Kind, targetID, err)
+	}
+}
@@ -0,0 +1,194 @@
+package contentfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxLengthFilter rejects text longer than a number of characters
+type MaxLengthFilter struct {
+	maxLength int
+}
+
+// NewMaxLengthFilter creates a new max length filter
+func NewMaxLengthFilter(maxLength int) *MaxLengthFilter {
+	return &MaxLengthFilter{maxLength: maxLength}
+}
+
+// Name returns the filter name
+func (f *MaxLengthFilter) Name() string {
+	return "max_length"
+}
+
+// Check rejects the content if it is too long
+func (f *MaxLengthFilter) Check(content Content) Decision {
+	if utf8.RuneCountInString(content.Text) > f.maxLength {
+		return Reject(fmt.Sprintf("text is longer than %d characters", f.maxLength))
+	}
+	return Allow()
+}
+
+// linkPattern matches web links in text
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkLimitFilter rejects text containing too many links
+type LinkLimitFilter struct {
+	maxLinks int
+}
+
+// NewLinkLimitFilter creates a new link limit filter
+func NewLinkLimitFilter(maxLinks int) *LinkLimitFilter {
+	return &LinkLimitFilter{maxLinks: maxLinks}
+}
+
+// Name returns the filter name
+func (f *LinkLimitFilter) Name() string {
+	return "link_limit"
+}
+
+// Check rejects the content if it has more links than allowed
+func (f *LinkLimitFilter) Check(content Content) Decision {
+	if len(linkPattern.FindAllStringIndex(content.Text, f.maxLinks+1)) > f.maxLinks {
+		return Reject(fmt.Sprintf("text contains more than %d links", f.maxLinks))
+	}
+	return Allow()
+}
+
+// BlocklistFilter rejects or flags text containing blocked words or matching a pattern
+type BlocklistFilter struct {
+	words   *regexp.Regexp
+	pattern *regexp.Regexp
+	action  Action
+}
+
+// NewBlocklistFilter creates a new blocklist filter. Words match whole words
+// case-insensitively; pattern is a regular expression. action must be
+// ActionReject or ActionFlag.
+func NewBlocklistFilter(words []string, pattern string, action Action) (*BlocklistFilter, error) {
+	if action != ActionReject && action != ActionFlag {
+		return nil, fmt.Errorf("invalid blocklist action: %s", action)
+	}
+
+	filter := &BlocklistFilter{action: action}
+
+	if len(words) > 0 {
+		quoted := make([]string, 0, len(words))
+		for _, word := range words {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+		filter.words = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	}
+
+	if pattern != "" {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist pattern: %w", err)
+		}
+		filter.pattern = compiled
+	}
+
+	return filter, nil
+}
+
+// Name returns the filter name
+func (f *BlocklistFilter) Name() string {
+	return "blocklist"
+}
+
+// Check rejects or flags the content if it contains blocked terms
+func (f *BlocklistFilter) Check(content Content) Decision {
+	if (f.words != nil && f.words.MatchString(content.Text)) ||
+		(f.pattern != nil && f.pattern.MatchString(content.Text)) {
+		reason := "text contains blocked terms"
+		if f.action == ActionFlag {
+			return Flag(reason)
+		}
+		return Reject(reason)
+	}
+	return Allow()
+}
+
+// spamEntry records when a user posted a text
+type spamEntry struct {
+	text     string
+	postedAt time.Time
+}
+
+// SpamFilter rejects a user repeating the same text too often within a time window
+type SpamFilter struct {
+	repeatLimit int
+	window      time.Duration
+	history     map[string][]spamEntry
+	lastSweep   time.Time // when users with nothing in the window were last evicted
+	mu          sync.Mutex
+}
+
+// NewSpamFilter creates a new repeated-message spam filter
+func NewSpamFilter(repeatLimit int, window time.Duration) *SpamFilter {
+	return &SpamFilter{
+		repeatLimit: repeatLimit,
+		window:      window,
+		history:     make(map[string][]spamEntry),
+	}
+}
+
+// Name returns the filter name
+func (f *SpamFilter) Name() string {
+	return "spam"
+}
+
+// Check rejects the content if the user already sent the same text repeatLimit
+// times within the window. Allowed texts are recorded for later checks. Edits
+// aren't new content, so they're neither checked nor recorded.
+func (f *SpamFilter) Check(content Content) Decision {
+	text := strings.ToLower(strings.Join(strings.Fields(content.Text), " "))
+	if text == "" || content.IsEdit {
+		return Allow()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-f.window)
+
+	// Once per window, forget the users who sent nothing within it
+	if now.Sub(f.lastSweep) >= f.window {
+		f.evictIdle(cutoff)
+		f.lastSweep = now
+	}
+
+	// Drop entries that are outside the window
+	recent := f.history[content.UserID][:0]
+	repeats := 0
+	for _, entry := range f.history[content.UserID] {
+		if entry.postedAt.After(cutoff) {
+			recent = append(recent, entry)
+			if entry.text == text {
+				repeats++
+			}
+		}
+	}
+
+	if repeats >= f.repeatLimit {
+		f.history[content.UserID] = recent
+		return Reject("you are sending the same message too often")
+	}
+
+	f.history[content.UserID] = append(recent, spamEntry{text: text, postedAt: now})
+	return Allow()
+}
+
+// evictIdle removes the history of users whose latest entry is before the
+// cutoff. Entries are recorded in order, so only the last one needs checking.
+func (f *SpamFilter) evictIdle(cutoff time.Time) {
+	for userID, entries := range f.history {
+		if len(entries) == 0 || !entries[len(entries)-1].postedAt.After(cutoff) {
+			delete(f.history, userID)
+		}
+	}
+}
@@ -18,15 +18,17 @@ type NotificationService struct {
 	repo             Repository
 	notificationRepo notifications.Service
 	log              *logger.Logger
+	filter           NotificationFilter
 }
 
 // NewNotificationService creates a new event notification service
-func NewNotificationService(hub *websocket.Hub, repo Repository, notificationRepo notifications.Service, log *logger.Logger) *NotificationService {
+func NewNotificationService(hub *websocket.Hub, repo Repository, notificationRepo notifications.Service, log *logger.Logger, filter NotificationFilter) *NotificationService {
 	return &NotificationService{
 		hub:              hub,
 		repo:             repo,
 		notificationRepo: notificationRepo,
 		log:              log,
+		filter:           filter,
 	}
 }
 
@@ -94,11 +96,16 @@ func (s *NotificationService) SendEventUpdatedNotification(event *models.GroupEv
 		return
 	}
 
-	// Send to all group members
+	memberIDs := make([]string, 0, len(members))
 	for _, member := range members {
+		memberIDs = append(memberIDs, member.UserID)
+	}
+
+	// Send to all group members who want to hear about events
+	for _, memberID := range s.filter.FilterNotified(event.GroupID, memberIDs, models.GroupNotificationEvent) {
 		// Create notification in database
 		notification := &notifications.NewNotification{
-			UserId:          member.UserID,
+			UserId:          memberID,
 			NotficationType: "groupEventUpdated",
 			SenderId:        sql.NullString{String: event.CreatorID, Valid: true},
 			Message:         fmt.Sprintf("updated the event %s", event.Title),
@@ -110,7 +117,7 @@ func (s *NotificationService) SendEventUpdatedNotification(event *models.GroupEv
 		}
 
 		// Retrieve the newly created notification
-		notifications, err := s.notificationRepo.GetNotifications(member.UserID, 1, 0)
+		notifications, err := s.notificationRepo.GetNotifications(memberID, 1, 0)
 		if err != nil || len(notifications) == 0 {
 			s.log.Error("Failed to retrieve newly created notification: %v", err)
 			continue
@@ -135,7 +142,7 @@ func (s *NotificationService) SendEventUpdatedNotification(event *models.GroupEv
 			},
 		}
 
-		s.hub.BroadcastToUser(member.UserID, notificationEvent)
+		s.hub.BroadcastToUser(memberID, notificationEvent)
 	}
 }
 
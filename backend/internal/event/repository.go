@@ -31,7 +31,6 @@ type Repository interface {
 	GetUserBasicByID(userID string) (*models.UserBasic, error)
 	GetGroupByID(id string) (*models.Group, error)
 	IsGroupMember(groupID, userID string) (bool, error)
-	GetMemberRole(groupID, userID string) (string, error)
 	GetGroupMembers(groupID string, status string) ([]*models.GroupMember, error)
 }
 
@@ -499,7 +498,9 @@ func (r *SQLiteRepository) GetGroupByID(id string) (*models.Group, error) {
 func (r *SQLiteRepository) IsGroupMember(groupID, userID string) (bool, error) {
 	var count int
 	err := r.db.QueryRow(
-		"SELECT COUNT(*) FROM group_members WHERE group_id = ? AND user_id = ? AND status = 'accepted'",
+		`SELECT COUNT(*) FROM group_members gm
+		 JOIN groups g ON g.id = gm.group_id AND g.archived_at IS NULL
+		 WHERE gm.group_id = ? AND gm.user_id = ? AND gm.status = 'accepted'`,
 		groupID, userID,
 	).Scan(&count)
 	if err != nil {
@@ -509,23 +510,6 @@ func (r *SQLiteRepository) IsGroupMember(groupID, userID string) (bool, error) {
 	return count > 0, nil
 }
 
-// GetMemberRole gets a member's role in a group
-func (r *SQLiteRepository) GetMemberRole(groupID, userID string) (string, error) {
-	var role string
-	err := r.db.QueryRow(
-		"SELECT role FROM group_members WHERE group_id = ? AND user_id = ? AND status = 'accepted'",
-		groupID, userID,
-	).Scan(&role)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return "", fmt.Errorf("user is not a member of this group")
-		}
-		return "", fmt.Errorf("failed to get member role: %w", err)
-	}
-
-	return role, nil
-}
-
 // GetGroupMembers gets all members of a group with optional status filter
 func (r *SQLiteRepository) GetGroupMembers(groupID string, status string) ([]*models.GroupMember, error) {
 	var query string
@@ -29,6 +29,17 @@ type Service interface {
 	GetEventResponses(eventID, userID string, responseType string) ([]*models.EventResponse, error)
 }
 
+// PermissionChecker checks what members may do in their groups
+type PermissionChecker interface {
+	CanPerform(groupID, userID, action string) (bool, error)
+}
+
+// NotificationFilter drops the members whose notification settings for a group
+// don't let a kind of notification through
+type NotificationFilter interface {
+	FilterNotified(groupID string, userIDs []string, kind string) []string
+}
+
 // EventService implements the Service interface
 type EventService struct {
 	repo                Repository
@@ -36,11 +47,13 @@ type EventService struct {
 	log                 *logger.Logger
 	wsHub               *websocket.Hub
 	notificationService *NotificationService
+	permissions         PermissionChecker
+	notificationFilter  NotificationFilter
 }
 
 // NewService creates a new event service
-func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Logger, NotificationRepo notifications.Service, wsHub *websocket.Hub) *EventService {
-	notificationSvc := NewNotificationService(wsHub, repo, NotificationRepo, log)
+func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Logger, NotificationRepo notifications.Service, wsHub *websocket.Hub, permissions PermissionChecker, notificationFilter NotificationFilter) *EventService {
+	notificationSvc := NewNotificationService(wsHub, repo, NotificationRepo, log, notificationFilter)
 
 	return &EventService{
 		repo:                repo,
@@ -48,19 +61,20 @@ func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Log
 		log:                 log,
 		wsHub:               wsHub,
 		notificationService: notificationSvc,
+		permissions:         permissions,
+		notificationFilter:  notificationFilter,
 	}
 }
 
 // CreateEvent creates a new event in a group
 func (s *EventService) CreateEvent(groupID, userID, title, description string, eventDate time.Time, banner *multipart.FileHeader, response string) (*models.GroupEvent, error) {
-	// Check if user is a member of the group
-	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	canCreate, err := s.permissions.CanPerform(groupID, userID, models.GroupActionCreateEvent)
 	if err != nil {
 		return nil, err
 	}
 
-	if !isMember {
-		return nil, errors.New("you must be a member of the group to create an event")
+	if !canCreate {
+		return nil, errors.New("you don't have permission to create events in this group")
 	}
 
 	// Create event
@@ -105,11 +119,16 @@ func (s *EventService) CreateEvent(groupID, userID, title, description string, e
 	}
 
 	
-	// Broadcast event creation to group members
+	memberIDs := make([]string, 0, len(groupMembers))
 	for _, member := range groupMembers {
-		if member.UserID != userID {
+		memberIDs = append(memberIDs, member.UserID)
+	}
+
+	// Broadcast event creation to group members who want to hear about events
+	for _, memberID := range s.notificationFilter.FilterNotified(groupID, memberIDs, models.GroupNotificationEvent) {
+		if memberID != userID {
 			newNotification := &notifications.NewNotification{
-				UserId:          member.UserID,
+				UserId:          memberID,
 				SenderId:        sql.NullString{String: userID, Valid: true},
 				NotficationType: "groupEvent",
 				Message:         fmt.Sprintf(event.Title),
@@ -117,7 +136,7 @@ func (s *EventService) CreateEvent(groupID, userID, title, description string, e
 				TargetEventID:   sql.NullString{String: event.ID, Valid: true},
 			}
 			// Notify group members about new event
-			s.notificationService.SendEventCreatedNotification(userID, member.UserID, newNotification, eventcreator)
+			s.notificationService.SendEventCreatedNotification(userID, memberID, newNotification, eventcreator)
 		}
 	}
 	
@@ -201,16 +220,15 @@ func (s *EventService) UpdateEvent(eventID, userID, title, description string, e
 		return nil, err
 	}
 
-	// Check if user is the creator or an admin
+	// Check if user is the creator or may manage the group's events
 	if event.CreatorID != userID {
-		// Check if user is an admin
-		role, err := s.repo.GetMemberRole(event.GroupID, userID)
+		canManage, err := s.permissions.CanPerform(event.GroupID, userID, models.GroupActionManageEvents)
 		if err != nil {
 			return nil, err
 		}
 
-		if role != "admin" {
-			return nil, errors.New("only the event creator or group admins can update this event")
+		if !canManage {
+			return nil, errors.New("you don't have permission to update this event")
 		}
 	}
 
@@ -251,16 +269,15 @@ func (s *EventService) DeleteEvent(eventID, userID string) error {
 		return err
 	}
 
-	// Check if user is the creator or an admin
+	// Check if user is the creator or may manage the group's events
 	if event.CreatorID != userID {
-		// Check if user is an admin
-		role, err := s.repo.GetMemberRole(event.GroupID, userID)
+		canManage, err := s.permissions.CanPerform(event.GroupID, userID, models.GroupActionManageEvents)
 		if err != nil {
 			return err
 		}
 
-		if role != "admin" {
-			return errors.New("only the event creator or group admins can delete this event")
+		if !canManage {
+			return errors.New("you don't have permission to delete this event")
 		}
 	}
 
@@ -2,7 +2,9 @@ package follow
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/Athooh/social-network/internal/auth"
 	"github.com/Athooh/social-network/pkg/httputil"
@@ -172,6 +174,97 @@ func (h *Handler) DeclineFollowRequest(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
+// CancelFollowRequest handles the requester withdrawing a pending follow request
+func (h *Handler) CancelFollowRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get user ID from context (set by auth middleware)
+	followerID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || followerID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse request body
+	var request struct {
+		UserID string `json:"userId"` // The user the request was sent to
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.CancelFollowRequest(followerID, request.UserID); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]bool{"success": true})
+}
+
+// BulkAcceptFollowRequests handles accepting several follow requests at once
+func (h *Handler) BulkAcceptFollowRequests(w http.ResponseWriter, r *http.Request) {
+	h.handleBulkFollowRequests(w, r, h.service.AcceptFollowRequests)
+}
+
+// BulkDeclineFollowRequests handles declining several follow requests at once
+func (h *Handler) BulkDeclineFollowRequests(w http.ResponseWriter, r *http.Request) {
+	h.handleBulkFollowRequests(w, r, h.service.DeclineFollowRequests)
+}
+
+// handleBulkFollowRequests parses a bulk request body, either a list of
+// follower IDs or "all": true, and applies the given action
+func (h *Handler) handleBulkFollowRequests(w http.ResponseWriter, r *http.Request, apply func(followingID string, followerIDs []string) (int, error)) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Get user ID from context (set by auth middleware)
+	followingID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || followingID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse request body
+	var request struct {
+		FollowerIDs []string `json:"followerIds"`
+		All         bool     `json:"all"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !request.All && len(request.FollowerIDs) == 0 {
+		h.sendError(w, http.StatusBadRequest, "Follower IDs are required")
+		return
+	}
+
+	followerIDs := request.FollowerIDs
+	if request.All {
+		followerIDs = nil
+	}
+
+	count, err := apply(followingID, followerIDs)
+	if err != nil {
+		h.log.Error("Failed to update follow requests: %v", err)
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"count":   count,
+	})
+}
+
 // GetPendingFollowRequests handles a request to get all pending follow requests
 func (h *Handler) GetPendingFollowRequests(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
@@ -193,54 +286,115 @@ func (h *Handler) GetPendingFollowRequests(w http.ResponseWriter, r *http.Reques
 	h.sendJSON(w, http.StatusOK, requests)
 }
 
-// GetFollowers handles a request to get all followers of a user
+// GetFollowers handles a request to get a page of a user's followers
 func (h *Handler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	h.handleConnections(w, r, true)
+}
+
+// GetFollowing handles a request to get a page of the users a user is following
+func (h *Handler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	h.handleConnections(w, r, false)
+}
+
+// handleConnections serves a followers or following page. The list belongs to
+// the "userId" query parameter (the current user by default) and is filtered by
+// "q" and "favorites", ordered by "sort" ("recent" or "alphabetical") and paged
+// with "cursor" and "limit".
+func (h *Handler) handleConnections(w http.ResponseWriter, r *http.Request, followers bool) {
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserIDFromContext(r.Context())
 	if !ok || userID == "" {
 		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
-	profileID := r.URL.Query().Get("userId")
+
+	query := r.URL.Query()
+	profileID := query.Get("userId")
 	if profileID == "" {
 		profileID = userID
 	}
 
-	// Get followers
-	followers, err := h.service.GetFollowers(profileID)
+	opts := ConnectionListOptions{
+		ViewerID: userID,
+		Search:   query.Get("q"),
+		Sort:     query.Get("sort"),
+		Cursor:   query.Get("cursor"),
+
+		FavoritesOnly: query.Get("favorites") == "true",
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid limit parameter")
+			return
+		}
+		opts.Limit = limit
+	}
+
+	var page *ConnectionPage
+	var err error
+	if followers {
+		page, err = h.service.GetFollowers(profileID, opts)
+	} else {
+		page, err = h.service.GetFollowing(profileID, opts)
+	}
 	if err != nil {
-		h.log.Error("Failed to get followers: %v", err)
+		if errors.Is(err, ErrConnectionsPrivate) {
+			h.sendError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, ErrInvalidCursor) {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.log.Error("Failed to get connections: %v", err)
 		h.sendError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Return the followers
-	h.sendJSON(w, http.StatusOK, followers)
+	h.sendJSON(w, http.StatusOK, page)
 }
 
-// GetFollowing handles a request to get all users a user is following
-func (h *Handler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+// SetFavorite handles marking or unmarking a followed user as a favorite.
+// Post notifications default to on when favoriting.
+func (h *Handler) SetFavorite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
 	// Get user ID from context (set by auth middleware)
 	userID, ok := auth.GetUserIDFromContext(r.Context())
 	if !ok || userID == "" {
 		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
-	profileID := r.URL.Query().Get("userId")
-	if profileID == "" {
-		profileID = userID
+
+	var req struct {
+		UserID       string `json:"userId"`
+		Favorite     bool   `json:"favorite"`
+		NotifyOnPost *bool  `json:"notifyOnPost"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
 	}
 
-	// Get following
-	following, err := h.service.GetFollowing(profileID)
-	if err != nil {
-		h.log.Error("Failed to get following: %v", err)
-		h.sendError(w, http.StatusInternalServerError, err.Error())
+	notifyOnPost := req.Favorite
+	if req.NotifyOnPost != nil {
+		notifyOnPost = *req.NotifyOnPost
+	}
+
+	if err := h.service.SetFavorite(userID, req.UserID, req.Favorite, notifyOnPost); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Return the following
-	h.sendJSON(w, http.StatusOK, following)
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"favorite":     req.Favorite,
+		"notifyOnPost": req.Favorite && notifyOnPost,
+	})
 }
 
 // IsFollowing handles a request to check if a user is following another user
@@ -271,6 +425,7 @@ func (h *Handler) IsFollowing(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, http.StatusOK, map[string]bool{"isFollowing": isFollowing})
 }
 
+// GetSuggestedFriends handles listing friend suggestions with pagination
 func (h *Handler) GetSuggestedFriends(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -281,8 +436,18 @@ func (h *Handler) GetSuggestedFriends(w http.ResponseWriter, r *http.Request) {
 		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
 	// Get suggested friends
-	suggestions, err := h.service.GetSuggestedFriends(userID)
+	suggestions, total, err := h.service.GetSuggestedFriends(userID, limit, offset)
 	if err != nil {
 		h.log.Error("Failed to get suggested friends: %v", err)
 		h.sendError(w, http.StatusInternalServerError, err.Error())
@@ -293,9 +458,39 @@ func (h *Handler) GetSuggestedFriends(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, http.StatusOK, map[string]interface{}{
 		"suggestions": suggestions,
 		"count":       len(suggestions),
+		"total":       total,
+		"hasMore":     offset+len(suggestions) < total,
 	})
 }
 
+// DismissSuggestion handles hiding a user from the current user's suggestions
+func (h *Handler) DismissSuggestion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		UserID string `json:"userId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.DismissSuggestion(userID, request.UserID); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]string{"message": "Suggestion dismissed"})
+}
+
 func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
 	httputil.SendJSON(w, status, data)
 }
@@ -12,6 +12,10 @@ const (
 	StatusAccepted FollowStatus = "accepted"
 	// StatusDeclined indicates a follow request has been declined
 	StatusDeclined FollowStatus = "declined"
+	// StatusCancelled indicates the requester withdrew the follow request
+	StatusCancelled FollowStatus = "cancelled"
+	// StatusExpired indicates the follow request was pending for too long
+	StatusExpired FollowStatus = "expired"
 )
 
 // FollowRequest represents a follow request between users
@@ -19,7 +23,7 @@ type FollowRequest struct {
 	ID          int64
 	FollowerID  string // User who initiated the follow
 	FollowingID string // User being followed
-	Status      string // Status of the follow request (pending, accepted, declined)
+	Status      string // Status of the follow request (pending, accepted, declined, cancelled, expired)
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -32,17 +36,55 @@ type Follower struct {
 	CreatedAt   time.Time
 }
 
+// Connection list sort orders
+const (
+	SortRecent       = "recent"       // newest connections first
+	SortAlphabetical = "alphabetical" // by full name
+)
+
+// ConnectionListOptions filters and pages a followers or following list
+type ConnectionListOptions struct {
+	ViewerID string // relationship flags are computed relative to this user
+	Search   string // matches the name or nickname
+	Sort     string // SortRecent or SortAlphabetical
+	Cursor   string // from the previous page's NextCursor, empty for the first page
+	Limit    int
+
+	FavoritesOnly bool // only users the viewer has marked as favorites
+}
+
 type BasicUser struct {
 	ID string
 }
 
 type SuggestedFriend struct {
-	ID            string  `json:"id"`
-	FirstName     string  `json:"firstName"`
-	LastName      string  `json:"lastName"`
-	Nickname      string  `json:"nickname"`
-	Avatar        string  `json:"avatar"`
-	IsPublic      bool    `json:"isPublic"`
-	MutualFriends int     `json:"mutualFriends"`
-	IsOnline      bool    `json:"isOnline"`
+	ID              string   `json:"id"`
+	FirstName       string   `json:"firstName"`
+	LastName        string   `json:"lastName"`
+	Nickname        string   `json:"nickname"`
+	Avatar          string   `json:"avatar"`
+	IsPublic        bool     `json:"isPublic"`
+	MutualFriends   int      `json:"mutualFriends"`
+	SharedGroups    int      `json:"sharedGroups"`
+	SharedEvents    int      `json:"sharedEvents"`
+	SharedInterests []string `json:"sharedInterests"`
+	Score           int      `json:"score"`
+	Reason          string   `json:"reason"` // e.g. "3 mutual friends, both in Go Nairobi"
+	IsOnline        bool     `json:"isOnline"`
+}
+
+// SuggestionCandidate is a user reached by walking the follow and group graph,
+// with the connection counts used to score them
+type SuggestionCandidate struct {
+	ID              string
+	FirstName       string
+	LastName        string
+	Nickname        string
+	Avatar          string
+	IsPublic        bool
+	MutualFriends   int
+	SharedGroups    int
+	SharedGroupName string // one of the shared groups, for the explanation
+	SharedEvents    int
+	Interests       string // comma-separated interests and skills from the profile
 }
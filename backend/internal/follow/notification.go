@@ -1,11 +1,14 @@
 package follow
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
+	"github.com/Athooh/social-network/internal/mute"
 	notifications "github.com/Athooh/social-network/internal/notifcations"
 	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
 	"github.com/Athooh/social-network/pkg/user"
 	"github.com/Athooh/social-network/pkg/websocket"
 	"github.com/Athooh/social-network/pkg/websocket/events"
@@ -17,15 +20,17 @@ type NotificationService struct {
 	userRepo         user.Repository
 	log              *logger.Logger
 	notificationRepo notifications.Service
+	mutes            mute.Service
 }
 
 // NewNotificationService creates a new follow notification service
-func NewNotificationService(hub *websocket.Hub, userRepo user.Repository, notifcationRepo notifications.Service, log *logger.Logger) *NotificationService {
+func NewNotificationService(hub *websocket.Hub, userRepo user.Repository, notifcationRepo notifications.Service, mutes mute.Service, log *logger.Logger) *NotificationService {
 	return &NotificationService{
 		hub:              hub,
 		userRepo:         userRepo,
 		log:              log,
 		notificationRepo: notifcationRepo,
+		mutes:            mutes,
 	}
 }
 
@@ -36,6 +41,11 @@ func (s *NotificationService) SendFollowRequestNotification(followerID, followin
 		return
 	}
 
+	// The request still stands; the recipient just isn't told about it
+	if s.mutes.IsMuted(followingID, followerID, models.MuteTypeNotifications) {
+		return
+	}
+
 	// Create notification in database
 	if err := s.notificationRepo.CreateNotification(notification); err != nil {
 		s.log.Error("Failed to create follow request notification: %v", err)
@@ -71,7 +81,7 @@ func (s *NotificationService) SendFollowRequestNotification(followerID, followin
 
 // SendFollowRequestAcceptedNotification sends a notification when a follow request is accepted
 func (s *NotificationService) SendFollowRequestAcceptedNotification(followerID, followingID string) {
-	if s.hub == nil {
+	if s.hub == nil || s.mutes.IsMuted(followerID, followingID, models.MuteTypeNotifications) {
 		return
 	}
 
@@ -99,9 +109,57 @@ func (s *NotificationService) SendFollowRequestAcceptedNotification(followerID,
 	s.hub.BroadcastToUser(followerID, event)
 }
 
+// NotifyFollowRequestAccepted stores a notification telling the
+// follower their request was accepted and pushes it to them
+func (s *NotificationService) NotifyFollowRequestAccepted(followerID string, following *user.User) {
+	if s.mutes.IsMuted(followerID, following.ID, models.MuteTypeNotifications) {
+		return
+	}
+
+	followingName := fmt.Sprintf("%s %s", following.FirstName, following.LastName)
+
+	notification := &notifications.NewNotification{
+		UserId:          followerID,
+		SenderId:        sql.NullString{String: following.ID, Valid: true},
+		NotficationType: "followRequestAccepted",
+		Message:         fmt.Sprintf("%s accepted your follow request", followingName),
+	}
+
+	if err := s.notificationRepo.CreateNotification(notification); err != nil {
+		s.log.Error("Failed to create follow request accepted notification: %v", err)
+		return
+	}
+
+	if s.hub == nil {
+		return
+	}
+
+	// Retrieve the newly created notification to get its ID and CreatedAt
+	notifications, err := s.notificationRepo.GetNotifications(followerID, 1, 0)
+	if err != nil || len(notifications) == 0 {
+		s.log.Error("Failed to retrieve newly created notification: %v", err)
+		return
+	}
+	dbNotification := notifications[0]
+
+	s.hub.BroadcastToUser(followerID, events.Event{
+		Type: events.HeaderNotificationUpdate,
+		Payload: map[string]interface{}{
+			"id":           dbNotification.ID,
+			"type":         notification.NotficationType,
+			"senderId":     following.ID,
+			"senderName":   followingName,
+			"senderAvatar": following.Avatar,
+			"message":      notification.Message,
+			"createdAt":    dbNotification.CreatedAt.Format(time.RFC3339),
+			"isRead":       dbNotification.IsRead,
+		},
+	})
+}
+
 // SendFollowNotification sends a notification when a user follows/unfollows another user
 func (s *NotificationService) SendFollowNotification(followerID, followingID string, isFollow bool) {
-	if s.hub == nil {
+	if s.hub == nil || s.mutes.IsMuted(followingID, followerID, models.MuteTypeNotifications) {
 		return
 	}
 
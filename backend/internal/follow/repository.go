@@ -2,9 +2,13 @@ package follow
 
 import (
 	"database/sql"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
 )
 
 // Repository defines the interface for follow data access
@@ -14,6 +18,7 @@ type Repository interface {
 	GetFollowRequest(followerID, followingID string) (*FollowRequest, error)
 	UpdateFollowRequestStatus(followerID, followingID, status string) error
 	GetPendingFollowRequests(userID string) ([]*FollowRequest, error)
+	ExpireFollowRequests(pendingSince time.Time) (int64, error)
 
 	// Followers
 	CreateFollower(followerID, followingID string) error
@@ -23,6 +28,8 @@ type Repository interface {
 	GetFollowing(userID string) ([]*Follower, error)
 	GetFollowersCount(userID string) (int, error)
 	GetFollowingCount(userID string) (int, error)
+	GetConnections(userID string, followers bool, opts ConnectionListOptions) ([]*FollowerWithUser, []string, error)
+	SetFavorite(followerID, followingID string, favorite, notifyOnPost bool) (bool, error)
 
 	// User profile check
 	IsUserProfilePublic(userID string) (bool, error)
@@ -31,7 +38,10 @@ type Repository interface {
 	GetMutualFollowers(userID1, userID2 string) ([]*Follower, error)
 	GetMutualFollowersCount(userID1, userID2 string) (int, error)
 
-	GetUsersNotFollowed(userID string) ([]*BasicUser, error)
+	// Suggestions
+	GetSuggestionCandidates(userID string, limit int) ([]*SuggestionCandidate, error)
+	GetProfileInterests(userID string) (string, error)
+	DismissSuggestion(userID, dismissedUserID string) error
 }
 
 // SQLiteRepository implements Repository interface for SQLite
@@ -133,6 +143,24 @@ func (r *SQLiteRepository) GetPendingFollowRequests(userID string) ([]*FollowReq
 	return requests, rows.Err()
 }
 
+// ExpireFollowRequests marks requests that have been pending since before the
+// given time as expired. A request's updated_at is reset whenever it is made
+// again, so re-sent requests get a fresh expiry.
+func (r *SQLiteRepository) ExpireFollowRequests(pendingSince time.Time) (int64, error) {
+	query := `
+		UPDATE follow_requests
+		SET status = ?, updated_at = ?
+		WHERE status = ? AND updated_at < ?
+	`
+
+	result, err := r.db.Exec(query, string(StatusExpired), time.Now(), string(StatusPending), pendingSince)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 func (r *SQLiteRepository) CreateFollower(followerID, followingID string) error {
 	// Start transaction
 	tx, err := r.db.Begin()
@@ -328,6 +356,163 @@ func (r *SQLiteRepository) GetFollowing(userID string) ([]*Follower, error) {
 	return following, rows.Err()
 }
 
+// GetConnections gets a page of the user's followers (or, when followers is
+// false, the users they follow) with each listed user's relationship to the
+// viewer. It also returns the cursor pointing after each row.
+func (r *SQLiteRepository) GetConnections(userID string, followers bool, opts ConnectionListOptions) ([]*FollowerWithUser, []string, error) {
+	ownColumn, otherColumn := "follower_id", "following_id"
+	if followers {
+		ownColumn, otherColumn = "following_id", "follower_id"
+	}
+
+	query := `
+		SELECT f.id, f.follower_id, f.following_id, f.created_at,
+			u.id, u.first_name, u.last_name, COALESCE(u.avatar, ''),
+			COALESCE(us.is_online, 0) AND (u.id = ? OR COALESCE(us.presence, '') != 'invisible' AND CASE COALESCE(
+				(SELECT ps.who_can_see_online FROM privacy_settings ps WHERE ps.user_id = u.id), 'everyone')
+				WHEN 'everyone' THEN 1
+				WHEN 'followers' THEN EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = ? AND x.following_id = u.id)
+				WHEN 'mutuals' THEN EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = ? AND x.following_id = u.id)
+					AND EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = u.id AND x.following_id = ?)
+				ELSE 0
+				END),
+			EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = u.id AND x.following_id = ?),
+			EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = ? AND x.following_id = u.id),
+			EXISTS(SELECT 1 FROM follow_requests fr WHERE fr.follower_id = ? AND fr.following_id = u.id AND fr.status = ?),
+			(SELECT COUNT(*) FROM followers a
+				JOIN followers b ON b.follower_id = a.following_id
+				WHERE a.follower_id = ? AND b.following_id = u.id),
+			COALESCE(vf.is_favorite, 0), COALESCE(vf.notify_on_post, 0),
+			COALESCE(cs.status_text, ''), COALESCE(cs.status_emoji, ''),
+			LOWER(u.first_name || ' ' || u.last_name)
+		FROM followers f
+		JOIN users u ON u.id = f.` + otherColumn + `
+		LEFT JOIN user_status us ON us.user_id = u.id
+		LEFT JOIN followers vf ON vf.follower_id = ? AND vf.following_id = u.id
+		LEFT JOIN user_status cs ON cs.user_id = u.id AND (cs.status_expires_at IS NULL OR cs.status_expires_at > ?)
+		WHERE f.` + ownColumn + ` = ?
+	`
+	args := []interface{}{
+		opts.ViewerID, opts.ViewerID, opts.ViewerID, opts.ViewerID, // online status visibility
+		opts.ViewerID, opts.ViewerID, opts.ViewerID, string(StatusPending), opts.ViewerID, opts.ViewerID, time.Now().UTC(), userID,
+	}
+
+	if opts.FavoritesOnly {
+		query += ` AND vf.is_favorite = 1`
+	}
+
+	if opts.Search != "" {
+		query += ` AND (u.first_name || ' ' || u.last_name LIKE ? OR u.nickname LIKE ?)`
+		pattern := "%" + opts.Search + "%"
+		args = append(args, pattern, pattern)
+	}
+
+	if opts.Cursor != "" {
+		sortKey, id, err := decodeConnectionCursor(opts.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		if opts.Sort == SortAlphabetical {
+			query += ` AND (LOWER(u.first_name || ' ' || u.last_name), u.id) > (?, ?)`
+			args = append(args, sortKey, id)
+		} else {
+			query += ` AND f.id < ?`
+			args = append(args, id)
+		}
+	}
+
+	if opts.Sort == SortAlphabetical {
+		query += ` ORDER BY LOWER(u.first_name || ' ' || u.last_name), u.id`
+	} else {
+		query += ` ORDER BY f.id DESC`
+	}
+	query += ` LIMIT ?`
+	args = append(args, opts.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var connections []*FollowerWithUser
+	var cursors []string
+	for rows.Next() {
+		var connection FollowerWithUser
+		var firstName, lastName, sortName string
+		err := rows.Scan(
+			&connection.ID,
+			&connection.FollowerID,
+			&connection.FollowingID,
+			&connection.CreatedAt,
+			&connection.UserID,
+			&firstName,
+			&lastName,
+			&connection.UserAvatar,
+			&connection.IsOnline,
+			&connection.FollowsYou,
+			&connection.YouFollow,
+			&connection.RequestPending,
+			&connection.MutualCount,
+			&connection.IsFavorite,
+			&connection.NotifyOnPost,
+			&connection.StatusText,
+			&connection.StatusEmoji,
+			&sortName,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		connection.UserName = fmt.Sprintf("%s %s", firstName, lastName)
+		connections = append(connections, &connection)
+
+		if opts.Sort == SortAlphabetical {
+			cursors = append(cursors, encodeConnectionCursor(sortName, connection.UserID))
+		} else {
+			cursors = append(cursors, encodeConnectionCursor("", fmt.Sprintf("%d", connection.ID)))
+		}
+	}
+
+	return connections, cursors, rows.Err()
+}
+
+// SetFavorite updates the favorites tier of a follow. It reports false when
+// the follower doesn't follow the user.
+func (r *SQLiteRepository) SetFavorite(followerID, followingID string, favorite, notifyOnPost bool) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE followers
+		SET is_favorite = ?, notify_on_post = ?
+		WHERE follower_id = ? AND following_id = ?
+	`, favorite, notifyOnPost, followerID, followingID)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// encodeConnectionCursor builds an opaque cursor from a sort key and a tiebreaker ID
+func encodeConnectionCursor(sortKey, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(sortKey + "\x00" + id))
+}
+
+// decodeConnectionCursor splits a cursor made by encodeConnectionCursor
+func decodeConnectionCursor(cursor string) (string, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	sortKey, id, found := strings.Cut(string(raw), "\x00")
+	if !found {
+		return "", "", ErrInvalidCursor
+	}
+	return sortKey, id, nil
+}
+
 // GetFollowersCount retrieves the number of followers for a user
 func (r *SQLiteRepository) GetFollowersCount(userID string) (int, error) {
 	query := `
@@ -433,38 +618,133 @@ func (r *SQLiteRepository) GetMutualFollowersCount(userID1, userID2 string) (int
 	return count, nil
 }
 
-func (r *SQLiteRepository) GetUsersNotFollowed(userID string) ([]*BasicUser, error) {
+// GetSuggestionCandidates walks the graph from the user to find people they may
+// know: users followed by the people they follow, and members of their groups.
+// Users already followed or requested, users with a pending request to them and
+// dismissed suggestions are excluded. Candidates come back ranked by their
+// connection counts, at most limit of them.
+func (r *SQLiteRepository) GetSuggestionCandidates(userID string, limit int) ([]*SuggestionCandidate, error) {
 	query := `
-		SELECT u.id
-		FROM users u
-		WHERE u.id != ? 
-		AND u.id NOT IN (
-			SELECT f.following_id 
-			FROM followers f 
-			WHERE f.follower_id = ?
-		)
-		AND u.id NOT IN (
-			SELECT fr.following_id 
-			FROM follow_requests fr 
-			WHERE fr.follower_id = ? AND fr.status = 'pending'
+		WITH my_following AS (
+			SELECT following_id AS id FROM followers WHERE follower_id = ?1
+		),
+		my_groups AS (
+			SELECT group_id FROM group_members WHERE user_id = ?1 AND status = 'accepted'
+		),
+		my_events AS (
+			SELECT event_id FROM event_responses WHERE user_id = ?1 AND response = 'going'
+		),
+		candidates AS (
+			SELECT f.following_id AS id
+			FROM followers f
+			JOIN my_following mf ON mf.id = f.follower_id
+			UNION
+			SELECT gm.user_id
+			FROM group_members gm
+			JOIN my_groups mg ON mg.group_id = gm.group_id
+			WHERE gm.status = 'accepted'
+		),
+		scored AS (
+			SELECT u.id, u.first_name, u.last_name, u.nickname, u.avatar, u.is_public, u.created_at,
+				(SELECT COUNT(*) FROM followers f
+					JOIN my_following mf ON mf.id = f.follower_id
+					WHERE f.following_id = u.id) AS mutual_friends,
+				(SELECT COUNT(*) FROM group_members gm
+					JOIN my_groups mg ON mg.group_id = gm.group_id
+					WHERE gm.user_id = u.id AND gm.status = 'accepted') AS shared_groups,
+				(SELECT g.name FROM group_members gm
+					JOIN my_groups mg ON mg.group_id = gm.group_id
+					JOIN groups g ON g.id = gm.group_id
+					WHERE gm.user_id = u.id AND gm.status = 'accepted'
+					ORDER BY g.name LIMIT 1) AS shared_group_name,
+				(SELECT COUNT(*) FROM event_responses er
+					JOIN my_events me ON me.event_id = er.event_id
+					WHERE er.user_id = u.id AND er.response = 'going') AS shared_events,
+				(SELECT COALESCE(GROUP_CONCAT(ps.name), '') FROM profile_skills ps WHERE ps.user_id = u.id) AS interests
+			FROM candidates c
+			JOIN users u ON u.id = c.id
+			WHERE u.id != ?1
+			AND u.id NOT IN (SELECT id FROM my_following)
+			AND u.id NOT IN (
+				SELECT following_id FROM follow_requests WHERE follower_id = ?1 AND status = 'pending'
+			)
+			AND u.id NOT IN (
+				SELECT follower_id FROM follow_requests WHERE following_id = ?1 AND status = 'pending'
+			)
+			AND u.id NOT IN (
+				SELECT dismissed_user_id FROM suggestion_dismissals WHERE user_id = ?1
+			)
+			-- Candidates aren't followed by the user, so only people who can be
+			-- found by everyone qualify
+			AND u.id NOT IN (
+				SELECT user_id FROM privacy_settings WHERE who_can_find_me != ?6
+			)
 		)
-		ORDER BY u.created_at DESC
+		SELECT id, first_name, last_name, COALESCE(nickname, ''), COALESCE(avatar, ''), is_public,
+			mutual_friends, shared_groups, COALESCE(shared_group_name, ''), shared_events, interests
+		FROM scored
+		ORDER BY mutual_friends * ?3 + shared_groups * ?4 + shared_events * ?5 DESC, created_at DESC
+		LIMIT ?2
 	`
-	rows, err := r.db.Query(query, userID, userID, userID)
+
+	rows, err := r.db.Query(query, userID, limit, mutualFriendWeight, sharedGroupWeight, sharedEventWeight, models.AudienceEveryone)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []*BasicUser
+	var candidates []*SuggestionCandidate
 	for rows.Next() {
-		var user BasicUser
-		err := rows.Scan(&user.ID)
+		var candidate SuggestionCandidate
+		err := rows.Scan(
+			&candidate.ID,
+			&candidate.FirstName,
+			&candidate.LastName,
+			&candidate.Nickname,
+			&candidate.Avatar,
+			&candidate.IsPublic,
+			&candidate.MutualFriends,
+			&candidate.SharedGroups,
+			&candidate.SharedGroupName,
+			&candidate.SharedEvents,
+			&candidate.Interests,
+		)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, &user)
+		candidates = append(candidates, &candidate)
 	}
 
-	return users, rows.Err()
+	return candidates, rows.Err()
+}
+
+// GetProfileInterests gets a user's comma-separated interests and skills
+func (r *SQLiteRepository) GetProfileInterests(userID string) (string, error) {
+	query := `
+		SELECT COALESCE(GROUP_CONCAT(name), '')
+		FROM profile_skills
+		WHERE user_id = ?
+	`
+
+	var interests string
+	err := r.db.QueryRow(query, userID).Scan(&interests)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return interests, nil
+}
+
+// DismissSuggestion hides a user from another user's friend suggestions
+func (r *SQLiteRepository) DismissSuggestion(userID, dismissedUserID string) error {
+	query := `
+		INSERT OR IGNORE INTO suggestion_dismissals (id, user_id, dismissed_user_id, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, userID+":"+dismissedUserID, userID, dismissedUserID, time.Now())
+	return err
 }
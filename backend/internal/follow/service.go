@@ -4,13 +4,35 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/Athooh/social-network/internal/mute"
 	notifications "github.com/Athooh/social-network/internal/notifcations"
+	"github.com/Athooh/social-network/internal/privacy"
 	"github.com/Athooh/social-network/pkg/logger"
 	"github.com/Athooh/social-network/pkg/user"
 	"github.com/Athooh/social-network/pkg/websocket"
 )
 
+// Suggestion ranking weights and limits
+const (
+	mutualFriendWeight      = 3
+	sharedGroupWeight       = 2
+	sharedEventWeight       = 2
+	sharedInterestWeight    = 1
+	maxSuggestionCandidates = 200
+	defaultSuggestionLimit  = 20
+	maxSuggestionLimit      = 50
+)
+
+// Connection list page sizes
+const (
+	defaultConnectionLimit = 20
+	maxConnectionLimit     = 100
+)
+
 // Service defines the follow service interface
 type Service interface {
 	// Follow/unfollow operations
@@ -20,16 +42,29 @@ type Service interface {
 	// Follow request operations
 	AcceptFollowRequest(followerID, followingID string) error
 	DeclineFollowRequest(followerID, followingID string) error
+	CancelFollowRequest(followerID, followingID string) error
+	AcceptFollowRequests(followingID string, followerIDs []string) (int, error)
+	DeclineFollowRequests(followingID string, followerIDs []string) (int, error)
+	AcceptAllFollowRequests(userID string) (int, error)
 	GetPendingFollowRequests(userID string) ([]*FollowRequestWithUser, error)
 
+	// Follow request expiry
+	ExpireFollowRequests(maxAge time.Duration) (int64, error)
+	RunRequestExpirySweeper(maxAge, interval time.Duration)
+
 	// Status checks
 	IsFollowing(followerID, followingID string) (bool, error)
 
 	// Retrieval operations
-	GetFollowers(userID string) ([]*FollowerWithUser, error)
-	GetFollowing(userID string) ([]*FollowerWithUser, error)
+	GetFollowers(userID string, opts ConnectionListOptions) (*ConnectionPage, error)
+	GetFollowing(userID string, opts ConnectionListOptions) (*ConnectionPage, error)
+
+	// Favorites
+	SetFavorite(userID, followingID string, favorite, notifyOnPost bool) error
 
-	GetSuggestedFriends(userID string) ([]*SuggestedFriend, error)
+	// Suggestions
+	GetSuggestedFriends(userID string, limit, offset int) ([]*SuggestedFriend, int, error)
+	DismissSuggestion(userID, dismissedUserID string) error
 }
 
 // FollowRequestWithUser extends FollowRequest with user information
@@ -40,14 +75,37 @@ type FollowRequestWithUser struct {
 	MutualFriends  int
 }
 
-// FollowerWithUser extends Follower with user information
+// FollowerWithUser extends Follower with user information and the listed
+// user's relationship to the viewer
 type FollowerWithUser struct {
 	Follower
-	UserName   string
-	UserAvatar string
-	IsOnline   bool
+	UserID         string // the listed user, the follower or the followed user
+	UserName       string
+	UserAvatar     string
+	IsOnline       bool
+	FollowsYou     bool // the listed user follows the viewer
+	YouFollow      bool // the viewer follows the listed user
+	RequestPending bool // the viewer has a pending request to the listed user
+	MutualCount    int  // people the viewer follows who also follow the listed user
+	IsFavorite     bool // the viewer marked the listed user as a favorite
+	NotifyOnPost   bool // the viewer is notified when the listed user posts
+	StatusText     string
+	StatusEmoji    string
 }
 
+// ConnectionPage is one page of a followers or following list
+type ConnectionPage struct {
+	Items      []*FollowerWithUser `json:"items"`
+	NextCursor string              `json:"nextCursor"` // empty when there are no more items
+}
+
+// ErrConnectionsPrivate is returned when the viewer may not see a private
+// user's followers or following
+var ErrConnectionsPrivate = errors.New("you can't see this user's connections")
+
+// ErrInvalidCursor is returned for a malformed connection list cursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 // FollowService implements the Service interface
 type FollowService struct {
 	repo            Repository
@@ -55,11 +113,12 @@ type FollowService struct {
 	statusRepo      user.StatusRepository
 	log             *logger.Logger
 	notificationSvc *NotificationService
+	privacy         privacy.Service
 }
 
 // NewService creates a new follow service
-func NewService(repo Repository, userRepo user.Repository, statusRepo user.StatusRepository, notificationRepo notifications.Service, log *logger.Logger, wsHub *websocket.Hub) Service {
-	notificationSvc := NewNotificationService(wsHub, userRepo, notificationRepo, log)
+func NewService(repo Repository, userRepo user.Repository, statusRepo user.StatusRepository, notificationRepo notifications.Service, mutes mute.Service, privacySvc privacy.Service, log *logger.Logger, wsHub *websocket.Hub) Service {
+	notificationSvc := NewNotificationService(wsHub, userRepo, notificationRepo, mutes, log)
 
 	return &FollowService{
 		repo:            repo,
@@ -67,6 +126,7 @@ func NewService(repo Repository, userRepo user.Repository, statusRepo user.Statu
 		statusRepo:      statusRepo,
 		log:             log,
 		notificationSvc: notificationSvc,
+		privacy:         privacySvc,
 	}
 }
 
@@ -206,6 +266,18 @@ func (s *FollowService) AcceptFollowRequest(followerID, followingID string) erro
 		return errors.New("follow request is not pending")
 	}
 
+	if err := s.acceptRequest(followerID, followingID); err != nil {
+		return err
+	}
+
+	// Send notification to the follower that their request was accepted
+	// s.notificationSvc.SendFollowRequestAcceptedNotification(followerID, followingID)
+
+	return nil
+}
+
+// acceptRequest accepts a request already known to be pending
+func (s *FollowService) acceptRequest(followerID, followingID string) error {
 	// Update request status
 	if err := s.repo.UpdateFollowRequestStatus(followerID, followingID, string(StatusAccepted)); err != nil {
 		return err
@@ -219,9 +291,6 @@ func (s *FollowService) AcceptFollowRequest(followerID, followingID string) erro
 	// Update follower counts
 	s.notificationSvc.UpdateFollowerCounts(followerID, followingID, s.repo)
 
-	// Send notification to the follower that their request was accepted
-	// s.notificationSvc.SendFollowRequestAcceptedNotification(followerID, followingID)
-
 	return nil
 }
 
@@ -245,6 +314,137 @@ func (s *FollowService) DeclineFollowRequest(followerID, followingID string) err
 	return s.repo.UpdateFollowRequestStatus(followerID, followingID, string(StatusDeclined))
 }
 
+// CancelFollowRequest lets the requester withdraw their pending follow request
+func (s *FollowService) CancelFollowRequest(followerID, followingID string) error {
+	if followingID == "" {
+		return errors.New("user ID is required")
+	}
+
+	request, err := s.repo.GetFollowRequest(followerID, followingID)
+	if err != nil {
+		return err
+	}
+
+	if request == nil || request.Status != string(StatusPending) {
+		return errors.New("no pending follow request to cancel")
+	}
+
+	return s.repo.UpdateFollowRequestStatus(followerID, followingID, string(StatusCancelled))
+}
+
+// AcceptFollowRequests accepts several pending requests to the user at once.
+// A nil or empty followerIDs accepts every pending request. Each accepted
+// requester gets one notification. It returns how many were accepted.
+func (s *FollowService) AcceptFollowRequests(followingID string, followerIDs []string) (int, error) {
+	requests, err := s.selectPendingRequests(followingID, followerIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	following, err := s.userRepo.GetByID(followingID)
+	if err != nil {
+		s.log.Warn("Failed to get user info for accepted notifications: %v", err)
+	}
+
+	accepted := 0
+	for _, request := range requests {
+		if err := s.acceptRequest(request.FollowerID, followingID); err != nil {
+			s.log.Error("Failed to accept follow request from %s: %v", request.FollowerID, err)
+			continue
+		}
+		accepted++
+
+		if following != nil {
+			s.notificationSvc.NotifyFollowRequestAccepted(request.FollowerID, following)
+		}
+	}
+
+	return accepted, nil
+}
+
+// DeclineFollowRequests declines several pending requests to the user at once.
+// A nil or empty followerIDs declines every pending request. As with a single
+// decline, requesters are not notified. It returns how many were declined.
+func (s *FollowService) DeclineFollowRequests(followingID string, followerIDs []string) (int, error) {
+	requests, err := s.selectPendingRequests(followingID, followerIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	declined := 0
+	for _, request := range requests {
+		if err := s.repo.UpdateFollowRequestStatus(request.FollowerID, followingID, string(StatusDeclined)); err != nil {
+			s.log.Error("Failed to decline follow request from %s: %v", request.FollowerID, err)
+			continue
+		}
+		declined++
+	}
+
+	return declined, nil
+}
+
+// AcceptAllFollowRequests accepts every pending request to the user, for when
+// their profile becomes public
+func (s *FollowService) AcceptAllFollowRequests(userID string) (int, error) {
+	return s.AcceptFollowRequests(userID, nil)
+}
+
+// selectPendingRequests gets the user's pending requests, limited to the given
+// requesters unless followerIDs is empty
+func (s *FollowService) selectPendingRequests(followingID string, followerIDs []string) ([]*FollowRequest, error) {
+	requests, err := s.repo.GetPendingFollowRequests(followingID)
+	if err != nil {
+		s.log.Error("Failed to get pending follow requests: %v", err)
+		return nil, err
+	}
+
+	if len(followerIDs) == 0 {
+		return requests, nil
+	}
+
+	wanted := make(map[string]bool, len(followerIDs))
+	for _, id := range followerIDs {
+		wanted[id] = true
+	}
+
+	var selected []*FollowRequest
+	for _, request := range requests {
+		if wanted[request.FollowerID] {
+			selected = append(selected, request)
+		}
+	}
+
+	return selected, nil
+}
+
+// ExpireFollowRequests expires requests that have been pending for longer than maxAge
+func (s *FollowService) ExpireFollowRequests(maxAge time.Duration) (int64, error) {
+	expired, err := s.repo.ExpireFollowRequests(time.Now().Add(-maxAge))
+	if err != nil {
+		s.log.Error("Failed to expire follow requests: %v", err)
+		return 0, err
+	}
+
+	if expired > 0 {
+		s.log.Info("Expired %d follow requests", expired)
+	}
+
+	return expired, nil
+}
+
+// RunRequestExpirySweeper expires old requests immediately and then on every
+// interval. It blocks, so start it in its own goroutine.
+func (s *FollowService) RunRequestExpirySweeper(maxAge, interval time.Duration) {
+	s.ExpireFollowRequests(maxAge)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.ExpireFollowRequests(maxAge)
+	}
+}
+
 // GetPendingFollowRequests retrieves all pending follow requests for a user with mutual friends count
 func (s *FollowService) GetPendingFollowRequests(userID string) ([]*FollowRequestWithUser, error) {
 	requests, err := s.repo.GetPendingFollowRequests(userID)
@@ -286,119 +486,257 @@ func (s *FollowService) IsFollowing(followerID, followingID string) (bool, error
 	return s.repo.IsFollowing(followerID, followingID)
 }
 
-// GetFollowers retrieves all followers of a user with user information
-func (s *FollowService) GetFollowers(userID string) ([]*FollowerWithUser, error) {
-	followers, err := s.repo.GetFollowers(userID)
-	if err != nil {
-		return nil, err
-	}
+// GetFollowers lists a user's followers a page at a time
+func (s *FollowService) GetFollowers(userID string, opts ConnectionListOptions) (*ConnectionPage, error) {
+	return s.getConnections(userID, true, opts)
+}
 
-	var followersWithUser []*FollowerWithUser
-	for _, follower := range followers {
-		// Get follower user info
-		user, err := s.userRepo.GetByID(follower.FollowerID)
+// GetFollowing lists the users a user follows a page at a time
+func (s *FollowService) GetFollowing(userID string, opts ConnectionListOptions) (*ConnectionPage, error) {
+	return s.getConnections(userID, false, opts)
+}
+
+// getConnections checks the viewer may see the user's connections and gets
+// one page of them
+func (s *FollowService) getConnections(userID string, followers bool, opts ConnectionListOptions) (*ConnectionPage, error) {
+	if opts.ViewerID != userID {
+		isPublic, err := s.repo.IsUserProfilePublic(userID)
 		if err != nil {
-			s.log.Warn("Failed to get follower info: %v", err)
-			continue
+			return nil, err
+		}
+		if !isPublic {
+			isFollowing, err := s.repo.IsFollowing(opts.ViewerID, userID)
+			if err != nil {
+				return nil, err
+			}
+			if !isFollowing {
+				return nil, ErrConnectionsPrivate
+			}
 		}
 
-		// Check if the user is online
-		isOnline, err := s.statusRepo.GetUserStatus(follower.FollowerID)
+		canSee, err := s.privacy.Allows(userID, opts.ViewerID, privacy.SettingSeeFollowers)
 		if err != nil {
-			s.log.Warn("Failed to get online status for user %s: %v", follower.FollowerID, err)
-			// Continue with isOnline = false as default
+			return nil, err
 		}
-
-		followerWithUser := &FollowerWithUser{
-			Follower:   *follower,
-			UserName:   fmt.Sprintf("%s %s", user.FirstName, user.LastName),
-			UserAvatar: user.Avatar,
-			IsOnline:   isOnline,
+		if !canSee {
+			return nil, ErrConnectionsPrivate
 		}
+	}
 
-		followersWithUser = append(followersWithUser, followerWithUser)
+	if opts.Sort != SortAlphabetical {
+		opts.Sort = SortRecent
+	}
+	if opts.Limit < 1 || opts.Limit > maxConnectionLimit {
+		opts.Limit = defaultConnectionLimit
 	}
+	opts.Search = strings.TrimSpace(opts.Search)
 
-	return followersWithUser, nil
-}
+	// Fetch one extra row to know whether there is another page
+	limit := opts.Limit
+	opts.Limit++
 
-// GetFollowing retrieves all users a user is following with user information
-func (s *FollowService) GetFollowing(userID string) ([]*FollowerWithUser, error) {
-	following, err := s.repo.GetFollowing(userID)
+	connections, cursors, err := s.repo.GetConnections(userID, followers, opts)
 	if err != nil {
+		s.log.Error("Failed to get connections: %v", err)
 		return nil, err
 	}
 
-	var followingWithUser []*FollowerWithUser
-	for _, follow := range following {
-		// Get following user info
-		user, err := s.userRepo.GetByID(follow.FollowingID)
-		if err != nil {
-			s.log.Warn("Failed to get following user info: %v", err)
-			continue
-		}
+	page := &ConnectionPage{Items: connections}
+	if len(connections) > limit {
+		page.Items = connections[:limit]
+		page.NextCursor = cursors[limit-1]
+	}
+	if page.Items == nil {
+		page.Items = []*FollowerWithUser{}
+	}
 
-		// Check if the user is online
-		isOnline, err := s.statusRepo.GetUserStatus(follow.FollowingID)
-		if err != nil {
-			s.log.Warn("Failed to get online status for user %s: %v", follow.FollowingID, err)
-			// Continue with isOnline = false as default
-		}
+	return page, nil
+}
 
-		followWithUser := &FollowerWithUser{
-			Follower:   *follow,
-			UserName:   fmt.Sprintf("%s %s", user.FirstName, user.LastName),
-			UserAvatar: user.Avatar,
-			IsOnline:   isOnline,
-		}
+// SetFavorite marks or unmarks a followed user as a favorite. Favorites are
+// boosted in the user's feed and, with notifyOnPost, the user is notified of
+// each of their new posts.
+func (s *FollowService) SetFavorite(userID, followingID string, favorite, notifyOnPost bool) error {
+	if followingID == "" {
+		return errors.New("user ID is required")
+	}
+
+	// Post notifications are part of the favorites tier
+	if !favorite {
+		notifyOnPost = false
+	}
 
-		followingWithUser = append(followingWithUser, followWithUser)
+	updated, err := s.repo.SetFavorite(userID, followingID, favorite, notifyOnPost)
+	if err != nil {
+		s.log.Error("Failed to set favorite: %v", err)
+		return err
+	}
+	if !updated {
+		return errors.New("you can only favorite people you follow")
 	}
 
-	return followingWithUser, nil
+	return nil
 }
 
-func (s *FollowService) GetSuggestedFriends(userID string) ([]*SuggestedFriend, error) {
-	// Get users not followed by current user
-	suggestions, err := s.repo.GetUsersNotFollowed(userID)
+// GetSuggestedFriends ranks people the user may know by mutual friends, shared
+// groups, shared events and overlapping interests, and explains each
+// suggestion. It returns one page of suggestions and the total available.
+func (s *FollowService) GetSuggestedFriends(userID string, limit, offset int) ([]*SuggestedFriend, int, error) {
+	if limit < 1 || limit > maxSuggestionLimit {
+		limit = defaultSuggestionLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	candidates, err := s.repo.GetSuggestionCandidates(userID, maxSuggestionCandidates)
 	if err != nil {
-		return nil, err
+		s.log.Error("Failed to get suggestion candidates: %v", err)
+		return nil, 0, err
 	}
 
-	var suggestedFriends []*SuggestedFriend
-	for _, suggestion := range suggestions {
-		// Get user info
-		user, err := s.userRepo.GetByID(suggestion.ID)
-		if err != nil {
-			s.log.Warn("Failed to get user info for suggestion %s: %v", suggestion.ID, err)
-			continue
+	myInterests, err := s.repo.GetProfileInterests(userID)
+	if err != nil {
+		s.log.Warn("Failed to get profile interests for user %s: %v", userID, err)
+	}
+	interestSet := splitInterests(myInterests)
+
+	suggestions := make([]*SuggestedFriend, 0, len(candidates))
+	for _, candidate := range candidates {
+		var sharedInterests []string
+		for _, interest := range splitInterestList(candidate.Interests) {
+			if interestSet[strings.ToLower(interest)] {
+				sharedInterests = append(sharedInterests, interest)
+			}
 		}
 
-		// Get mutual friends count
-		mutualCount, err := s.repo.GetMutualFollowersCount(userID, suggestion.ID)
-		if err != nil {
-			s.log.Warn("Failed to get mutual followers count for user %s: %v", suggestion.ID, err)
-			mutualCount = 0
+		suggestion := &SuggestedFriend{
+			ID:              candidate.ID,
+			FirstName:       candidate.FirstName,
+			LastName:        candidate.LastName,
+			Nickname:        candidate.Nickname,
+			Avatar:          candidate.Avatar,
+			IsPublic:        candidate.IsPublic,
+			MutualFriends:   candidate.MutualFriends,
+			SharedGroups:    candidate.SharedGroups,
+			SharedEvents:    candidate.SharedEvents,
+			SharedInterests: sharedInterests,
+			Score: candidate.MutualFriends*mutualFriendWeight +
+				candidate.SharedGroups*sharedGroupWeight +
+				candidate.SharedEvents*sharedEventWeight +
+				len(sharedInterests)*sharedInterestWeight,
+			Reason: suggestionReason(candidate, sharedInterests),
 		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	// Re-rank now that interest overlap is known; the stable sort keeps the
+	// repository's order for equal scores
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	total := len(suggestions)
+	if offset >= total {
+		return []*SuggestedFriend{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	page := suggestions[offset:end]
 
-		// Check if user is online
-		isOnline, err := s.statusRepo.GetUserStatus(suggestion.ID)
+	for _, suggestion := range page {
+		isOnline, err := s.statusRepo.GetUserStatus(userID, suggestion.ID)
 		if err != nil {
 			s.log.Warn("Failed to get online status for user %s: %v", suggestion.ID, err)
-			// Continue with isOnline = false as default
 		}
-		suggestedFriend := &SuggestedFriend{
-			ID:            user.ID,
-			FirstName:     user.FirstName,
-			LastName:      user.LastName,
-			Nickname:      user.Nickname,
-			Avatar:        user.Avatar,
-			IsPublic:      user.IsPublic,
-			MutualFriends: mutualCount,
-			IsOnline:      isOnline,
+		suggestion.IsOnline = isOnline
+	}
+
+	return page, total, nil
+}
+
+// DismissSuggestion stops suggesting a user to the current user
+func (s *FollowService) DismissSuggestion(userID, dismissedUserID string) error {
+	if dismissedUserID == "" {
+		return errors.New("user ID is required")
+	}
+	if userID == dismissedUserID {
+		return errors.New("cannot dismiss yourself")
+	}
+
+	if err := s.repo.DismissSuggestion(userID, dismissedUserID); err != nil {
+		s.log.Error("Failed to dismiss suggestion: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// suggestionReason builds a readable explanation such as
+// "3 mutual friends, both in Go Nairobi"
+func suggestionReason(candidate *SuggestionCandidate, sharedInterests []string) string {
+	var parts []string
+
+	switch {
+	case candidate.MutualFriends == 1:
+		parts = append(parts, "1 mutual friend")
+	case candidate.MutualFriends > 1:
+		parts = append(parts, fmt.Sprintf("%d mutual friends", candidate.MutualFriends))
+	}
+
+	switch {
+	case candidate.SharedGroups == 1:
+		parts = append(parts, "both in "+candidate.SharedGroupName)
+	case candidate.SharedGroups > 1:
+		parts = append(parts, fmt.Sprintf("both in %s and %d other groups", candidate.SharedGroupName, candidate.SharedGroups-1))
+	}
+
+	switch {
+	case candidate.SharedEvents == 1:
+		parts = append(parts, "both going to an event")
+	case candidate.SharedEvents > 1:
+		parts = append(parts, fmt.Sprintf("both going to %d events", candidate.SharedEvents))
+	}
+
+	if len(sharedInterests) > 0 {
+		shown := sharedInterests
+		if len(shown) > 2 {
+			shown = shown[:2]
+		}
+		parts = append(parts, "both interested in "+strings.Join(shown, " and "))
+	}
+
+	if len(parts) == 0 {
+		return "Suggested for you"
+	}
+
+	reason := strings.Join(parts, ", ")
+	return strings.ToUpper(reason[:1]) + reason[1:]
+}
+
+// splitInterestList splits comma-separated interests, dropping blanks and duplicates
+func splitInterestList(list string) []string {
+	seen := make(map[string]bool)
+	var interests []string
+	for _, interest := range strings.Split(list, ",") {
+		interest = strings.TrimSpace(interest)
+		key := strings.ToLower(interest)
+		if interest == "" || seen[key] {
+			continue
 		}
+		seen[key] = true
+		interests = append(interests, interest)
+	}
+	return interests
+}
 
-		suggestedFriends = append(suggestedFriends, suggestedFriend)
+// splitInterests returns the lowercased set of comma-separated interests
+func splitInterests(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, interest := range splitInterestList(list) {
+		set[strings.ToLower(interest)] = true
 	}
-	return suggestedFriends, nil
+	return set
 }
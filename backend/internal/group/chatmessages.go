@@ -0,0 +1,192 @@
+package group
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Athooh/social-network/internal/contentfilter"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// maxPinnedMessages is how many chat messages a group can have pinned at once
+const maxPinnedMessages = 5
+
+// getChatMessage gets a group chat message, failing if there's none
+func (s *GroupService) getChatMessage(messageID int64) (*models.GroupChatMessage, error) {
+	message, err := s.repo.GetChatMessageByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, errors.New("message not found")
+	}
+	return message, nil
+}
+
+// attachReplies adds the message each of the messages replies to, unless it
+// was deleted
+func (s *GroupService) attachReplies(messages []*models.GroupChatMessage) error {
+	parents := make(map[int64]*models.GroupChatMessage)
+	for _, message := range messages {
+		if message.ReplyToID == 0 {
+			continue
+		}
+
+		parent, ok := parents[message.ReplyToID]
+		if !ok {
+			var err error
+			if parent, err = s.repo.GetChatMessageByID(message.ReplyToID); err != nil {
+				return err
+			}
+			parents[message.ReplyToID] = parent
+		}
+		message.ReplyTo = parent
+	}
+	return nil
+}
+
+// EditChatMessage changes the content of the user's own chat message and
+// marks it edited
+func (s *GroupService) EditChatMessage(messageID int64, userID, content string) (*models.GroupChatMessage, error) {
+	message, err := s.getChatMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.UserID != userID {
+		return nil, errors.New("you can only edit your own messages")
+	}
+
+	if err := s.requirePermission(message.GroupID, userID, models.GroupActionChat, "you don't have permission to send messages in this group"); err != nil {
+		return nil, err
+	}
+
+	if content == "" {
+		return nil, errors.New("message content is required")
+	}
+
+	// Run the new text through the content filters before saving it
+	filterContent := contentfilter.Content{UserID: userID, Kind: contentfilter.KindGroupMessage, Text: content, IsEdit: true}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
+	if err := s.repo.UpdateChatMessageContent(messageID, content); err != nil {
+		return nil, err
+	}
+
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(messageID, 10))
+
+	// Reload the message to pick up the edit time
+	if message, err = s.getChatMessage(messageID); err != nil {
+		return nil, err
+	}
+	if err := s.attachReplies([]*models.GroupChatMessage{message}); err != nil {
+		return nil, err
+	}
+
+	s.notifications.NotifyGroupChatMessageEdited(message)
+
+	return message, nil
+}
+
+// DeleteChatMessage deletes a chat message. Users can delete their own
+// messages; anyone else needs permission to delete messages.
+func (s *GroupService) DeleteChatMessage(messageID int64, userID string) error {
+	message, err := s.getChatMessage(messageID)
+	if err != nil {
+		return err
+	}
+
+	if message.UserID != userID {
+		if err := s.requirePermission(message.GroupID, userID, models.GroupActionDeleteMessages, "you don't have permission to delete this message"); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.DeleteChatMessage(messageID); err != nil {
+		return err
+	}
+
+	s.notifications.NotifyGroupChatMessageDeleted(message, userID)
+
+	return nil
+}
+
+// PinChatMessage pins a chat message for the group. Members need permission to
+// pin, and a group can only have maxPinnedMessages pinned at once.
+func (s *GroupService) PinChatMessage(messageID int64, userID string) error {
+	message, err := s.getChatMessage(messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.requirePermission(message.GroupID, userID, models.GroupActionPin, "you don't have permission to pin messages"); err != nil {
+		return err
+	}
+
+	if message.IsPinned {
+		return nil
+	}
+
+	pinned, err := s.repo.GetPinnedChatMessages(message.GroupID)
+	if err != nil {
+		return err
+	}
+	if len(pinned) >= maxPinnedMessages {
+		return errors.New("a group can have at most " + strconv.Itoa(maxPinnedMessages) + " pinned messages")
+	}
+
+	if err := s.repo.PinChatMessage(messageID, userID); err != nil {
+		return err
+	}
+
+	s.notifications.NotifyGroupChatMessagePinned(message, userID, true)
+
+	return nil
+}
+
+// UnpinChatMessage unpins a chat message. Members need permission to pin.
+func (s *GroupService) UnpinChatMessage(messageID int64, userID string) error {
+	message, err := s.getChatMessage(messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.requirePermission(message.GroupID, userID, models.GroupActionPin, "you don't have permission to unpin messages"); err != nil {
+		return err
+	}
+
+	if err := s.repo.UnpinChatMessage(messageID); err != nil {
+		return err
+	}
+
+	s.notifications.NotifyGroupChatMessagePinned(message, userID, false)
+
+	return nil
+}
+
+// GetPinnedChatMessages gets the group's pinned chat messages. Only members
+// can see them.
+func (s *GroupService) GetPinnedChatMessages(groupID, userID string) ([]*models.GroupChatMessage, error) {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, errors.New("only group members can view messages")
+	}
+
+	messages, err := s.repo.GetPinnedChatMessages(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachReplies(messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
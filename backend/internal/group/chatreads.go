@@ -0,0 +1,126 @@
+package group
+
+import (
+	"errors"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// unreadContextMessages is how many already read messages are shown before the
+// first unread one when jumping to it
+const unreadContextMessages = 5
+
+// UnreadChatMessages is a page of a group's chat that starts just before the
+// first message the user hasn't read
+type UnreadChatMessages struct {
+	Messages      []*models.GroupChatMessage `json:"messages"`
+	FirstUnreadID int64                      `json:"firstUnreadId"` // 0 if everything's been read
+	UnreadCount   int                        `json:"unreadCount"`
+	Offset        int                        `json:"offset"` // of the page, to keep paging with GetGroupChatMessages
+}
+
+// MarkChatRead marks the group's chat as read up to the message, or up to the
+// latest message if it's 0, and tells the group's members
+func (s *GroupService) MarkChatRead(groupID, userID string, messageID int64) error {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !isMember {
+		return errors.New("only group members can read messages")
+	}
+
+	latestID, err := s.repo.GetLatestChatMessageID(groupID)
+	if err != nil {
+		return err
+	}
+	if messageID <= 0 || messageID > latestID {
+		messageID = latestID
+	}
+	if messageID == 0 {
+		return nil
+	}
+
+	moved, err := s.repo.MarkChatRead(groupID, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if moved {
+		s.notifications.NotifyGroupMessagesRead(groupID, userID, messageID, time.Now())
+	}
+
+	return nil
+}
+
+// GetChatReadCursors gets how far each member has read the group's chat. Only
+// members can see them.
+func (s *GroupService) GetChatReadCursors(groupID, userID string) ([]*models.GroupChatRead, error) {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, errors.New("only group members can view read receipts")
+	}
+
+	return s.repo.GetChatReadCursors(groupID)
+}
+
+// GetUnreadChatMessages gets the page of the group's chat that starts a few
+// messages before the first one the user hasn't read. If they've read
+// everything, it's the latest page.
+func (s *GroupService) GetUnreadChatMessages(groupID, userID string, limit int) (*UnreadChatMessages, error) {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, errors.New("only group members can view messages")
+	}
+
+	unread, firstUnreadID, err := s.repo.CountUnreadChatMessages(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.CountChatMessages(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Messages are paged newest first, so the first unread message is the
+	// unread-th newest. The page ends a few messages before it, if it fits,
+	// but no further back than the oldest message.
+	context := unreadContextMessages
+	if context > limit-1 {
+		context = limit - 1
+	}
+	offset := unread + context - limit
+	if offset > total-limit {
+		offset = total - limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	messages, err := s.repo.GetGroupChatMessages(groupID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachReplies(messages); err != nil {
+		return nil, err
+	}
+
+	return &UnreadChatMessages{
+		Messages:      messages,
+		FirstUnreadID: firstUnreadID,
+		UnreadCount:   unread,
+		Offset:        offset,
+	}, nil
+}
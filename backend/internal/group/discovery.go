@@ -0,0 +1,133 @@
+package group
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Orders discovered groups can be sorted in
+const (
+	DiscoverSortActivity = "activity" // latest post or chat message first
+	DiscoverSortMembers  = "members"  // most members first
+	DiscoverSortNewest   = "newest"   // most recently created first
+)
+
+// Limits on group tags
+const (
+	maxGroupTags      = 10
+	maxGroupTagLength = 30
+)
+
+// groupCategories are the categories a group can be filed under
+var groupCategories = []string{
+	"arts", "business", "community", "education", "food", "gaming", "health",
+	"music", "science", "sports", "technology", "travel", "other",
+}
+
+// DiscoverFilter narrows down and orders the groups a user discovers
+type DiscoverFilter struct {
+	Query    string // matches names, and descriptions of groups the user can see
+	Category string
+	Tag      string
+	Sort     string // one of the DiscoverSort constants
+	Limit    int
+	Offset   int
+}
+
+// isGroupCategory checks that a category is one groups can be filed under
+func isGroupCategory(category string) bool {
+	for _, known := range groupCategories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTag lowercases a tag and drops surrounding spaces and a leading #
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// normalizeTags normalizes a group's tags, dropping blanks and duplicates, and
+// checks them against the tag limits
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxGroupTagLength {
+			return nil, errors.New("tags must be at most " + strconv.Itoa(maxGroupTagLength) + " characters")
+		}
+		for _, r := range tag {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+				return nil, errors.New("tags can only contain letters, digits, - and _")
+			}
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxGroupTags {
+		return nil, errors.New("a group can have at most " + strconv.Itoa(maxGroupTags) + " tags")
+	}
+
+	return normalized, nil
+}
+
+// GetGroupCategories gets the categories a group can be filed under
+func (s *GroupService) GetGroupCategories() []string {
+	return groupCategories
+}
+
+// DiscoverGroups finds groups for the user by name, category and tag. Private
+// groups the user isn't in only show up, by name alone, if they're listed by
+// name and the user searched for it.
+func (s *GroupService) DiscoverGroups(userID string, filter DiscoverFilter) ([]*models.Group, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Category = strings.ToLower(strings.TrimSpace(filter.Category))
+	filter.Tag = normalizeTag(filter.Tag)
+
+	if filter.Category != "" && !isGroupCategory(filter.Category) {
+		return nil, errors.New("unknown group category")
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = DiscoverSortActivity
+	case DiscoverSortActivity, DiscoverSortMembers, DiscoverSortNewest:
+	default:
+		return nil, errors.New("groups can be sorted by activity, members or newest")
+	}
+
+	groups, err := s.repo.DiscoverGroups(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, group := range groups {
+		if !group.IsPublic && !group.IsMember {
+			groups[i] = &models.Group{
+				ID:           group.ID,
+				Name:         group.Name,
+				MemberStatus: group.MemberStatus,
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// RecommendGroups suggests public groups that people the user follows have
+// joined, those with the most of them first
+func (s *GroupService) RecommendGroups(userID string, limit int) ([]*models.Group, error) {
+	return s.repo.GetRecommendedGroups(userID, limit)
+}
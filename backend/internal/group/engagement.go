@@ -0,0 +1,205 @@
+package group
+
+import (
+	"errors"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/Athooh/social-network/internal/contentfilter"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// anonymousCommenter is shown in place of the author of an anonymized comment
+var anonymousCommenter = models.PostUserData{FirstName: "Former", LastName: "member"}
+
+// LikeGroupPost likes a group post for the user, or takes their like back if
+// they already liked it. It returns whether the post is now liked and its new
+// likes count. Only group members can like posts.
+func (s *GroupService) LikeGroupPost(postID int64, userID string) (bool, int64, error) {
+	post, err := s.getMemberPost(postID, userID, "only group members can like posts")
+	if err != nil {
+		return false, 0, err
+	}
+
+	isLiked, likesCount, err := s.repo.ToggleGroupPostLike(postID, userID)
+	if err != nil {
+		return false, 0, err
+	}
+
+	s.notifications.NotifyGroupPostLiked(post, userID, isLiked, likesCount)
+
+	return isLiked, likesCount, nil
+}
+
+// CreateGroupPostComment adds a comment to a group post. Members need
+// permission to comment in the group.
+func (s *GroupService) CreateGroupPostComment(postID int64, userID, content string, image *multipart.FileHeader) (*models.Comment, error) {
+	if content == "" && image == nil {
+		return nil, errors.New("comment content or image is required")
+	}
+
+	post, err := s.getGroupPost(postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requirePermission(post.GroupID, userID, models.GroupActionComment, "you don't have permission to comment in this group"); err != nil {
+		return nil, err
+	}
+
+	// Run the text through the content filters before saving anything
+	filterContent := contentfilter.Content{UserID: userID, Kind: contentfilter.KindComment, Text: content}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
+	comment := &models.Comment{
+		PostID:  postID,
+		UserID:  userID,
+		Content: content,
+	}
+
+	if image != nil {
+		imagePath, err := s.fileStore.SaveFile(image, "comments")
+		if err != nil {
+			return nil, err
+		}
+		comment.ImagePath.String = imagePath
+		comment.ImagePath.Valid = true
+	}
+
+	commentsCount, err := s.repo.CreateGroupPostComment(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(comment.ID, 10))
+
+	user, err := s.repo.GetUserBasicByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	comment.UserData = &models.PostUserData{
+		ID:        user.ID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Avatar:    user.Avatar,
+	}
+
+	go s.notifications.NotifyGroupPostCommented(post, userID, commentsCount)
+
+	return comment, nil
+}
+
+// GetGroupPostComments gets the comments on a group post. Only group members
+// can see them. Anonymized comments don't say who wrote them.
+func (s *GroupService) GetGroupPostComments(postID int64, userID string) ([]*models.Comment, error) {
+	if _, err := s.getMemberPost(postID, userID, "only group members can view comments"); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.GetGroupPostComments(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, comment := range comments {
+		if comment.IsAnonymized {
+			commenter := anonymousCommenter
+			comment.UserID = ""
+			comment.UserData = &commenter
+			continue
+		}
+
+		user, err := s.repo.GetUserBasicByID(comment.UserID)
+		if err != nil {
+			s.log.Warn("Failed to get user data for comment %d: %v", comment.ID, err)
+			continue
+		}
+		comment.UserData = &models.PostUserData{
+			ID:        user.ID,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Avatar:    user.Avatar,
+		}
+	}
+
+	return comments, nil
+}
+
+// DeleteGroupPostComment deletes a comment from a group post. Authors can
+// delete their own comments; deleting others' needs permission to delete posts.
+func (s *GroupService) DeleteGroupPostComment(commentID int64, userID string) error {
+	comment, err := s.repo.GetGroupPostComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment == nil {
+		return errors.New("comment not found")
+	}
+
+	post, err := s.getGroupPost(comment.PostID, userID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != userID || comment.IsAnonymized {
+		if err := s.requirePermission(post.GroupID, userID, models.GroupActionDeletePosts, "you don't have permission to delete this comment"); err != nil {
+			return err
+		}
+	}
+
+	commentsCount, err := s.repo.DeleteGroupPostComment(comment)
+	if err != nil {
+		return err
+	}
+
+	go s.notifications.NotifyGroupPostCommentCount(post, userID, commentsCount)
+
+	return nil
+}
+
+// getGroupPost gets an approved group post for the user, returning an error if
+// there's none. Posts hidden by moderation are only there for their author and
+// members who can delete posts.
+func (s *GroupService) getGroupPost(postID int64, userID string) (*models.GroupPost, error) {
+	post, err := s.repo.GetGroupPostByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil || post.Status != models.GroupPostApproved {
+		return nil, errors.New("post not found")
+	}
+
+	if post.IsHidden && post.UserID != userID {
+		canModerate, err := s.CanPerform(post.GroupID, userID, models.GroupActionDeletePosts)
+		if err != nil {
+			return nil, err
+		}
+		if !canModerate {
+			return nil, errors.New("post not found")
+		}
+	}
+
+	return post, nil
+}
+
+// getMemberPost gets a group post, returning an error with the given message
+// unless the user is a member of its group
+func (s *GroupService) getMemberPost(postID int64, userID, message string) (*models.GroupPost, error) {
+	post, err := s.getGroupPost(postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := s.repo.IsGroupMember(post.GroupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New(message)
+	}
+
+	return post, nil
+}
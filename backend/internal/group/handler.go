@@ -2,15 +2,31 @@ package group
 
 import (
 	"encoding/json"
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Athooh/social-network/internal/auth"
 	"github.com/Athooh/social-network/pkg/httputil"
 	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
 )
 
+// CommentResponse is a comment on a group post as sent to clients
+type CommentResponse struct {
+	ID           int64                `json:"id"`
+	PostID       int64                `json:"postId"`
+	UserID       string               `json:"userId"`
+	Content      string               `json:"content"`
+	ImageURL     string               `json:"imageUrl,omitempty"`
+	IsAnonymized bool                 `json:"isAnonymized"`
+	CreatedAt    string               `json:"createdAt"`
+	UpdatedAt    string               `json:"updatedAt"`
+	UserData     *models.PostUserData `json:"userData"`
+}
+
 // Handler handles HTTP requests for group operations
 type Handler struct {
 	service Service
@@ -188,6 +204,93 @@ func (h *Handler) GetAllGroups(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, http.StatusOK, groups)
 }
 
+// DiscoverGroups handles searching for groups by name, category and tag
+func (h *Handler) DiscoverGroups(w http.ResponseWriter, r *http.Request) {
+	// Only allow GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := DiscoverFilter{
+		Query:    query.Get("q"),
+		Category: query.Get("category"),
+		Tag:      query.Get("tag"),
+		Sort:     query.Get("sort"),
+		Limit:    10,
+	}
+
+	// Get pagination parameters
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		filter.Offset = offset
+	}
+
+	// Find groups
+	groups, err := h.service.DiscoverGroups(userID, filter)
+	if err != nil {
+		h.log.Error("Failed to discover groups: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return response
+	h.sendJSON(w, http.StatusOK, groups)
+}
+
+// GetRecommendedGroups handles getting groups recommended to the user
+func (h *Handler) GetRecommendedGroups(w http.ResponseWriter, r *http.Request) {
+	// Only allow GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit := 10
+	if parsedLimit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsedLimit > 0 {
+		limit = parsedLimit
+	}
+
+	// Get recommendations
+	groups, err := h.service.RecommendGroups(userID, limit)
+	if err != nil {
+		h.log.Error("Failed to get recommended groups: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return response
+	h.sendJSON(w, http.StatusOK, groups)
+}
+
+// GetGroupCategories handles getting the categories groups can be filed under
+func (h *Handler) GetGroupCategories(w http.ResponseWriter, r *http.Request) {
+	// Only allow GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, h.service.GetGroupCategories())
+}
+
 // UpdateGroup handles updating a group
 func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
 	// Only allow PUT method
@@ -275,6 +378,89 @@ func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, http.StatusOK, map[string]string{"message": "Group deleted successfully"})
 }
 
+// RestoreGroup handles bringing back a deleted group
+func (h *Handler) RestoreGroup(w http.ResponseWriter, r *http.Request) {
+	// Only allow POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		GroupID string `json:"groupId"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.GroupID == "" {
+		http.Error(w, "Group ID is required", http.StatusBadRequest)
+		return
+	}
+
+	// Restore group
+	if err := h.service.RestoreGroup(req.GroupID, userID); err != nil {
+		h.log.Error("Failed to restore group: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return success response
+	h.sendJSON(w, http.StatusOK, map[string]string{"message": "Group restored successfully"})
+}
+
+// TransferOwnership handles handing a group over to another admin
+func (h *Handler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	// Only allow POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		GroupID string `json:"groupId"`
+		UserID  string `json:"userId"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.GroupID == "" || req.UserID == "" {
+		http.Error(w, "Group ID and user ID are required", http.StatusBadRequest)
+		return
+	}
+
+	// Transfer ownership
+	if err := h.service.TransferOwnership(req.GroupID, userID, req.UserID); err != nil {
+		h.log.Error("Failed to transfer group ownership: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return success response
+	h.sendJSON(w, http.StatusOK, map[string]string{"message": "Ownership transferred successfully"})
+}
+
 // InviteToGroup handles inviting a user to a group
 func (h *Handler) InviteToGroup(w http.ResponseWriter, r *http.Request) {
 	// Only allow POST method
@@ -329,7 +515,8 @@ func (h *Handler) JoinGroup(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request body
 	var req struct {
-		GroupID string `json:"groupId"`
+		GroupID string   `json:"groupId"`
+		Answers []string `json:"answers"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -338,7 +525,7 @@ func (h *Handler) JoinGroup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Join group
-	if err := h.service.JoinGroup(req.GroupID, userID); err != nil {
+	if err := h.service.JoinGroup(req.GroupID, userID, req.Answers); err != nil {
 		h.log.Error("Failed to join group: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -639,6 +826,138 @@ func (h *Handler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, http.StatusOK, members)
 }
 
+// HandleBans handles banning (POST), listing the bans of (GET) and unbanning
+// (DELETE) a group's members
+func (h *Handler) HandleBans(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			GroupID   string     `json:"groupId"`
+			UserID    string     `json:"userId"`
+			Reason    string     `json:"reason"`
+			ExpiresAt *time.Time `json:"expiresAt"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.GroupID == "" || req.UserID == "" {
+			http.Error(w, "Group ID and User ID are required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.BanMember(req.GroupID, userID, req.UserID, req.Reason, req.ExpiresAt); err != nil {
+			h.log.Error("Failed to ban member: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Member banned"})
+
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		bans, err := h.service.GetGroupBans(groupID, userID)
+		if err != nil {
+			h.log.Error("Failed to get group bans: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, bans)
+
+	case http.MethodDelete:
+		groupID := r.URL.Query().Get("groupId")
+		bannedID := r.URL.Query().Get("userId")
+
+		if groupID == "" || bannedID == "" {
+			http.Error(w, "Group ID and User ID are required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.UnbanMember(groupID, userID, bannedID); err != nil {
+			h.log.Error("Failed to unban member: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Member unbanned"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleJoinQuestions handles getting (GET) and replacing (PUT) the questions
+// people asking to join a group answer
+func (h *Handler) HandleJoinQuestions(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		questions, err := h.service.GetJoinQuestions(groupID)
+		if err != nil {
+			h.log.Error("Failed to get join questions: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, questions)
+
+	case http.MethodPut:
+		var req struct {
+			GroupID   string   `json:"groupId"`
+			Questions []string `json:"questions"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.GroupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		questions, err := h.service.SetJoinQuestions(req.GroupID, userID, req.Questions)
+		if err != nil {
+			h.log.Error("Failed to set join questions: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, questions)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // CreateGroupPost handles creating a post in a group
 func (h *Handler) CreateGroupPost(w http.ResponseWriter, r *http.Request) {
 	// Only allow POST method
@@ -783,10 +1102,11 @@ func (h *Handler) DeleteGroupPost(w http.ResponseWriter, r *http.Request) {
 	h.sendJSON(w, http.StatusOK, map[string]string{"message": "Post deleted successfully"})
 }
 
-// SendChatMessage handles sending a message to a group chat
-func (h *Handler) SendChatMessage(w http.ResponseWriter, r *http.Request) {
-	// Only allow POST method
-	if r.Method != http.MethodPost {
+// GetPendingGroupPosts handles getting a group's posts that are waiting for
+// review
+func (h *Handler) GetPendingGroupPosts(w http.ResponseWriter, r *http.Request) {
+	// Only allow GET method
+	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
@@ -798,38 +1118,26 @@ func (h *Handler) SendChatMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse request body
-	var request struct {
-		GroupID string `json:"groupId"`
-		Content string `json:"content"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if request.GroupID == "" || request.Content == "" {
-		http.Error(w, "Group ID and content are required", http.StatusBadRequest)
+	groupID := r.URL.Query().Get("groupId")
+	if groupID == "" {
+		http.Error(w, "Group ID is required", http.StatusBadRequest)
 		return
 	}
 
-	// Send message
-	message, err := h.service.SendChatMessage(request.GroupID, userID, request.Content)
+	posts, err := h.service.GetPendingGroupPosts(groupID, userID)
 	if err != nil {
-		h.log.Error("Failed to send chat message: %v", err)
+		h.log.Error("Failed to get pending group posts: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Return response
-	h.sendJSON(w, http.StatusCreated, message)
+	h.sendJSON(w, http.StatusOK, posts)
 }
 
-// GetGroupChatMessages handles getting messages from a group chat
-func (h *Handler) GetGroupChatMessages(w http.ResponseWriter, r *http.Request) {
-	// Only allow GET method
-	if r.Method != http.MethodGet {
+// ReviewGroupPost handles approving or rejecting a post waiting for review
+func (h *Handler) ReviewGroupPost(w http.ResponseWriter, r *http.Request) {
+	// Only allow POST method
+	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
@@ -841,43 +1149,910 @@ func (h *Handler) GetGroupChatMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get query parameters
-	groupID := r.URL.Query().Get("groupId")
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	if groupID == "" {
-		http.Error(w, "Group ID is required", http.StatusBadRequest)
-		return
+	var req struct {
+		PostID  int64 `json:"postId"`
+		Approve bool  `json:"approve"`
 	}
 
-	limit := 50
-	offset := 0
-
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
-	if offsetStr != "" {
-		parsedOffset, err := strconv.Atoi(offsetStr)
-		if err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
+	if req.PostID == 0 {
+		http.Error(w, "Post ID is required", http.StatusBadRequest)
+		return
 	}
 
-	// Get messages
-	messages, err := h.service.GetGroupChatMessages(groupID, userID, limit, offset)
-	if err != nil {
-		h.log.Error("Failed to get group chat messages: %v", err)
+	if err := h.service.ReviewGroupPost(req.PostID, userID, req.Approve); err != nil {
+		h.log.Error("Failed to review group post: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Return response
-	h.sendJSON(w, http.StatusOK, messages)
+	message := "Post rejected"
+	if req.Approve {
+		message = "Post approved"
+	}
+	h.sendJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// HandlePin handles pinning and unpinning group posts
+func (h *Handler) HandlePin(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			PostID    int64      `json:"postId"`
+			ExpiresAt *time.Time `json:"expiresAt"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.PostID == 0 {
+			http.Error(w, "Post ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.PinGroupPost(req.PostID, userID, req.ExpiresAt); err != nil {
+			h.log.Error("Failed to pin group post: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Post pinned"})
+
+	case http.MethodDelete:
+		postID, err := strconv.ParseInt(r.URL.Query().Get("postId"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid post ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.UnpinGroupPost(postID, userID); err != nil {
+			h.log.Error("Failed to unpin group post: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Post unpinned"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleInviteLinks handles creating (POST), listing (GET) and revoking
+// (DELETE) a group's invite links
+func (h *Handler) HandleInviteLinks(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			GroupID string `json:"groupId"`
+			InviteLinkOptions
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.GroupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		link, err := h.service.CreateInviteLink(req.GroupID, userID, req.InviteLinkOptions)
+		if err != nil {
+			h.log.Error("Failed to create invite link: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusCreated, link)
+
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		links, err := h.service.GetInviteLinks(groupID, userID)
+		if err != nil {
+			h.log.Error("Failed to get invite links: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, links)
+
+	case http.MethodDelete:
+		linkID := r.URL.Query().Get("linkId")
+		if linkID == "" {
+			http.Error(w, "Link ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.RevokeInviteLink(linkID, userID); err != nil {
+			h.log.Error("Failed to revoke invite link: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Invite link revoked"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetInviteLinkUses handles getting who redeemed an invite link
+func (h *Handler) GetInviteLinkUses(w http.ResponseWriter, r *http.Request) {
+	// Only allow GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	linkID := r.URL.Query().Get("linkId")
+	if linkID == "" {
+		http.Error(w, "Link ID is required", http.StatusBadRequest)
+		return
+	}
+
+	uses, err := h.service.GetInviteLinkUses(linkID, userID)
+	if err != nil {
+		h.log.Error("Failed to get invite link uses: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, uses)
+}
+
+// RedeemInviteLink handles joining a group, or asking to, through an invite link
+func (h *Handler) RedeemInviteLink(w http.ResponseWriter, r *http.Request) {
+	// Only allow POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Token   string   `json:"token"`
+		Answers []string `json:"answers"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" {
+		http.Error(w, "Token is required", http.StatusBadRequest)
+		return
+	}
+
+	redemption, err := h.service.RedeemInviteLink(req.Token, userID, req.Answers)
+	if err != nil {
+		if errors.Is(err, ErrTooManyInviteAttempts) {
+			h.sendError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		h.log.Error("Failed to redeem invite link: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, redemption)
+}
+
+// LikeGroupPost handles liking or unliking a group post
+func (h *Handler) LikeGroupPost(w http.ResponseWriter, r *http.Request) {
+	// Only allow POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	postID, err := strconv.ParseInt(r.URL.Query().Get("postId"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid post ID", http.StatusBadRequest)
+		return
+	}
+
+	// Toggle like status
+	isLiked, likesCount, err := h.service.LikeGroupPost(postID, userID)
+	if err != nil {
+		h.log.Error("Failed to like group post: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"postId":     postID,
+		"likesCount": likesCount,
+		"isLiked":    isLiked,
+	})
+}
+
+// HandleGroupPostComments handles adding, listing and deleting the comments on
+// a group post
+func (h *Handler) HandleGroupPostComments(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		// Parse multipart form
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "Failed to parse form", http.StatusBadRequest)
+			return
+		}
+
+		postID, err := strconv.ParseInt(r.FormValue("postId"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid post ID", http.StatusBadRequest)
+			return
+		}
+
+		var image *multipart.FileHeader
+		if files := r.MultipartForm.File["image"]; len(files) > 0 {
+			image = files[0]
+		}
+
+		comment, err := h.service.CreateGroupPostComment(postID, userID, r.FormValue("content"), image)
+		if err != nil {
+			h.log.Error("Failed to comment on group post: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusCreated, toCommentResponse(comment))
+
+	case http.MethodGet:
+		postID, err := strconv.ParseInt(r.URL.Query().Get("postId"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid post ID", http.StatusBadRequest)
+			return
+		}
+
+		comments, err := h.service.GetGroupPostComments(postID, userID)
+		if err != nil {
+			h.log.Error("Failed to get group post comments: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		response := make([]CommentResponse, 0, len(comments))
+		for _, comment := range comments {
+			response = append(response, toCommentResponse(comment))
+		}
+
+		h.sendJSON(w, http.StatusOK, response)
+
+	case http.MethodDelete:
+		commentID, err := strconv.ParseInt(r.URL.Query().Get("commentId"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.DeleteGroupPostComment(commentID, userID); err != nil {
+			h.log.Error("Failed to delete group post comment: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Comment deleted successfully"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// toCommentResponse converts a comment on a group post into its response form
+func toCommentResponse(comment *models.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:           comment.ID,
+		PostID:       comment.PostID,
+		UserID:       comment.UserID,
+		Content:      comment.Content,
+		IsAnonymized: comment.IsAnonymized,
+		CreatedAt:    comment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    comment.UpdatedAt.Format(time.RFC3339),
+		UserData:     comment.UserData,
+	}
+
+	if comment.ImagePath.String != "" {
+		response.ImageURL = "/uploads/" + comment.ImagePath.String
+	}
+
+	return response
+}
+
+// SendChatMessage handles sending a message to a group chat
+func (h *Handler) SendChatMessage(w http.ResponseWriter, r *http.Request) {
+	// Only allow POST method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Parse request body
+	var request struct {
+		GroupID   string `json:"groupId"`
+		Content   string `json:"content"`
+		ReplyToID int64  `json:"replyToId"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if request.GroupID == "" || request.Content == "" {
+		http.Error(w, "Group ID and content are required", http.StatusBadRequest)
+		return
+	}
+
+	// Send message
+	message, err := h.service.SendChatMessage(request.GroupID, userID, request.Content, request.ReplyToID)
+	if err != nil {
+		h.log.Error("Failed to send chat message: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return response
+	h.sendJSON(w, http.StatusCreated, message)
+}
+
+// HandleChatMessage handles editing (PUT) and deleting (DELETE) a group chat
+// message
+func (h *Handler) HandleChatMessage(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var req struct {
+			MessageID int64  `json:"messageId"`
+			Content   string `json:"content"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.MessageID == 0 || req.Content == "" {
+			http.Error(w, "Message ID and content are required", http.StatusBadRequest)
+			return
+		}
+
+		message, err := h.service.EditChatMessage(req.MessageID, userID, req.Content)
+		if err != nil {
+			h.log.Error("Failed to edit chat message: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, message)
+
+	case http.MethodDelete:
+		messageID, err := strconv.ParseInt(r.URL.Query().Get("messageId"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.DeleteChatMessage(messageID, userID); err != nil {
+			h.log.Error("Failed to delete chat message: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Message deleted"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleChatPins handles pinning (POST), unpinning (DELETE) and listing the
+// pinned (GET) group chat messages
+func (h *Handler) HandleChatPins(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			MessageID int64 `json:"messageId"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.MessageID == 0 {
+			http.Error(w, "Message ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.PinChatMessage(req.MessageID, userID); err != nil {
+			h.log.Error("Failed to pin chat message: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Message pinned"})
+
+	case http.MethodDelete:
+		messageID, err := strconv.ParseInt(r.URL.Query().Get("messageId"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid message ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.UnpinChatMessage(messageID, userID); err != nil {
+			h.log.Error("Failed to unpin chat message: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Message unpinned"})
+
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		messages, err := h.service.GetPinnedChatMessages(groupID, userID)
+		if err != nil {
+			h.log.Error("Failed to get pinned chat messages: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, messages)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetGroupChatMessages handles getting messages from a group chat
+func (h *Handler) GetGroupChatMessages(w http.ResponseWriter, r *http.Request) {
+	// Only allow GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Get query parameters
+	groupID := r.URL.Query().Get("groupId")
+	limitStr := r.URL.Query().Get("limit")
+	offsetStr := r.URL.Query().Get("offset")
+
+	if groupID == "" {
+		http.Error(w, "Group ID is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	offset := 0
+
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	if offsetStr != "" {
+		parsedOffset, err := strconv.Atoi(offsetStr)
+		if err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	// Get messages
+	messages, err := h.service.GetGroupChatMessages(groupID, userID, limit, offset)
+	if err != nil {
+		h.log.Error("Failed to get group chat messages: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Return response
+	h.sendJSON(w, http.StatusOK, messages)
+}
+
+// GetUnreadChatMessages handles jumping to the first group chat message the
+// user hasn't read
+func (h *Handler) GetUnreadChatMessages(w http.ResponseWriter, r *http.Request) {
+	// Only allow GET method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	groupID := r.URL.Query().Get("groupId")
+	if groupID == "" {
+		http.Error(w, "Group ID is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if parsedLimit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsedLimit > 0 {
+		limit = parsedLimit
+	}
+
+	page, err := h.service.GetUnreadChatMessages(groupID, userID, limit)
+	if err != nil {
+		h.log.Error("Failed to get unread group chat messages: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, page)
+}
+
+// HandleChatRead handles marking a group's chat as read (POST) and getting how
+// far each member has read it (GET)
+func (h *Handler) HandleChatRead(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			GroupID   string `json:"groupId"`
+			MessageID int64  `json:"messageId"` // 0 for the latest message
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.GroupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.MarkChatRead(req.GroupID, userID, req.MessageID); err != nil {
+			h.log.Error("Failed to mark group chat read: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Messages marked as read"})
+
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		cursors, err := h.service.GetChatReadCursors(groupID, userID)
+		if err != nil {
+			h.log.Error("Failed to get group chat read cursors: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, cursors)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandlePermissions handles getting and changing what each of a group's roles
+// may do
+func (h *Handler) HandlePermissions(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		matrix, err := h.service.GetPermissionMatrix(groupID, userID)
+		if err != nil {
+			h.log.Error("Failed to get group permissions: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, matrix)
+
+	case http.MethodPut:
+		var req struct {
+			GroupID     string          `json:"groupId"`
+			Role        string          `json:"role"`
+			Permissions map[string]bool `json:"permissions"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.GroupID == "" || req.Role == "" {
+			http.Error(w, "Group ID and role are required", http.StatusBadRequest)
+			return
+		}
+
+		matrix, err := h.service.UpdateRolePermissions(req.GroupID, userID, req.Role, req.Permissions)
+		if err != nil {
+			h.log.Error("Failed to update group permissions: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, matrix)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleRoles handles adding and removing a group's custom roles
+func (h *Handler) HandleRoles(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			GroupID string `json:"groupId"`
+			Name    string `json:"name"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		role, err := h.service.CreateRole(req.GroupID, userID, req.Name)
+		if err != nil {
+			h.log.Error("Failed to create group role: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusCreated, role)
+
+	case http.MethodDelete:
+		groupID := r.URL.Query().Get("groupId")
+		name := r.URL.Query().Get("name")
+
+		if groupID == "" || name == "" {
+			http.Error(w, "Group ID and role name are required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.DeleteRole(groupID, userID, name); err != nil {
+			h.log.Error("Failed to delete group role: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, map[string]string{"message": "Role removed successfully"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleSettings handles getting and changing a group's settings
+func (h *Handler) HandleSettings(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		settings, err := h.service.GetGroupSettings(groupID, userID)
+		if err != nil {
+			h.log.Error("Failed to get group settings: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, settings)
+
+	case http.MethodPatch:
+		var req struct {
+			GroupID string `json:"groupId"`
+			GroupSettingsUpdate
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.GroupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		settings, err := h.service.UpdateGroupSettings(req.GroupID, userID, &req.GroupSettingsUpdate)
+		if err != nil {
+			h.log.Error("Failed to update group settings: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, settings)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleNotificationSettings handles getting (GET) and changing (PUT) how the
+// user is notified about a group
+func (h *Handler) HandleNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		groupID := r.URL.Query().Get("groupId")
+		if groupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		setting, err := h.service.GetNotificationSettings(groupID, userID)
+		if err != nil {
+			h.log.Error("Failed to get group notification settings: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, setting)
+
+	case http.MethodPut:
+		var req struct {
+			GroupID     string     `json:"groupId"`
+			Level       string     `json:"level"`
+			ChatEnabled bool       `json:"chatEnabled"`
+			MutedUntil  *time.Time `json:"mutedUntil"` // nil to unmute
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.GroupID == "" {
+			http.Error(w, "Group ID is required", http.StatusBadRequest)
+			return
+		}
+
+		setting, err := h.service.UpdateNotificationSettings(req.GroupID, userID, req.Level, req.ChatEnabled, req.MutedUntil)
+		if err != nil {
+			h.log.Error("Failed to update group notification settings: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.sendJSON(w, http.StatusOK, setting)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
 }
 
 // Helper method to send JSON responses
@@ -0,0 +1,301 @@
+package group
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+const (
+	// inviteTokenBytes is how much randomness goes into an invite link token
+	inviteTokenBytes = 32
+	// maxInviteLinkUses caps the uses a link can be created with
+	maxInviteLinkUses = 10000
+
+	// Invite link redemptions allowed per user within inviteRedeemWindow
+	inviteRedeemLimit  = 10
+	inviteRedeemWindow = 10 * time.Minute
+)
+
+// ErrTooManyInviteAttempts is returned when the user redeemed too many invite
+// links too quickly
+var ErrTooManyInviteAttempts = errors.New("too many invite link attempts, please try again later")
+
+// InviteLinkOptions shape a new invite link
+type InviteLinkOptions struct {
+	ExpiresAt        *time.Time `json:"expiresAt"` // nil for a link that doesn't expire
+	MaxUses          int        `json:"maxUses"`   // 0 for no limit
+	RequiresApproval bool       `json:"requiresApproval"`
+}
+
+// InviteRedemption is the result of redeeming an invite link
+type InviteRedemption struct {
+	GroupID string `json:"groupId"`
+	Outcome string `json:"outcome"` // joined, requested
+}
+
+// attemptLimiter allows each user a number of attempts within a time window
+type attemptLimiter struct {
+	limit     int
+	window    time.Duration
+	attempts  map[string][]time.Time
+	lastSweep time.Time // when users with no attempts in the window were last evicted
+	mu        sync.Mutex
+}
+
+// newAttemptLimiter creates a new per-user attempt limiter
+func newAttemptLimiter(limit int, window time.Duration) *attemptLimiter {
+	return &attemptLimiter{
+		limit:    limit,
+		window:   window,
+		attempts: make(map[string][]time.Time),
+	}
+}
+
+// allow records an attempt by the user, unless they already used up their
+// attempts within the window
+func (l *attemptLimiter) allow(userID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-l.window)
+
+	// Once per window, forget the users who made no attempts within it
+	if now.Sub(l.lastSweep) >= l.window {
+		for id, attempts := range l.attempts {
+			if len(attempts) == 0 || !attempts[len(attempts)-1].After(cutoff) {
+				delete(l.attempts, id)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	// Drop attempts that are outside the window
+	recent := l.attempts[userID][:0]
+	for _, at := range l.attempts[userID] {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.attempts[userID] = recent
+		return false
+	}
+
+	l.attempts[userID] = append(recent, now)
+	return true
+}
+
+// newInviteToken generates an unguessable invite link token
+func newInviteToken() (string, error) {
+	b := make([]byte, inviteTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CreateInviteLink creates a shareable link to the group. Members need
+// permission to manage invite links.
+func (s *GroupService) CreateInviteLink(groupID, userID string, options InviteLinkOptions) (*models.GroupInviteLink, error) {
+	if err := s.requirePermission(groupID, userID, models.GroupActionInviteLinks, "you don't have permission to create invite links for this group"); err != nil {
+		return nil, err
+	}
+
+	if options.ExpiresAt != nil && !options.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("invite link expiry must be in the future")
+	}
+	if options.MaxUses < 0 || options.MaxUses > maxInviteLinkUses {
+		return nil, errors.New("invite link max uses must be between 0 and 10000")
+	}
+
+	token, err := newInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &models.GroupInviteLink{
+		GroupID:          groupID,
+		Token:            token,
+		CreatedBy:        userID,
+		MaxUses:          options.MaxUses,
+		RequiresApproval: options.RequiresApproval,
+	}
+	if options.ExpiresAt != nil {
+		link.ExpiresAt = *options.ExpiresAt
+	}
+
+	if err := s.repo.CreateInviteLink(link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// GetInviteLinks gets the group's invite links. Members need permission to
+// manage invite links.
+func (s *GroupService) GetInviteLinks(groupID, userID string) ([]*models.GroupInviteLink, error) {
+	if err := s.requirePermission(groupID, userID, models.GroupActionInviteLinks, "you don't have permission to view this group's invite links"); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetGroupInviteLinks(groupID)
+}
+
+// RevokeInviteLink stops an invite link from being redeemed. The link's
+// creator can revoke it; anyone else needs permission to manage invite links.
+func (s *GroupService) RevokeInviteLink(linkID, userID string) error {
+	link, err := s.repo.GetInviteLink(linkID)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return errors.New("invite link not found")
+	}
+
+	if link.CreatedBy != userID {
+		if err := s.requirePermission(link.GroupID, userID, models.GroupActionInviteLinks, "you don't have permission to revoke this invite link"); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.RevokeInviteLink(linkID)
+}
+
+// GetInviteLinkUses gets who redeemed an invite link and whether they joined
+// or asked to. Members need permission to manage invite links.
+func (s *GroupService) GetInviteLinkUses(linkID, userID string) ([]*models.GroupInviteLinkUse, error) {
+	link, err := s.repo.GetInviteLink(linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, errors.New("invite link not found")
+	}
+
+	if err := s.requirePermission(link.GroupID, userID, models.GroupActionInviteLinks, "you don't have permission to view this invite link's history"); err != nil {
+		return nil, err
+	}
+
+	uses, err := s.repo.GetInviteLinkUses(linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, use := range uses {
+		user, err := s.repo.GetUserBasicByID(use.UserID)
+		if err != nil {
+			s.log.Warn("Failed to get user data for invite link use %d: %v", use.ID, err)
+			continue
+		}
+		use.User = user
+	}
+
+	return uses, nil
+}
+
+// RedeemInviteLink joins the user to the link's group, or asks to join it if
+// the link requires approval, in which case the answers to the group's join
+// questions are needed. A pending invitation or join request the user already
+// has is accepted by a link that doesn't. Users can only redeem
+// inviteRedeemLimit links within inviteRedeemWindow.
+func (s *GroupService) RedeemInviteLink(token, userID string, answers []string) (*InviteRedemption, error) {
+	if !s.inviteRedemptions.allow(userID) {
+		return nil, ErrTooManyInviteAttempts
+	}
+
+	link, err := s.repo.GetInviteLinkByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, errors.New("invite link not found")
+	}
+	if !link.RevokedAt.IsZero() {
+		return nil, errors.New("this invite link has been revoked")
+	}
+	if !link.ExpiresAt.IsZero() && !link.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("this invite link has expired")
+	}
+
+	group, err := s.repo.GetGroupByID(link.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if !group.ArchivedAt.IsZero() {
+		return nil, errors.New("invite link not found")
+	}
+
+	banned, err := s.isBanned(link.GroupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if banned {
+		return nil, errors.New("you are banned from this group")
+	}
+
+	existingMember, err := s.repo.GetMemberByID(link.GroupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := models.InviteLinkJoined
+	if link.RequiresApproval {
+		outcome = models.InviteLinkRequested
+	}
+
+	if existingMember != nil {
+		if existingMember.Status == "accepted" {
+			return nil, errors.New("you are already a member of this group")
+		}
+		if existingMember.Status == "pending" && outcome == models.InviteLinkRequested {
+			return nil, errors.New("you already have a pending request or invitation for this group")
+		}
+	}
+
+	var joinAnswers []*models.GroupJoinAnswer
+	if outcome == models.InviteLinkRequested {
+		if joinAnswers, err = s.joinAnswers(link.GroupID, answers); err != nil {
+			return nil, err
+		}
+	}
+
+	member := &models.GroupMember{
+		GroupID:   link.GroupID,
+		UserID:    userID,
+		Role:      models.GroupRoleMember,
+		Status:    "accepted",
+		InvitedBy: link.CreatedBy,
+	}
+	if outcome == models.InviteLinkRequested {
+		member.Status = "pending"
+		member.InvitedBy = ""
+	}
+
+	used, err := s.repo.RedeemInviteLink(link.ID, outcome, member, joinAnswers)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("this invite link is no longer valid")
+	}
+
+	redemption := &InviteRedemption{GroupID: link.GroupID, Outcome: outcome}
+
+	if outcome == models.InviteLinkRequested {
+		if err := s.notifyJoinRequest(group, userID); err != nil {
+			s.log.Error("Failed to notify about join request to group %s: %v", group.ID, err)
+		}
+		return redemption, nil
+	}
+
+	s.notifyGroupInvitationAccepted(group, userID)
+
+	return redemption, nil
+}
@@ -0,0 +1,115 @@
+package group
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Limits on join questions and their answers
+const (
+	maxJoinQuestions      = 3
+	maxJoinQuestionLength = 200
+	maxJoinAnswerLength   = 1000
+)
+
+// GetJoinQuestions gets the questions people asking to join the group answer
+func (s *GroupService) GetJoinQuestions(groupID string) ([]string, error) {
+	group, err := s.repo.GetGroupByID(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if !group.ArchivedAt.IsZero() {
+		return nil, errors.New("group not found")
+	}
+
+	return s.repo.GetJoinQuestions(groupID)
+}
+
+// SetJoinQuestions replaces the questions people asking to join the group
+// answer. An empty list stops asking them. Members need permission to edit the
+// group's settings.
+func (s *GroupService) SetJoinQuestions(groupID, userID string, questions []string) ([]string, error) {
+	if err := s.requirePermission(groupID, userID, models.GroupActionEditSettings, "you don't have permission to edit this group"); err != nil {
+		return nil, err
+	}
+
+	if len(questions) > maxJoinQuestions {
+		return nil, errors.New("a group can have at most " + strconv.Itoa(maxJoinQuestions) + " join questions")
+	}
+
+	trimmed := make([]string, 0, len(questions))
+	for _, question := range questions {
+		question = strings.TrimSpace(question)
+		if question == "" {
+			return nil, errors.New("join questions can't be empty")
+		}
+		if utf8.RuneCountInString(question) > maxJoinQuestionLength {
+			return nil, errors.New("join questions must be at most " + strconv.Itoa(maxJoinQuestionLength) + " characters")
+		}
+		trimmed = append(trimmed, question)
+	}
+
+	if err := s.repo.SetJoinQuestions(groupID, trimmed); err != nil {
+		return nil, err
+	}
+
+	return trimmed, nil
+}
+
+// joinAnswers checks there's an answer for each of the group's join questions
+// and pairs them up
+func (s *GroupService) joinAnswers(groupID string, answers []string) ([]*models.GroupJoinAnswer, error) {
+	questions, err := s.repo.GetJoinQuestions(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(answers) != len(questions) {
+		return nil, errors.New("please answer each of the group's join questions")
+	}
+
+	joinAnswers := make([]*models.GroupJoinAnswer, 0, len(questions))
+	for i, question := range questions {
+		answer := strings.TrimSpace(answers[i])
+		if answer == "" {
+			return nil, errors.New("please answer each of the group's join questions")
+		}
+		if utf8.RuneCountInString(answer) > maxJoinAnswerLength {
+			return nil, errors.New("join answers must be at most " + strconv.Itoa(maxJoinAnswerLength) + " characters")
+		}
+		joinAnswers = append(joinAnswers, &models.GroupJoinAnswer{
+			Position: i,
+			Question: question,
+			Answer:   answer,
+		})
+	}
+
+	return joinAnswers, nil
+}
+
+// requestToJoin adds the user's pending request to join the group along with
+// their answers to its join questions, and tells the group's creator
+func (s *GroupService) requestToJoin(group *models.Group, userID string, answers []*models.GroupJoinAnswer) error {
+	member := &models.GroupMember{
+		GroupID: group.ID,
+		UserID:  userID,
+		Role:    models.GroupRoleMember,
+		Status:  "pending",
+	}
+
+	if err := s.repo.AddMember(member); err != nil {
+		return err
+	}
+
+	if len(answers) > 0 {
+		if err := s.repo.SaveJoinAnswers(member.ID, answers); err != nil {
+			return err
+		}
+	}
+
+	return s.notifyJoinRequest(group, userID)
+}
@@ -0,0 +1,141 @@
+package group
+
+import (
+	"errors"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// TransferOwnership hands the group over to another of its admins, who becomes
+// its creator. Only the group's creator can transfer it; they stay an admin.
+func (s *GroupService) TransferOwnership(groupID, ownerID, newOwnerID string) error {
+	if err := s.requireCreator(groupID, ownerID, "only the group creator can transfer ownership"); err != nil {
+		return err
+	}
+
+	if newOwnerID == ownerID {
+		return errors.New("you already own this group")
+	}
+
+	role, err := s.repo.GetMemberRole(groupID, newOwnerID)
+	if err != nil {
+		return err
+	}
+	if role != models.GroupRoleAdmin {
+		return errors.New("ownership can only be transferred to an admin of the group")
+	}
+
+	if err := s.repo.TransferOwnership(groupID, newOwnerID); err != nil {
+		return err
+	}
+
+	group, err := s.repo.GetGroupByID(groupID)
+	if err != nil {
+		return err
+	}
+
+	s.notifications.NotifyGroupOwnershipTransferred(group, ownerID, newOwnerID)
+
+	return nil
+}
+
+// successorAdmin finds the admin who has been in the group longest, other than
+// the user. If there's none, the longest-standing moderator is promoted to
+// admin. It returns "" if there's no moderator either.
+func (s *GroupService) successorAdmin(group *models.Group, userID string) (string, error) {
+	adminID, err := s.repo.GetLongestStandingMember(group.ID, models.GroupRoleAdmin, userID)
+	if err != nil || adminID != "" {
+		return adminID, err
+	}
+
+	moderatorID, err := s.repo.GetLongestStandingMember(group.ID, models.GroupRoleModerator, userID)
+	if err != nil || moderatorID == "" {
+		return "", err
+	}
+
+	if err := s.repo.UpdateMemberRole(group.ID, moderatorID, models.GroupRoleAdmin); err != nil {
+		return "", err
+	}
+
+	s.notifyGroupMemberRoleUpdated(group, moderatorID, models.GroupRoleAdmin, "")
+
+	return moderatorID, nil
+}
+
+// RestoreGroup brings back a deleted group that hasn't been purged yet. Only
+// the group's creator can restore it.
+func (s *GroupService) RestoreGroup(id, userID string) error {
+	group, err := s.repo.GetGroupByID(id)
+	if err != nil {
+		return err
+	}
+
+	if group.CreatorID != userID {
+		return errors.New("only the group creator can restore the group")
+	}
+
+	if group.ArchivedAt.IsZero() {
+		return errors.New("group is not deleted")
+	}
+
+	// The purge sweeper may not have reached a group whose time is up yet
+	if time.Since(group.ArchivedAt) > s.archiveRetention {
+		return errors.New("the group can no longer be restored")
+	}
+
+	if err := s.repo.RestoreGroup(id); err != nil {
+		return err
+	}
+	group.ArchivedAt = time.Time{}
+
+	s.notifications.NotifyGroupRestored(group, userID)
+
+	return nil
+}
+
+// PurgeArchivedGroups permanently deletes groups that were archived longer ago
+// than the retention, along with their uploaded files. It returns how many
+// groups were purged.
+func (s *GroupService) PurgeArchivedGroups() (int, error) {
+	ids, err := s.repo.GetGroupsArchivedBefore(time.Now().Add(-s.archiveRetention))
+	if err != nil {
+		s.log.Error("Failed to get archived groups: %v", err)
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		files, err := s.repo.PurgeGroup(id)
+		if err != nil {
+			s.log.Error("Failed to purge group %s: %v", id, err)
+			continue
+		}
+		purged++
+
+		for _, file := range files {
+			if err := s.fileStore.DeleteFile(file); err != nil {
+				s.log.Warn("Failed to delete file %s of purged group %s: %v", file, id, err)
+			}
+		}
+	}
+
+	if purged > 0 {
+		s.log.Info("Purged %d archived groups", purged)
+	}
+
+	return purged, nil
+}
+
+// RunArchivePurgeSweeper purges archived groups immediately and then on every
+// interval. It blocks, so start it in its own goroutine.
+func (s *GroupService) RunArchivePurgeSweeper(interval time.Duration) {
+	s.PurgeArchivedGroups()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.PurgeArchivedGroups()
+	}
+}
@@ -0,0 +1,68 @@
+package group
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Athooh/social-network/internal/privacy"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// groupMentionPattern matches @all, which mentions every member of the group,
+// and @here, which mentions those who are online
+var groupMentionPattern = regexp.MustCompile(`(?i)(?:^|[^A-Za-z0-9_@])@(all|here)\b`)
+
+// chatMentions finds the members a chat message mentions, by handle or with
+// @all or @here. Its author and members who don't let the author mention them
+// are left out.
+func (s *GroupService) chatMentions(message *models.GroupChatMessage) ([]string, error) {
+	members, err := s.repo.GetGroupMembers(message.GroupID, "accepted")
+	if err != nil {
+		return nil, err
+	}
+
+	isMember := make(map[string]bool, len(members))
+	for _, member := range members {
+		isMember[member.UserID] = true
+	}
+
+	mentioned := make(map[string]bool)
+	for _, match := range groupMentionPattern.FindAllStringSubmatch(message.Content, -1) {
+		everyone := strings.EqualFold(match[1], "all")
+		for _, member := range members {
+			if everyone || s.wsHub != nil && s.wsHub.HasActiveClient(member.UserID) {
+				mentioned[member.UserID] = true
+			}
+		}
+	}
+
+	mentions, err := s.handles.ResolveMentions(message.Content)
+	if err != nil {
+		return nil, err
+	}
+	for _, mention := range mentions {
+		if isMember[mention.UserID] {
+			mentioned[mention.UserID] = true
+		}
+	}
+
+	delete(mentioned, message.UserID)
+
+	var userIDs []string
+	for _, member := range members {
+		if !mentioned[member.UserID] {
+			continue
+		}
+
+		allowed, err := s.privacy.Allows(member.UserID, message.UserID, privacy.SettingMention)
+		if err != nil {
+			s.log.Warn("Failed to check whether %s can be mentioned: %v", member.UserID, err)
+			continue
+		}
+		if allowed {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,155 @@
+package group
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// maxPinnedPosts is how many posts a group can have pinned at once
+const maxPinnedPosts = 3
+
+// newPostStatus decides the review state a user's new post in the group
+// starts in. Groups that require approval hold the posts of members who
+// can't approve posts themselves.
+func (s *GroupService) newPostStatus(groupID, userID string) (string, error) {
+	group, err := s.repo.GetGroupByID(groupID)
+	if err != nil {
+		return "", err
+	}
+	if !group.RequirePostApproval {
+		return models.GroupPostApproved, nil
+	}
+
+	canApprove, err := s.CanPerform(groupID, userID, models.GroupActionApprovePosts)
+	if err != nil {
+		return "", err
+	}
+	if canApprove {
+		return models.GroupPostApproved, nil
+	}
+
+	return models.GroupPostPending, nil
+}
+
+// GetPendingGroupPosts gets the group's posts that are waiting for review.
+// Members need permission to approve posts.
+func (s *GroupService) GetPendingGroupPosts(groupID, userID string) ([]*models.GroupPost, error) {
+	if err := s.requirePermission(groupID, userID, models.GroupActionApprovePosts, "you don't have permission to review posts"); err != nil {
+		return nil, err
+	}
+
+	posts, err := s.repo.GetPendingGroupPosts(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, post := range posts {
+		user, err := s.repo.GetUserBasicByID(post.UserID)
+		if err != nil {
+			return nil, err
+		}
+		post.User = &models.PostUserData{
+			ID:        user.ID,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Avatar:    user.Avatar,
+		}
+	}
+
+	return posts, nil
+}
+
+// ReviewGroupPost approves or rejects a post waiting for review, and tells its
+// author either way. Approved posts are then shown to the group.
+func (s *GroupService) ReviewGroupPost(postID int64, reviewerID string, approve bool) error {
+	post, err := s.repo.GetGroupPostByID(postID)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return errors.New("post not found")
+	}
+
+	if err := s.requirePermission(post.GroupID, reviewerID, models.GroupActionApprovePosts, "you don't have permission to review posts"); err != nil {
+		return err
+	}
+
+	if post.Status != models.GroupPostPending {
+		return errors.New("post is not waiting for review")
+	}
+
+	post.Status = models.GroupPostRejected
+	if approve {
+		post.Status = models.GroupPostApproved
+	}
+
+	if err := s.repo.UpdateGroupPostStatus(postID, post.Status); err != nil {
+		return err
+	}
+
+	s.notifications.NotifyGroupPostReviewed(post, reviewerID)
+	if approve {
+		s.notifyGroupPostCreated(post)
+	}
+
+	return nil
+}
+
+// PinGroupPost pins an approved post to the top of the group's posts until the
+// expiry, or until it's unpinned if there's none. Members need permission to
+// pin posts, and a group can only have maxPinnedPosts pinned at once.
+func (s *GroupService) PinGroupPost(postID int64, userID string, expiresAt *time.Time) error {
+	post, err := s.getGroupPost(postID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.requirePermission(post.GroupID, userID, models.GroupActionPin, "you don't have permission to pin posts"); err != nil {
+		return err
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return errors.New("pin expiry must be in the future")
+	}
+
+	if !post.IsPinned {
+		pinned, err := s.repo.CountPinnedGroupPosts(post.GroupID)
+		if err != nil {
+			return err
+		}
+		if pinned >= maxPinnedPosts {
+			return errors.New("a group can have at most " + strconv.Itoa(maxPinnedPosts) + " pinned posts")
+		}
+	}
+
+	if err := s.repo.PinGroupPost(postID, userID, expiresAt); err != nil {
+		return err
+	}
+
+	s.notifications.NotifyGroupPostPinned(post, userID, true)
+
+	return nil
+}
+
+// UnpinGroupPost unpins a post. Members need permission to pin posts.
+func (s *GroupService) UnpinGroupPost(postID int64, userID string) error {
+	post, err := s.getGroupPost(postID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.requirePermission(post.GroupID, userID, models.GroupActionPin, "you don't have permission to unpin posts"); err != nil {
+		return err
+	}
+
+	if err := s.repo.UnpinGroupPost(postID); err != nil {
+		return err
+	}
+
+	s.notifications.NotifyGroupPostPinned(post, userID, false)
+
+	return nil
+}
@@ -1,8 +1,11 @@
 package group
 
 import (
+	"database/sql"
+	"fmt"
 	"time"
 
+	"github.com/Athooh/social-network/internal/mute"
 	notifications "github.com/Athooh/social-network/internal/notifcations"
 	"github.com/Athooh/social-network/pkg/logger"
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
@@ -16,18 +19,65 @@ type Notifications struct {
 	wsHub            *websocket.Hub
 	log              *logger.Logger
 	notificationRepo notifications.Service
+	mutes            mute.Service
 }
 
 // NewNotifications creates a new notifications handler
-func NewNotifications(repo Repository, wsHub *websocket.Hub, log *logger.Logger, notifications notifications.Service) *Notifications {
+func NewNotifications(repo Repository, wsHub *websocket.Hub, log *logger.Logger, notifications notifications.Service, mutes mute.Service) *Notifications {
 	return &Notifications{
 		repo:             repo,
 		wsHub:            wsHub,
 		log:              log,
 		notificationRepo: notifications,
+		mutes:            mutes,
 	}
 }
 
+// filterNotified drops the users whose notification settings for the group
+// don't let the kind of notification through
+func (n *Notifications) filterNotified(groupID string, userIDs []string, kind string) []string {
+	settings, err := n.repo.GetNotificationSettings(groupID)
+	if err != nil {
+		n.log.Warn("Failed to check group notification settings: %v", err)
+		return userIDs
+	}
+	if len(settings) == 0 {
+		return userIDs
+	}
+
+	now := time.Now()
+	filtered := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if notificationAllowed(settings[userID], kind, now) {
+			filtered = append(filtered, userID)
+		}
+	}
+	return filtered
+}
+
+// memberIDs gets the IDs of the group's accepted members. Pushes that keep
+// their view of the group in sync go to all of them, whatever their
+// notification settings.
+func (n *Notifications) memberIDs(groupID string) []string {
+	members, _ := n.repo.GetGroupMembers(groupID, "accepted")
+	memberIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.UserID)
+	}
+	return memberIDs
+}
+
+// notifies reports whether the user's notification settings for the group let
+// the kind of notification through
+func (n *Notifications) notifies(groupID, userID, kind string) bool {
+	setting, err := n.repo.GetNotificationSetting(groupID, userID)
+	if err != nil {
+		n.log.Warn("Failed to check group notification settings: %v", err)
+		return true
+	}
+	return notificationAllowed(setting, kind, time.Now())
+}
+
 // NotifyGroupCreated notifies about group creation
 func (n *Notifications) NotifyGroupCreated(group *models.Group, userID string) {
 	event := events.Event{
@@ -52,9 +102,8 @@ func (n *Notifications) NotifyGroupUpdated(group *models.Group, userID string) {
 	}
 
 	// Notify all members
-	members, _ := n.repo.GetGroupMembers(group.ID, "accepted")
-	for _, member := range members {
-		n.wsHub.BroadcastToUser(member.UserID, event)
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, event)
 	}
 }
 
@@ -69,9 +118,44 @@ func (n *Notifications) NotifyGroupDeleted(group *models.Group, userID string) {
 	}
 
 	// Notify all members
-	members, _ := n.repo.GetGroupMembers(group.ID, "accepted")
-	for _, member := range members {
-		n.wsHub.BroadcastToUser(member.UserID, event)
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupRestored notifies about an archived group being restored
+func (n *Notifications) NotifyGroupRestored(group *models.Group, userID string) {
+	event := events.Event{
+		Type: "group_restored",
+		Payload: map[string]interface{}{
+			"group":  group,
+			"userID": userID,
+		},
+	}
+
+	// Notify all members
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupOwnershipTransferred notifies about the group getting a new creator
+func (n *Notifications) NotifyGroupOwnershipTransferred(group *models.Group, fromID, toID string) {
+	from, _ := n.repo.GetUserBasicByID(fromID)
+	to, _ := n.repo.GetUserBasicByID(toID)
+
+	event := events.Event{
+		Type: "group_ownership_transferred",
+		Payload: map[string]interface{}{
+			"group":         group,
+			"previousOwner": from,
+			"newOwner":      to,
+		},
+	}
+
+	// Notify all members
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, event)
 	}
 }
 
@@ -82,6 +166,11 @@ func (n *Notifications) NotifyGroupInvitation(group *models.Group, inviterID, in
 		return
 	}
 
+	// The recipient has silenced notifications from the sender
+	if n.mutes.IsMuted(inviteeID, inviterID, models.MuteTypeNotifications) {
+		return
+	}
+
 	// Create notification in database
 	if err := n.notificationRepo.CreateNotification(newNote); err != nil {
 		n.log.Error("Failed to create follow request notification: %v", err)
@@ -129,9 +218,8 @@ func (n *Notifications) NotifyGroupInvitationAccepted(group *models.Group, userI
 	}
 
 	// Notify all members
-	members, _ := n.repo.GetGroupMembers(group.ID, "accepted")
-	for _, member := range members {
-		n.wsHub.BroadcastToUser(member.UserID, event)
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, event)
 	}
 }
 
@@ -142,6 +230,12 @@ func (n *Notifications) NotifyGroupJoinRequest(group *models.Group, inviterID, i
 		return
 	}
 
+	// The recipient has silenced notifications from the sender, or about the
+	// group
+	if n.mutes.IsMuted(inviteeID, inviterID, models.MuteTypeNotifications) || !n.notifies(group.ID, inviteeID, models.GroupNotificationActivity) {
+		return
+	}
+
 	// Create notification in database
 	if err := n.notificationRepo.CreateNotification(newNote); err != nil {
 		n.log.Error("Failed to create follow request notification: %v", err)
@@ -200,10 +294,9 @@ func (n *Notifications) NotifyGroupJoinRequestAccepted(group *models.Group, user
 		},
 	}
 
-	members, _ := n.repo.GetGroupMembers(group.ID, "accepted")
-	for _, member := range members {
-		if member.UserID != userID {
-			n.wsHub.BroadcastToUser(member.UserID, memberEvent)
+	for _, memberID := range n.memberIDs(group.ID) {
+		if memberID != userID {
+			n.wsHub.BroadcastToUser(memberID, memberEvent)
 		}
 	}
 }
@@ -221,9 +314,8 @@ func (n *Notifications) NotifyGroupMemberLeft(group *models.Group, userID string
 	}
 
 	// Notify all members
-	members, _ := n.repo.GetGroupMembers(group.ID, "accepted")
-	for _, member := range members {
-		n.wsHub.BroadcastToUser(member.UserID, event)
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, event)
 	}
 }
 
@@ -243,9 +335,8 @@ func (n *Notifications) NotifyGroupMemberRoleUpdated(group *models.Group, userID
 	}
 
 	// Notify all members
-	members, _ := n.repo.GetGroupMembers(group.ID, "accepted")
-	for _, member := range members {
-		n.wsHub.BroadcastToUser(member.UserID, event)
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, event)
 	}
 }
 
@@ -274,9 +365,8 @@ func (n *Notifications) NotifyGroupMemberRemoved(group *models.Group, userID, ad
 		},
 	}
 
-	members, _ := n.repo.GetGroupMembers(group.ID, "accepted")
-	for _, member := range members {
-		n.wsHub.BroadcastToUser(member.UserID, memberEvent)
+	for _, memberID := range n.memberIDs(group.ID) {
+		n.wsHub.BroadcastToUser(memberID, memberEvent)
 	}
 }
 
@@ -289,36 +379,376 @@ func (n *Notifications) NotifyGroupPostCreated(post *models.GroupPost) {
 		},
 	}
 
-	// Notify all members
-	members, _ := n.repo.GetGroupMembers(post.GroupID, "accepted")
-	for _, member := range members {
-		n.wsHub.BroadcastToUser(member.UserID, event)
+	// Notify all members, except those who muted the author's posts
+	memberIDs := n.memberIDs(post.GroupID)
+	for _, memberID := range n.mutes.FilterMuted(memberIDs, post.UserID, models.MuteTypePosts) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupPostLiked pushes a group post's new likes count to the group's
+// members
+func (n *Notifications) NotifyGroupPostLiked(post *models.GroupPost, userID string, isLiked bool, likesCount int64) {
+	userName := "Unknown User"
+	if user, err := n.repo.GetUserBasicByID(userID); err == nil && user != nil {
+		userName = user.FirstName + " " + user.LastName
+	}
+
+	event := events.Event{
+		Type: events.PostLiked,
+		Payload: events.PostLikedPayload{
+			PostID:     post.ID,
+			UserID:     userID,
+			UserName:   userName,
+			IsLiked:    isLiked,
+			LikesCount: int(likesCount),
+		},
+	}
+
+	for _, memberID := range n.memberIDs(post.GroupID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupPostCommentCount pushes a group post's new comments count to the
+// group's members
+func (n *Notifications) NotifyGroupPostCommentCount(post *models.GroupPost, userID string, commentsCount int64) {
+	event := events.Event{
+		Type: events.CommentCountUpdate,
+		Payload: map[string]interface{}{
+			"postId":    post.ID,
+			"groupId":   post.GroupID,
+			"userId":    userID,
+			"statsType": "Comments",
+			"count":     commentsCount,
+		},
+	}
+
+	for _, memberID := range n.memberIDs(post.GroupID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupPostCommented pushes a group post's new comments count to the
+// group's members and tells the post's author who commented
+func (n *Notifications) NotifyGroupPostCommented(post *models.GroupPost, commenterID string, commentsCount int64) {
+	n.NotifyGroupPostCommentCount(post, commenterID, commentsCount)
+
+	if post.UserID == commenterID {
+		return
+	}
+
+	// The post's author has silenced notifications from the commenter, or
+	// about the group
+	if n.mutes.IsMuted(post.UserID, commenterID, models.MuteTypeNotifications) || !n.notifies(post.GroupID, post.UserID, models.GroupNotificationActivity) {
+		return
+	}
+
+	commenter, err := n.repo.GetUserBasicByID(commenterID)
+	if err != nil || commenter == nil {
+		n.log.Error("Failed to fetch commenter details: %v", err)
+		return
+	}
+	commenterName := commenter.FirstName + " " + commenter.LastName
+
+	group, err := n.repo.GetGroupByID(post.GroupID)
+	if err != nil {
+		n.log.Error("Failed to fetch group for comment notification: %v", err)
+		return
+	}
+
+	newNote := &notifications.NewNotification{
+		UserId:          post.UserID,
+		NotficationType: "comment",
+		SenderId:        sql.NullString{String: commenterID, Valid: true},
+		TargetGroupID:   sql.NullString{String: group.ID, Valid: true},
+		Message:         fmt.Sprintf("%s commented on your post in %s.", commenterName, group.Name),
+	}
+	if err := n.notificationRepo.CreateNotification(newNote); err != nil {
+		n.log.Error("Failed to create comment notification: %v", err)
+		return
+	}
+
+	// Retrieve the newly created notification to get its ID and CreatedAt
+	notifications, err := n.notificationRepo.GetNotifications(post.UserID, 1, 0)
+	if err != nil || len(notifications) == 0 {
+		n.log.Error("Failed to retrieve newly created notification: %v", err)
+		return
+	}
+	dbNotification := notifications[0]
+
+	event := events.Event{
+		Type: events.HeaderNotificationUpdate,
+		Payload: map[string]interface{}{
+			"id":            dbNotification.ID,
+			"type":          newNote.NotficationType,
+			"senderId":      commenterID,
+			"targetGroupId": group.ID,
+			"senderName":    commenterName,
+			"senderAvatar":  commenter.Avatar,
+			"message":       newNote.Message,
+			"createdAt":     dbNotification.CreatedAt.Format(time.RFC3339),
+			"isRead":        dbNotification.IsRead,
+		},
+	}
+
+	n.wsHub.BroadcastToUser(post.UserID, event)
+}
+
+// NotifyGroupPostPending tells those who review the group's posts about a post
+// waiting for review
+func (n *Notifications) NotifyGroupPostPending(post *models.GroupPost, reviewerIDs []string) {
+	event := events.Event{
+		Type: "group_post_pending",
+		Payload: map[string]interface{}{
+			"post": post,
+		},
+	}
+
+	for _, reviewerID := range n.filterNotified(post.GroupID, reviewerIDs, models.GroupNotificationActivity) {
+		if reviewerID != post.UserID {
+			n.wsHub.BroadcastToUser(reviewerID, event)
+		}
 	}
 }
 
-// NotifyGroupChatMessage notifies about a new group chat message
+// NotifyGroupPostReviewed tells a held post's author whether it was approved
+// or rejected
+func (n *Notifications) NotifyGroupPostReviewed(post *models.GroupPost, reviewerID string) {
+	if post.UserID == reviewerID || !n.notifies(post.GroupID, post.UserID, models.GroupNotificationActivity) {
+		return
+	}
+
+	group, err := n.repo.GetGroupByID(post.GroupID)
+	if err != nil {
+		n.log.Error("Failed to fetch group for post review notification: %v", err)
+		return
+	}
+
+	notificationType := "groupPostApproved"
+	message := fmt.Sprintf("Your post in %s was approved.", group.Name)
+	if post.Status == models.GroupPostRejected {
+		notificationType = "groupPostRejected"
+		message = fmt.Sprintf("Your post in %s was not approved.", group.Name)
+	}
+
+	newNote := &notifications.NewNotification{
+		UserId:          post.UserID,
+		NotficationType: notificationType,
+		SenderId:        sql.NullString{String: reviewerID, Valid: true},
+		TargetGroupID:   sql.NullString{String: group.ID, Valid: true},
+		Message:         message,
+	}
+	if err := n.notificationRepo.CreateNotification(newNote); err != nil {
+		n.log.Error("Failed to create post review notification: %v", err)
+		return
+	}
+
+	// Retrieve the newly created notification to get its ID and CreatedAt
+	notifications, err := n.notificationRepo.GetNotifications(post.UserID, 1, 0)
+	if err != nil || len(notifications) == 0 {
+		n.log.Error("Failed to retrieve newly created notification: %v", err)
+		return
+	}
+	dbNotification := notifications[0]
+
+	event := events.Event{
+		Type: events.HeaderNotificationUpdate,
+		Payload: map[string]interface{}{
+			"id":            dbNotification.ID,
+			"type":          notificationType,
+			"senderId":      reviewerID,
+			"targetGroupId": group.ID,
+			"postId":        post.ID,
+			"message":       message,
+			"createdAt":     dbNotification.CreatedAt.Format(time.RFC3339),
+			"isRead":        dbNotification.IsRead,
+		},
+	}
+
+	n.wsHub.BroadcastToUser(post.UserID, event)
+}
+
+// NotifyGroupPostPinned tells the group's members a post was pinned or unpinned
+func (n *Notifications) NotifyGroupPostPinned(post *models.GroupPost, userID string, pinned bool) {
+	event := events.Event{
+		Type: "group_post_pinned",
+		Payload: map[string]interface{}{
+			"postId":  post.ID,
+			"groupId": post.GroupID,
+			"userId":  userID,
+			"pinned":  pinned,
+		},
+	}
+
+	for _, memberID := range n.memberIDs(post.GroupID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// chatMessagePayload is how a group chat message is pushed to members
+func chatMessagePayload(message *models.GroupChatMessage) map[string]interface{} {
+	return map[string]interface{}{
+		"id":      message.ID,
+		"Content": message.Content,
+		"User": map[string]interface{}{
+			"id":        message.UserID,
+			"firstName": message.User.FirstName,
+			"avatar":    message.User.Avatar,
+		},
+		"CreatedAt": message.CreatedAt,
+		"GroupID":   message.GroupID,
+		"ReplyToID": message.ReplyToID,
+		"ReplyTo":   message.ReplyTo,
+		"IsEdited":  message.IsEdited,
+		"EditedAt":  message.EditedAt,
+	}
+}
+
+// NotifyGroupChatMessage notifies about a new group chat message. Every member
+// gets it so their chat stays in sync; Notify tells their client whether their
+// notification settings want them alerted about it.
 func (n *Notifications) NotifyGroupChatMessage(message *models.GroupChatMessage) {
+	memberIDs := n.memberIDs(message.GroupID)
+
+	alerted := make(map[string]bool, len(memberIDs))
+	for _, memberID := range n.filterNotified(message.GroupID, memberIDs, models.GroupNotificationChat) {
+		alerted[memberID] = true
+	}
+
+	// Notify all members
+	for _, memberID := range memberIDs {
+		if memberID != message.User.ID {
+			payload := chatMessagePayload(message)
+			payload["Notify"] = alerted[memberID]
+			n.wsHub.BroadcastToUser(memberID, events.Event{
+				Type:    events.GroupMessage,
+				Payload: payload,
+			})
+		}
+	}
+}
+
+// NotifyGroupChatMessageEdited pushes an edited group chat message to the
+// group's members
+func (n *Notifications) NotifyGroupChatMessageEdited(message *models.GroupChatMessage) {
+	event := events.Event{
+		Type:    events.GroupMessageEdited,
+		Payload: chatMessagePayload(message),
+	}
+
+	for _, memberID := range n.memberIDs(message.GroupID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupChatMessageDeleted tells the group's members a chat message was
+// deleted
+func (n *Notifications) NotifyGroupChatMessageDeleted(message *models.GroupChatMessage, userID string) {
 	event := events.Event{
-		Type: events.GroupMessage,
+		Type: events.GroupMessageDeleted,
 		Payload: map[string]interface{}{
-			"id":      message.ID,
-			"Content": message.Content,
-			"User": map[string]interface{}{
-				"id":        message.UserID,
-				"firstName": message.User.FirstName,
-				"avatar":    message.User.Avatar,
-			},
-			"CreatedAt": message.CreatedAt,
+			"id":        message.ID,
 			"GroupID":   message.GroupID,
+			"deletedBy": userID,
 		},
 	}
 
-	// Notify all members
-	members, _ := n.repo.GetGroupMembers(message.GroupID, "accepted")
-	for _, member := range members {
-		if member.UserID != message.User.ID {
-			n.wsHub.BroadcastToUser(member.UserID, event)
+	for _, memberID := range n.memberIDs(message.GroupID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupChatMessagePinned tells the group's members a chat message was
+// pinned or unpinned
+func (n *Notifications) NotifyGroupChatMessagePinned(message *models.GroupChatMessage, userID string, pinned bool) {
+	event := events.Event{
+		Type: events.GroupMessagePinned,
+		Payload: map[string]interface{}{
+			"id":      message.ID,
+			"GroupID": message.GroupID,
+			"userId":  userID,
+			"pinned":  pinned,
+		},
+	}
+
+	for _, memberID := range n.memberIDs(message.GroupID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupMessagesRead tells the group's members how far a member has read
+// its chat
+func (n *Notifications) NotifyGroupMessagesRead(groupID, userID string, lastReadMessageID int64, readAt time.Time) {
+	event := events.Event{
+		Type: events.GroupMessagesRead,
+		Payload: map[string]interface{}{
+			"groupId":           groupID,
+			"userId":            userID,
+			"lastReadMessageId": lastReadMessageID,
+			"readAt":            readAt.Format(time.RFC3339),
+		},
+	}
+
+	for _, memberID := range n.memberIDs(groupID) {
+		n.wsHub.BroadcastToUser(memberID, event)
+	}
+}
+
+// NotifyGroupChatMention tells members they were mentioned in a group chat
+// message
+func (n *Notifications) NotifyGroupChatMention(message *models.GroupChatMessage, userIDs []string) {
+	group, err := n.repo.GetGroupByID(message.GroupID)
+	if err != nil {
+		n.log.Error("Failed to fetch group for mention notification: %v", err)
+		return
+	}
+
+	authorName := message.User.FirstName + " " + message.User.LastName
+
+	for _, userID := range n.filterNotified(group.ID, userIDs, models.GroupNotificationMention) {
+		// The member has silenced notifications from the author
+		if n.mutes.IsMuted(userID, message.UserID, models.MuteTypeNotifications) {
+			continue
+		}
+
+		newNote := &notifications.NewNotification{
+			UserId:          userID,
+			NotficationType: "groupMention",
+			SenderId:        sql.NullString{String: message.UserID, Valid: true},
+			TargetGroupID:   sql.NullString{String: group.ID, Valid: true},
+			Message:         fmt.Sprintf("%s mentioned you in %s.", authorName, group.Name),
 		}
+		if err := n.notificationRepo.CreateNotification(newNote); err != nil {
+			n.log.Error("Failed to create mention notification: %v", err)
+			continue
+		}
+
+		// Retrieve the newly created notification to get its ID and CreatedAt
+		notifications, err := n.notificationRepo.GetNotifications(userID, 1, 0)
+		if err != nil || len(notifications) == 0 {
+			n.log.Error("Failed to retrieve newly created notification: %v", err)
+			continue
+		}
+		dbNotification := notifications[0]
+
+		event := events.Event{
+			Type: events.HeaderNotificationUpdate,
+			Payload: map[string]interface{}{
+				"id":            dbNotification.ID,
+				"type":          newNote.NotficationType,
+				"senderId":      message.UserID,
+				"targetGroupId": group.ID,
+				"messageId":     message.ID,
+				"senderName":    authorName,
+				"senderAvatar":  message.User.Avatar,
+				"message":       newNote.Message,
+				"createdAt":     dbNotification.CreatedAt.Format(time.RFC3339),
+				"isRead":        dbNotification.IsRead,
+			},
+		}
+
+		n.wsHub.BroadcastToUser(userID, event)
 	}
 }
 
@@ -0,0 +1,112 @@
+package group
+
+import (
+	"errors"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// notificationAllowed reports whether a member's notification setting for a
+// group lets a kind of notification through at the time. Members without a
+// setting get everything. Mentions get through a temporary mute, but nothing
+// gets through "none".
+func notificationAllowed(setting *models.GroupNotificationSetting, kind string, at time.Time) bool {
+	if setting == nil {
+		return true
+	}
+	if setting.Level == models.GroupNotifyNone {
+		return false
+	}
+	if kind == models.GroupNotificationMention {
+		return true
+	}
+	if setting.MutedUntil.After(at) {
+		return false
+	}
+
+	switch kind {
+	case models.GroupNotificationEvent:
+		return true
+	case models.GroupNotificationChat:
+		return setting.Level == models.GroupNotifyAll && setting.ChatEnabled
+	default:
+		return setting.Level == models.GroupNotifyAll
+	}
+}
+
+// GetNotificationSettings gets how the user is notified about the group. Only
+// members have notification settings.
+func (s *GroupService) GetNotificationSettings(groupID, userID string) (*models.GroupNotificationSetting, error) {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, errors.New("only group members have notification settings")
+	}
+
+	setting, err := s.repo.GetNotificationSetting(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		setting = &models.GroupNotificationSetting{
+			ID:          groupID + ":" + userID,
+			GroupID:     groupID,
+			UserID:      userID,
+			Level:       models.GroupNotifyAll,
+			ChatEnabled: true,
+		}
+	}
+
+	return setting, nil
+}
+
+// UpdateNotificationSettings changes how the user is notified about the group:
+// about everything, only mentions and events, or nothing, whether they're
+// notified about chat messages, and until when they've muted the group, if at
+// all
+func (s *GroupService) UpdateNotificationSettings(groupID, userID, level string, chatEnabled bool, mutedUntil *time.Time) (*models.GroupNotificationSetting, error) {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMember {
+		return nil, errors.New("only group members have notification settings")
+	}
+
+	switch level {
+	case models.GroupNotifyAll, models.GroupNotifyMentions, models.GroupNotifyNone:
+	default:
+		return nil, errors.New("notification level must be all, mentions or none")
+	}
+
+	if mutedUntil != nil && !mutedUntil.After(time.Now()) {
+		return nil, errors.New("mute expiry must be in the future")
+	}
+
+	setting := &models.GroupNotificationSetting{
+		GroupID:     groupID,
+		UserID:      userID,
+		Level:       level,
+		ChatEnabled: chatEnabled,
+	}
+	if mutedUntil != nil {
+		setting.MutedUntil = *mutedUntil
+	}
+
+	if err := s.repo.SaveNotificationSetting(setting); err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// FilterNotified drops the users whose notification settings for the group
+// don't let the kind of notification through
+func (s *GroupService) FilterNotified(groupID string, userIDs []string, kind string) []string {
+	return s.notifications.filterNotified(groupID, userIDs, kind)
+}
@@ -0,0 +1,326 @@
+package group
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+const maxRoleNameLength = 30
+
+// groupActions lists every action whose permission is set per role, in the
+// order they're shown
+var groupActions = []string{
+	models.GroupActionPost,
+	models.GroupActionComment,
+	models.GroupActionChat,
+	models.GroupActionCreateEvent,
+	models.GroupActionManageEvents,
+	models.GroupActionInvite,
+	models.GroupActionInviteLinks,
+	models.GroupActionApproveJoin,
+	models.GroupActionApprovePosts,
+	models.GroupActionPin,
+	models.GroupActionDeletePosts,
+	models.GroupActionDeleteMessages,
+	models.GroupActionRemoveMember,
+	models.GroupActionEditSettings,
+}
+
+// builtInRoles lists the roles every group has, from most to least powerful
+var builtInRoles = []string{models.GroupRoleAdmin, models.GroupRoleModerator, models.GroupRoleMember}
+
+// defaultPermissions is what each built-in role may do until the group's
+// creator changes it. Custom roles start out like members.
+var defaultPermissions = map[string]map[string]bool{
+	models.GroupRoleAdmin: {
+		models.GroupActionPost:           true,
+		models.GroupActionComment:        true,
+		models.GroupActionChat:           true,
+		models.GroupActionCreateEvent:    true,
+		models.GroupActionManageEvents:   true,
+		models.GroupActionInvite:         true,
+		models.GroupActionInviteLinks:    true,
+		models.GroupActionApproveJoin:    true,
+		models.GroupActionApprovePosts:   true,
+		models.GroupActionPin:            true,
+		models.GroupActionDeletePosts:    true,
+		models.GroupActionDeleteMessages: true,
+		models.GroupActionRemoveMember:   true,
+		models.GroupActionEditSettings:   true,
+	},
+	models.GroupRoleModerator: {
+		models.GroupActionPost:           true,
+		models.GroupActionComment:        true,
+		models.GroupActionChat:           true,
+		models.GroupActionCreateEvent:    true,
+		models.GroupActionInvite:         true,
+		models.GroupActionApprovePosts:   true,
+		models.GroupActionPin:            true,
+		models.GroupActionDeletePosts:    true,
+		models.GroupActionDeleteMessages: true,
+	},
+	models.GroupRoleMember: {
+		models.GroupActionPost:        true,
+		models.GroupActionComment:     true,
+		models.GroupActionChat:        true,
+		models.GroupActionCreateEvent: true,
+		models.GroupActionInvite:      true,
+	},
+}
+
+// RolePermissions is what one of a group's roles may do
+type RolePermissions struct {
+	Name        string          `json:"name"`
+	BuiltIn     bool            `json:"builtIn"`
+	Permissions map[string]bool `json:"permissions"` // by action
+}
+
+// PermissionMatrix is what each of a group's roles may do
+type PermissionMatrix struct {
+	Actions []string           `json:"actions"`
+	Roles   []*RolePermissions `json:"roles"`
+}
+
+// isGroupAction checks that an action is one permissions are set for
+func isGroupAction(action string) bool {
+	for _, known := range groupActions {
+		if action == known {
+			return true
+		}
+	}
+	return false
+}
+
+// isBuiltInRole checks whether a role is one every group has
+func isBuiltInRole(role string) bool {
+	_, ok := defaultPermissions[role]
+	return ok
+}
+
+// rolePermits decides whether a role may perform an action, given the group's
+// override for it, if any
+func rolePermits(role, action string, override *models.GroupRolePermission) bool {
+	if override != nil {
+		return override.Allowed
+	}
+	if defaults, ok := defaultPermissions[role]; ok {
+		return defaults[action]
+	}
+	return defaultPermissions[models.GroupRoleMember][action]
+}
+
+// CanPerform checks whether the user may perform the action in the group. The
+// group's creator may do everything; other accepted members may do what their
+// role allows.
+func (s *GroupService) CanPerform(groupID, userID, action string) (bool, error) {
+	if !isGroupAction(action) {
+		return false, errors.New("unknown group action")
+	}
+
+	creatorID, err := s.repo.GetGroupCreatorID(groupID)
+	if err != nil {
+		return false, err
+	}
+	if creatorID == "" {
+		return false, errors.New("group not found")
+	}
+	if creatorID == userID {
+		return true, nil
+	}
+
+	role, err := s.repo.GetMemberRole(groupID, userID)
+	if err != nil || role == "" {
+		return false, err
+	}
+
+	override, err := s.repo.GetRolePermission(groupID, role, action)
+	if err != nil {
+		return false, err
+	}
+
+	return rolePermits(role, action, override), nil
+}
+
+// GetPermissionMatrix gets what each of the group's roles may do. Only
+// members can see it.
+func (s *GroupService) GetPermissionMatrix(groupID, userID string) (*PermissionMatrix, error) {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("only group members can view role permissions")
+	}
+
+	customRoles, err := s.repo.GetGroupRoles(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.repo.GetRolePermissions(groupID)
+	if err != nil {
+		return nil, err
+	}
+	overrideFor := make(map[string]*models.GroupRolePermission, len(overrides))
+	for _, override := range overrides {
+		overrideFor[strings.ToLower(override.Role)+":"+override.Action] = override
+	}
+
+	matrix := &PermissionMatrix{Actions: groupActions}
+	addRole := func(name string, builtIn bool) {
+		permissions := make(map[string]bool, len(groupActions))
+		for _, action := range groupActions {
+			permissions[action] = rolePermits(name, action, overrideFor[strings.ToLower(name)+":"+action])
+		}
+		matrix.Roles = append(matrix.Roles, &RolePermissions{Name: name, BuiltIn: builtIn, Permissions: permissions})
+	}
+
+	for _, role := range builtInRoles {
+		addRole(role, true)
+	}
+	for _, role := range customRoles {
+		addRole(role.Name, false)
+	}
+
+	return matrix, nil
+}
+
+// UpdateRolePermissions changes what a role may do in the group, by action.
+// Only the group's creator can change permissions.
+func (s *GroupService) UpdateRolePermissions(groupID, userID, role string, permissions map[string]bool) (*PermissionMatrix, error) {
+	if err := s.requireCreator(groupID, userID, "only the group creator can change role permissions"); err != nil {
+		return nil, err
+	}
+
+	name, err := s.resolveRole(groupID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	for action := range permissions {
+		if !isGroupAction(action) {
+			return nil, errors.New("unknown group action: " + action)
+		}
+	}
+
+	if err := s.repo.SetRolePermissions(groupID, name, permissions); err != nil {
+		return nil, err
+	}
+
+	return s.GetPermissionMatrix(groupID, userID)
+}
+
+// CreateRole adds a custom role to the group. Only the group's creator can add
+// roles.
+func (s *GroupService) CreateRole(groupID, userID, name string) (*models.GroupRole, error) {
+	if err := s.requireCreator(groupID, userID, "only the group creator can add roles"); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxRoleNameLength {
+		return nil, errors.New("role names must be 1 to 30 characters")
+	}
+
+	if _, err := s.resolveRole(groupID, name); err == nil {
+		return nil, errors.New("this group already has a role with that name")
+	}
+
+	role := &models.GroupRole{GroupID: groupID, Name: name}
+	if err := s.repo.CreateGroupRole(role); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// DeleteRole removes a custom role from the group. Members who had it become
+// plain members. Only the group's creator can remove roles.
+func (s *GroupService) DeleteRole(groupID, userID, name string) error {
+	if err := s.requireCreator(groupID, userID, "only the group creator can remove roles"); err != nil {
+		return err
+	}
+
+	role, err := s.resolveRole(groupID, name)
+	if err != nil {
+		return err
+	}
+	if isBuiltInRole(role) {
+		return errors.New("built-in roles can't be removed")
+	}
+
+	return s.repo.DeleteGroupRole(groupID, role)
+}
+
+// requirePermission returns an error with the given message unless the user
+// may perform the action in the group
+func (s *GroupService) requirePermission(groupID, userID, action, message string) error {
+	allowed, err := s.CanPerform(groupID, userID, action)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// membersWhoCan gets the group's accepted members who may perform the action
+func (s *GroupService) membersWhoCan(groupID, action string) ([]string, error) {
+	members, err := s.repo.GetGroupMembers(groupID, "accepted")
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	for _, member := range members {
+		allowed, err := s.CanPerform(groupID, member.UserID, action)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// requireCreator returns an error with the given message unless the user
+// created the group
+func (s *GroupService) requireCreator(groupID, userID, message string) error {
+	creatorID, err := s.repo.GetGroupCreatorID(groupID)
+	if err != nil {
+		return err
+	}
+	if creatorID == "" {
+		return errors.New("group not found")
+	}
+	if creatorID != userID {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// resolveRole finds the group's role with the given name, ignoring case, and
+// returns its name as stored
+func (s *GroupService) resolveRole(groupID, name string) (string, error) {
+	if isBuiltInRole(strings.ToLower(name)) {
+		return strings.ToLower(name), nil
+	}
+
+	roles, err := s.repo.GetGroupRoles(groupID)
+	if err != nil {
+		return "", err
+	}
+	for _, role := range roles {
+		if strings.EqualFold(role.Name, name) {
+			return role.Name, nil
+		}
+	}
+
+	return "", errors.New("invalid role")
+}
@@ -0,0 +1,148 @@
+package group
+
+import (
+	"testing"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// allowedByDefault is what each role may do without overrides. Custom roles
+// start out like members.
+var allowedByDefault = map[string][]string{
+	models.GroupRoleAdmin: groupActions,
+	models.GroupRoleModerator: {
+		models.GroupActionPost,
+		models.GroupActionComment,
+		models.GroupActionChat,
+		models.GroupActionCreateEvent,
+		models.GroupActionInvite,
+		models.GroupActionApprovePosts,
+		models.GroupActionPin,
+		models.GroupActionDeletePosts,
+		models.GroupActionDeleteMessages,
+	},
+	models.GroupRoleMember: {
+		models.GroupActionPost,
+		models.GroupActionComment,
+		models.GroupActionChat,
+		models.GroupActionCreateEvent,
+		models.GroupActionInvite,
+	},
+	"Greeter": {
+		models.GroupActionPost,
+		models.GroupActionComment,
+		models.GroupActionChat,
+		models.GroupActionCreateEvent,
+		models.GroupActionInvite,
+	},
+}
+
+func TestRolePermits(t *testing.T) {
+	for role, allowedActions := range allowedByDefault {
+		allowed := make(map[string]bool, len(allowedActions))
+		for _, action := range allowedActions {
+			allowed[action] = true
+		}
+
+		for _, action := range groupActions {
+			tests := []struct {
+				name     string
+				override *models.GroupRolePermission
+				want     bool
+			}{
+				{"default", nil, allowed[action]},
+				{"allowed by override", &models.GroupRolePermission{Role: role, Action: action, Allowed: true}, true},
+				{"denied by override", &models.GroupRolePermission{Role: role, Action: action, Allowed: false}, false},
+			}
+
+			for _, tt := range tests {
+				t.Run(role+"/"+action+"/"+tt.name, func(t *testing.T) {
+					if got := rolePermits(role, action, tt.override); got != tt.want {
+						t.Errorf("rolePermits(%q, %q) = %v, want %v", role, action, got, tt.want)
+					}
+				})
+			}
+		}
+	}
+}
+
+// fakePermissionRepo answers the queries CanPerform makes from memory. Like
+// the SQLite repository, it only gives the role of accepted members.
+type fakePermissionRepo struct {
+	Repository
+	creatorID string
+	members   map[string]*models.GroupMember         // by user ID
+	overrides map[string]*models.GroupRolePermission // by "<role>:<action>"
+}
+
+func (r *fakePermissionRepo) GetGroupCreatorID(groupID string) (string, error) {
+	return r.creatorID, nil
+}
+
+func (r *fakePermissionRepo) GetMemberRole(groupID, userID string) (string, error) {
+	member, ok := r.members[userID]
+	if !ok || member.Status != "accepted" {
+		return "", nil
+	}
+	return member.Role, nil
+}
+
+func (r *fakePermissionRepo) GetRolePermission(groupID, role, action string) (*models.GroupRolePermission, error) {
+	return r.overrides[role+":"+action], nil
+}
+
+func TestCanPerform(t *testing.T) {
+	repo := &fakePermissionRepo{
+		creatorID: "creator",
+		members: map[string]*models.GroupMember{
+			"creator":   {UserID: "creator", Role: models.GroupRoleMember, Status: "accepted"},
+			"admin":     {UserID: "admin", Role: models.GroupRoleAdmin, Status: "accepted"},
+			"member":    {UserID: "member", Role: models.GroupRoleMember, Status: "accepted"},
+			"pending":   {UserID: "pending", Role: models.GroupRoleMember, Status: "pending"},
+			"moderator": {UserID: "moderator", Role: models.GroupRoleModerator, Status: "accepted"},
+		},
+		overrides: map[string]*models.GroupRolePermission{
+			models.GroupRoleModerator + ":" + models.GroupActionPin: {
+				Role: models.GroupRoleModerator, Action: models.GroupActionPin, Allowed: false,
+			},
+		},
+	}
+	s := &GroupService{repo: repo}
+
+	tests := []struct {
+		name   string
+		userID string
+		action string
+		want   bool
+	}{
+		{"creator may do everything", "creator", models.GroupActionEditSettings, true},
+		{"creator isn't bound by their role", "creator", models.GroupActionRemoveMember, true},
+		{"admin by default", "admin", models.GroupActionEditSettings, true},
+		{"member by default", "member", models.GroupActionPost, true},
+		{"member lacking permission", "member", models.GroupActionDeletePosts, false},
+		{"override denies moderator", "moderator", models.GroupActionPin, false},
+		{"non-member", "stranger", models.GroupActionPost, false},
+		{"pending member", "pending", models.GroupActionPost, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.CanPerform("group", tt.userID, tt.action)
+			if err != nil {
+				t.Fatalf("CanPerform returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CanPerform(%q, %q) = %v, want %v", tt.userID, tt.action, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.CanPerform("group", "creator", "launch_rockets"); err == nil {
+		t.Error("CanPerform with an unknown action returned no error")
+	}
+
+	repo.creatorID = ""
+	if _, err := s.CanPerform("group", "member", models.GroupActionPost); err == nil {
+		t.Error("CanPerform in a missing group returned no error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
@@ -18,10 +19,22 @@ type Repository interface {
 	GetGroupsByUserID(userID, viewerID string) ([]*models.Group, error)
 	GetAllGroups(userid string, limit, offset int) ([]*models.Group, error)
 	UpdateGroup(group *models.Group) error
-	DeleteGroup(id string) error
-	DeleteMembers(groupID string) error
 	GetGroupMemberCount(groupID string) (int, error)
 
+	// Group lifecycle operations
+	TransferOwnership(groupID, userID string) error
+	GetLongestStandingMember(groupID, role, excludeUserID string) (string, error)
+	ArchiveGroup(id string) error
+	RestoreGroup(id string) error
+	GetGroupsArchivedBefore(cutoff time.Time) ([]string, error)
+	PurgeGroup(id string) ([]string, error)
+
+	// Group discovery operations
+	GetGroupTags(groupID string) ([]string, error)
+	SetGroupTags(groupID string, tags []string) error
+	DiscoverGroups(userID string, filter DiscoverFilter) ([]*models.Group, error)
+	GetRecommendedGroups(userID string, limit int) ([]*models.Group, error)
+
 	// Group membership operations
 	AddMember(member *models.GroupMember) error
 	GetMemberByID(groupID, userID string) (*models.GroupMember, error)
@@ -33,15 +46,75 @@ type Repository interface {
 	IsGroupMember(groupID, userID string) (bool, error)
 	GetMemberRole(groupID, userID string) (string, error)
 
+	// Ban and join question operations
+	BanUser(ban *models.GroupBan) error
+	UnbanUser(groupID, userID string) error
+	GetActiveBan(groupID, userID string) (*models.GroupBan, error)
+	GetActiveBans(groupID string) ([]*models.GroupBan, error)
+	GetJoinQuestions(groupID string) ([]string, error)
+	SetJoinQuestions(groupID string, questions []string) error
+	SaveJoinAnswers(memberID string, answers []*models.GroupJoinAnswer) error
+	GetJoinAnswers(memberID string) ([]*models.GroupJoinAnswer, error)
+
+	// Invite link operations
+	CreateInviteLink(link *models.GroupInviteLink) error
+	GetInviteLink(id string) (*models.GroupInviteLink, error)
+	GetInviteLinkByToken(token string) (*models.GroupInviteLink, error)
+	GetGroupInviteLinks(groupID string) ([]*models.GroupInviteLink, error)
+	RevokeInviteLink(id string) error
+	RedeemInviteLink(linkID, outcome string, member *models.GroupMember, answers []*models.GroupJoinAnswer) (bool, error)
+	GetInviteLinkUses(linkID string) ([]*models.GroupInviteLinkUse, error)
+
+	// Role and permission operations
+	GetGroupCreatorID(groupID string) (string, error)
+	GetGroupRoles(groupID string) ([]*models.GroupRole, error)
+	CreateGroupRole(role *models.GroupRole) error
+	DeleteGroupRole(groupID, name string) error
+	GetRolePermissions(groupID string) ([]*models.GroupRolePermission, error)
+	GetRolePermission(groupID, role, action string) (*models.GroupRolePermission, error)
+	SetRolePermissions(groupID, role string, permissions map[string]bool) error
+
 	// Group posts operations
 	CreateGroupPost(post *models.GroupPost) error
 	GetGroupPosts(groupID string, currentUserID string, limit, offset int) ([]*models.GroupPost, error)
 	GetGroupPostByID(id int64) (*models.GroupPost, error)
 	DeleteGroupPost(id int64) error
 
+	// Group post review and pinning operations
+	GetPendingGroupPosts(groupID string) ([]*models.GroupPost, error)
+	UpdateGroupPostStatus(id int64, status string) error
+	PinGroupPost(id int64, userID string, expiresAt *time.Time) error
+	UnpinGroupPost(id int64) error
+	CountPinnedGroupPosts(groupID string) (int, error)
+
+	// Group post likes and comments operations
+	ToggleGroupPostLike(postID int64, userID string) (bool, int64, error)
+	CreateGroupPostComment(comment *models.Comment) (int64, error)
+	GetGroupPostComments(postID int64) ([]*models.Comment, error)
+	GetGroupPostComment(id int64) (*models.Comment, error)
+	DeleteGroupPostComment(comment *models.Comment) (int64, error)
+	AnonymizeMemberComments(groupID, userID string) error
+
 	// Group chat operations
 	AddChatMessage(message *models.GroupChatMessage) error
 	GetGroupChatMessages(groupID string, limit, offset int) ([]*models.GroupChatMessage, error)
+	MarkChatRead(groupID, userID string, messageID int64) (bool, error)
+	GetLatestChatMessageID(groupID string) (int64, error)
+	GetChatReadCursors(groupID string) ([]*models.GroupChatRead, error)
+	GetUnreadChatCounts(userID string) (map[string]int, error)
+	CountUnreadChatMessages(groupID, userID string) (int, int64, error)
+	CountChatMessages(groupID string) (int, error)
+	GetChatMessageByID(id int64) (*models.GroupChatMessage, error)
+	UpdateChatMessageContent(id int64, content string) error
+	DeleteChatMessage(id int64) error
+	PinChatMessage(id int64, userID string) error
+	UnpinChatMessage(id int64) error
+	GetPinnedChatMessages(groupID string) ([]*models.GroupChatMessage, error)
+
+	// Notification setting operations
+	GetNotificationSetting(groupID, userID string) (*models.GroupNotificationSetting, error)
+	GetNotificationSettings(groupID string) (map[string]*models.GroupNotificationSetting, error)
+	SaveNotificationSetting(setting *models.GroupNotificationSetting) error
 
 	// User data operations
 	GetUserBasicByID(userID string) (*models.UserBasic, error)
@@ -97,7 +170,7 @@ func (r *SQLiteRepository) CreateGroup(group *models.Group) error {
 		ID:        uuid.New().String(),
 		GroupID:   group.ID,
 		UserID:    group.CreatorID,
-		Role:      "admin",
+		Role:      models.GroupRoleAdmin,
 		Status:    "accepted",
 		CreatedAt: now,
 		UpdatedAt: now,
@@ -120,13 +193,15 @@ func (r *SQLiteRepository) CreateGroup(group *models.Group) error {
 func (r *SQLiteRepository) GetGroupByID(id string) (*models.Group, error) {
 	query := `
 		SELECT id, name, description, creator_id, banner_path, profile_pic_path, 
-		       is_public, created_at, updated_at
+		       is_public, anonymize_removed_comments, require_post_approval, category, listed_by_name,
+		       archived_at, created_at, updated_at
 		FROM groups
 		WHERE id = ?
 	`
 
 	var group models.Group
 	var bannerPath, profilePicPath sql.NullString
+	var archivedAt sql.NullTime
 
 	err := r.db.QueryRow(query, id).Scan(
 		&group.ID,
@@ -136,6 +211,11 @@ func (r *SQLiteRepository) GetGroupByID(id string) (*models.Group, error) {
 		&bannerPath,
 		&profilePicPath,
 		&group.IsPublic,
+		&group.AnonymizeRemovedComments,
+		&group.RequirePostApproval,
+		&group.Category,
+		&group.ListedByName,
+		&archivedAt,
 		&group.CreatedAt,
 		&group.UpdatedAt,
 	)
@@ -148,6 +228,7 @@ func (r *SQLiteRepository) GetGroupByID(id string) (*models.Group, error) {
 
 	group.BannerPath = bannerPath
 	group.ProfilePicPath = profilePicPath
+	group.ArchivedAt = archivedAt.Time
 
 	// Get member count
 	count, err := r.GetGroupMemberCount(id)
@@ -169,6 +250,12 @@ func (r *SQLiteRepository) GetGroupByID(id string) (*models.Group, error) {
 	}
 	group.Members = members
 
+	tags, err := r.GetGroupTags(group.ID)
+	if err != nil {
+		return nil, err
+	}
+	group.Tags = tags
+
 	return &group, nil
 }
 
@@ -179,7 +266,7 @@ func (r *SQLiteRepository) GetGroupsByUserID(userID, viewerID string) ([]*models
 		       g.is_public, g.created_at, g.updated_at, gm.role, gm.status
 		FROM groups g
 		JOIN group_members gm ON g.id = gm.group_id
-		WHERE gm.user_id = ? AND gm.status = 'accepted'
+		WHERE gm.user_id = ? AND gm.status = 'accepted' AND g.archived_at IS NULL
 		ORDER BY g.created_at DESC
 	`
 
@@ -253,17 +340,21 @@ func (r *SQLiteRepository) GetGroupsByUserID(userID, viewerID string) ([]*models
 	return groups, nil
 }
 
-// GetAllGroups retrieves all groups with pagination
+// GetAllGroups retrieves the groups the user can see with pagination: public
+// groups, groups they're a member of and private groups listed by name
 func (r *SQLiteRepository) GetAllGroups(userid string, limit, offset int) ([]*models.Group, error) {
 	query := `
-		SELECT id, name, description, creator_id, banner_path, profile_pic_path, 
-		       is_public, created_at, updated_at
-		FROM groups
-		ORDER BY created_at DESC
+		SELECT g.id, g.name, g.description, g.creator_id, g.banner_path, g.profile_pic_path,
+		       g.is_public, g.created_at, g.updated_at, COALESCE(m.status, '')
+		FROM groups g
+		LEFT JOIN group_members m ON m.group_id = g.id AND m.user_id = ?
+		WHERE g.archived_at IS NULL
+			AND (g.is_public = 1 OR m.status = 'accepted' OR g.listed_by_name = 1)
+		ORDER BY g.created_at DESC
 		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.Query(query, limit, offset)
+	rows, err := r.db.Query(query, userid, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get groups: %w", err)
 	}
@@ -285,10 +376,18 @@ func (r *SQLiteRepository) GetAllGroups(userid string, limit, offset int) ([]*mo
 			&group.IsPublic,
 			&group.CreatedAt,
 			&group.UpdatedAt,
+			&group.MemberStatus,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan group row: %w", err)
 		}
+		group.IsMember = group.MemberStatus == "accepted"
+
+		// Private groups the user isn't in are listed by name alone
+		if !group.IsPublic && !group.IsMember {
+			groups = append(groups, &group)
+			continue
+		}
 
 		group.BannerPath = bannerPath
 		group.ProfilePicPath = profilePicPath
@@ -313,14 +412,6 @@ func (r *SQLiteRepository) GetAllGroups(userid string, limit, offset int) ([]*mo
 		}
 		group.Members = members
 
-		member, err := r.GetMemberByID(group.ID, userid)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get member info: %w", err)
-		}
-		if member != nil && member.Status == "accepted" {
-			group.IsMember = true
-		}
-
 		groups = append(groups, &group)
 	}
 
@@ -338,7 +429,8 @@ func (r *SQLiteRepository) UpdateGroup(group *models.Group) error {
 	query := `
 		UPDATE groups
 		SET name = ?, description = ?, banner_path = ?, profile_pic_path = ?, 
-		    is_public = ?, updated_at = ?
+		    is_public = ?, anonymize_removed_comments = ?, require_post_approval = ?, category = ?,
+		    listed_by_name = ?, updated_at = ?
 		WHERE id = ?
 	`
 
@@ -349,6 +441,10 @@ func (r *SQLiteRepository) UpdateGroup(group *models.Group) error {
 		group.BannerPath,
 		group.ProfilePicPath,
 		group.IsPublic,
+		group.AnonymizeRemovedComments,
+		group.RequirePostApproval,
+		group.Category,
+		group.ListedByName,
 		group.UpdatedAt,
 		group.ID,
 	)
@@ -359,67 +455,420 @@ func (r *SQLiteRepository) UpdateGroup(group *models.Group) error {
 	return nil
 }
 
-// DeleteGroup deletes a group and all its members
-func (r *SQLiteRepository) DeleteGroup(id string) error {
-	// Get all members to update their group counts
-	members, err := r.GetGroupMembers(id, "accepted")
+// GetGroupTags gets a group's tags in alphabetical order
+func (r *SQLiteRepository) GetGroupTags(groupID string) ([]string, error) {
+	rows, err := r.db.Query("SELECT tag FROM group_tags WHERE group_id = ? ORDER BY tag", groupID)
 	if err != nil {
-		return fmt.Errorf("failed to get group members: %w", err)
+		return nil, fmt.Errorf("failed to get group tags: %w", err)
 	}
+	defer rows.Close()
 
-	// Start a transaction
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan group tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// SetGroupTags replaces a group's tags
+func (r *SQLiteRepository) SetGroupTags(groupID string, tags []string) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer tx.Rollback() // Rollback if there's an error
+	defer tx.Rollback()
 
-	// Delete the group
-	_, err = tx.Exec("DELETE FROM groups WHERE id = ?", id)
-	if err != nil {
-		return fmt.Errorf("failed to delete group: %w", err)
+	if _, err := tx.Exec("DELETE FROM group_tags WHERE group_id = ?", groupID); err != nil {
+		return fmt.Errorf("failed to clear group tags: %w", err)
 	}
 
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
+	for _, tag := range tags {
+		_, err := tx.Exec(
+			"INSERT INTO group_tags (id, group_id, tag, created_at) VALUES (?, ?, ?, ?)",
+			groupID+":"+tag, groupID, tag, time.Now(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add group tag: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	// Delete all members from the group
-	err = r.DeleteMembers(id)
+	return nil
+}
+
+// discoverSorts maps each DiscoverFilter sort to its ORDER BY clause
+var discoverSorts = map[string]string{
+	DiscoverSortActivity: "last_activity DESC, g.created_at DESC",
+	DiscoverSortMembers:  "member_count DESC, g.created_at DESC",
+	DiscoverSortNewest:   "g.created_at DESC",
+}
+
+// DiscoverGroups finds the groups a user can discover. Public groups and the
+// user's own groups match every filter; private groups that are listed by
+// name only match a name search without category or tag filters, and come
+// back with just their ID, name and visibility.
+func (r *SQLiteRepository) DiscoverGroups(userID string, filter DiscoverFilter) ([]*models.Group, error) {
+	orderBy, ok := discoverSorts[filter.Sort]
+	if !ok {
+		orderBy = discoverSorts[DiscoverSortActivity]
+	}
+
+	query := `
+		SELECT g.id, g.name, g.description, g.creator_id, g.banner_path, g.profile_pic_path,
+		       g.is_public, g.category, g.created_at, g.updated_at,
+		       COALESCE(m.status, '') AS member_status,
+		       (SELECT COUNT(*) FROM group_members gm
+		        WHERE gm.group_id = g.id AND gm.status = 'accepted') AS member_count,
+		       MAX(
+		           g.created_at,
+		           COALESCE((SELECT MAX(gp.created_at) FROM group_posts gp WHERE gp.group_id = g.id), ''),
+		           COALESCE((SELECT MAX(gc.created_at) FROM group_chat_messages gc WHERE gc.group_id = g.id), '')
+		       ) AS last_activity
+		FROM groups g
+		LEFT JOIN group_members m ON m.group_id = g.id AND m.user_id = ?
+		WHERE g.archived_at IS NULL AND ((
+			(g.is_public = 1 OR m.status = 'accepted')
+			AND (? = '' OR g.category = ?)
+			AND (? = '' OR EXISTS (SELECT 1 FROM group_tags t WHERE t.group_id = g.id AND t.tag = ?))
+			AND (? = '' OR g.name LIKE ? OR g.description LIKE ?)
+		) OR (
+			g.listed_by_name = 1 AND ? <> '' AND ? = '' AND ? = '' AND g.name LIKE ?
+		))
+		ORDER BY ` + orderBy + `
+		LIMIT ? OFFSET ?
+	`
+
+	pattern := "%" + filter.Query + "%"
+	rows, err := r.db.Query(query,
+		userID,
+		filter.Category, filter.Category,
+		filter.Tag, filter.Tag,
+		filter.Query, pattern, pattern,
+		filter.Query, filter.Category, filter.Tag, pattern,
+		filter.Limit, filter.Offset,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to delete group members: %w", err)
+		return nil, fmt.Errorf("failed to discover groups: %w", err)
 	}
+	defer rows.Close()
 
-	// Update group counts for all members
-	for _, member := range members {
-		_, err = r.UpdateUserGroupCount(member.UserID, false)
+	var groups []*models.Group
+	for rows.Next() {
+		var group models.Group
+		var lastActivity interface{}
+
+		err := rows.Scan(
+			&group.ID,
+			&group.Name,
+			&group.Description,
+			&group.CreatorID,
+			&group.BannerPath,
+			&group.ProfilePicPath,
+			&group.IsPublic,
+			&group.Category,
+			&group.CreatedAt,
+			&group.UpdatedAt,
+			&group.MemberStatus,
+			&group.MemberCount,
+			&lastActivity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group row: %w", err)
+		}
+		group.IsMember = group.MemberStatus == "accepted"
+
+		groups = append(groups, &group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group rows: %w", err)
+	}
+
+	for _, group := range groups {
+		if !group.IsPublic && !group.IsMember {
+			continue
+		}
+		if err := r.fillDiscoveryDetails(group); err != nil {
+			return nil, err
+		}
+	}
+
+	return groups, nil
+}
+
+// GetRecommendedGroups gets public groups the user hasn't joined or asked to
+// join, ranked by how many of their members the user follows. Groups none of
+// the user's followings have joined aren't recommended.
+func (r *SQLiteRepository) GetRecommendedGroups(userID string, limit int) ([]*models.Group, error) {
+	query := `
+		SELECT g.id, g.name, g.description, g.creator_id, g.banner_path, g.profile_pic_path,
+		       g.is_public, g.category, g.created_at, g.updated_at,
+		       COUNT(*) AS followed_members,
+		       (SELECT COUNT(*) FROM group_members gm
+		        WHERE gm.group_id = g.id AND gm.status = 'accepted') AS member_count
+		FROM groups g
+		JOIN group_members m ON m.group_id = g.id AND m.status = 'accepted'
+		JOIN followers f ON f.following_id = m.user_id AND f.follower_id = ?
+		WHERE g.is_public = 1 AND g.archived_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM group_members own WHERE own.group_id = g.id AND own.user_id = ?)
+		GROUP BY g.id
+		ORDER BY followed_members DESC, member_count DESC, g.created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, userID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommended groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*models.Group
+	for rows.Next() {
+		var group models.Group
+
+		err := rows.Scan(
+			&group.ID,
+			&group.Name,
+			&group.Description,
+			&group.CreatorID,
+			&group.BannerPath,
+			&group.ProfilePicPath,
+			&group.IsPublic,
+			&group.Category,
+			&group.CreatedAt,
+			&group.UpdatedAt,
+			&group.FollowedMembers,
+			&group.MemberCount,
+		)
 		if err != nil {
-			return fmt.Errorf("failed to update user group count for user %s: %w", member.UserID, err)
+			return nil, fmt.Errorf("failed to scan group row: %w", err)
+		}
+
+		groups = append(groups, &group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group rows: %w", err)
+	}
+
+	for _, group := range groups {
+		if err := r.fillDiscoveryDetails(group); err != nil {
+			return nil, err
+		}
+	}
+
+	return groups, nil
+}
+
+// fillDiscoveryDetails adds a discovered group's tags, creator and the time of
+// its latest post or chat message
+func (r *SQLiteRepository) fillDiscoveryDetails(group *models.Group) error {
+	tags, err := r.GetGroupTags(group.ID)
+	if err != nil {
+		return err
+	}
+	group.Tags = tags
+
+	creator, err := r.GetUserBasicByID(group.CreatorID)
+	if err != nil {
+		return fmt.Errorf("failed to get creator info: %w", err)
+	}
+	group.Creator = creator
+
+	group.LastActivityAt = group.CreatedAt
+	for _, query := range []string{
+		"SELECT created_at FROM group_posts WHERE group_id = ? ORDER BY created_at DESC LIMIT 1",
+		"SELECT created_at FROM group_chat_messages WHERE group_id = ? ORDER BY created_at DESC LIMIT 1",
+	} {
+		var latest time.Time
+		err := r.db.QueryRow(query, group.ID).Scan(&latest)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get group activity: %w", err)
+		}
+		if latest.After(group.LastActivityAt) {
+			group.LastActivityAt = latest
 		}
 	}
 
 	return nil
 }
 
-func (r *SQLiteRepository) DeleteMembers(groupID string) error {
-	// Start a transaction
-	tx, err := r.db.Begin()
+// TransferOwnership makes the user the group's creator
+func (r *SQLiteRepository) TransferOwnership(groupID, userID string) error {
+	_, err := r.db.Exec("UPDATE groups SET creator_id = ?, updated_at = ? WHERE id = ?", userID, time.Now(), groupID)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to transfer group ownership: %w", err)
+	}
+	return nil
+}
+
+// GetLongestStandingMember gets the accepted member with the role who joined
+// the group first, leaving out excludeUserID. It returns "" if there's none.
+func (r *SQLiteRepository) GetLongestStandingMember(groupID, role, excludeUserID string) (string, error) {
+	var userID string
+	err := r.db.QueryRow(`
+		SELECT user_id FROM group_members
+		WHERE group_id = ? AND role = ? AND status = 'accepted' AND user_id <> ?
+		ORDER BY created_at ASC
+		LIMIT 1
+	`, groupID, role, excludeUserID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", nil
 	}
-	// Delete all members from the group
-	_, err = tx.Exec("DELETE FROM group_members WHERE group_id = ?", groupID)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete group members: %w", err)
+		return "", fmt.Errorf("failed to get longest-standing member: %w", err)
 	}
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	return userID, nil
+}
+
+// ArchiveGroup soft-deletes a group, hiding it until it's restored or purged
+func (r *SQLiteRepository) ArchiveGroup(id string) error {
+	_, err := r.db.Exec("UPDATE groups SET archived_at = ? WHERE id = ? AND archived_at IS NULL", time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to archive group: %w", err)
+	}
+	return nil
+}
+
+// RestoreGroup brings back an archived group
+func (r *SQLiteRepository) RestoreGroup(id string) error {
+	_, err := r.db.Exec("UPDATE groups SET archived_at = NULL, updated_at = ? WHERE id = ?", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to restore group: %w", err)
 	}
 	return nil
 }
+
+// GetGroupsArchivedBefore gets the IDs of groups archived before the cutoff
+func (r *SQLiteRepository) GetGroupsArchivedBefore(cutoff time.Time) ([]string, error) {
+	rows, err := r.db.Query("SELECT id FROM groups WHERE archived_at IS NOT NULL AND archived_at <= ?", cutoff.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived groups: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan archived group: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating archived groups: %w", err)
+	}
+
+	return ids, nil
+}
+
+// groupFileQueries select every uploaded file that belongs to a group
+var groupFileQueries = []string{
+	"SELECT banner_path FROM groups WHERE id = ?1",
+	"SELECT profile_pic_path FROM groups WHERE id = ?1",
+	"SELECT image_path FROM group_posts WHERE group_id = ?1",
+	"SELECT video_path FROM group_posts WHERE group_id = ?1",
+	"SELECT c.image_path FROM comments c JOIN group_posts gp ON gp.id = c.post_id WHERE gp.group_id = ?1",
+	"SELECT banner_path FROM group_events WHERE group_id = ?1",
+}
+
+// groupPurgeStatements delete everything that belongs to a group, the group
+// itself last
+var groupPurgeStatements = []string{
+	"DELETE FROM comments WHERE post_id IN (SELECT id FROM group_posts WHERE group_id = ?1)",
+	"DELETE FROM post_likes WHERE post_id IN (SELECT id FROM group_posts WHERE group_id = ?1)",
+	"DELETE FROM bookmarks WHERE post_id IN (SELECT id FROM group_posts WHERE group_id = ?1)",
+	"DELETE FROM post_impressions WHERE post_id IN (SELECT id FROM group_posts WHERE group_id = ?1)",
+	"DELETE FROM group_posts WHERE group_id = ?1",
+	"DELETE FROM event_responses WHERE event_id IN (SELECT id FROM group_events WHERE group_id = ?1)",
+	"DELETE FROM group_events WHERE group_id = ?1",
+	"DELETE FROM group_chat_messages WHERE group_id = ?1",
+	"DELETE FROM group_chat_reads WHERE group_id = ?1",
+	"DELETE FROM group_notification_settings WHERE group_id = ?1",
+	"DELETE FROM notifications WHERE target_group_id = ?1",
+	"DELETE FROM group_tags WHERE group_id = ?1",
+	"DELETE FROM group_role_permissions WHERE group_id = ?1",
+	"DELETE FROM group_roles WHERE group_id = ?1",
+	"DELETE FROM group_invite_link_uses WHERE link_id IN (SELECT id FROM group_invite_links WHERE group_id = ?1)",
+	"DELETE FROM group_invite_links WHERE group_id = ?1",
+	"DELETE FROM group_bans WHERE group_id = ?1",
+	"DELETE FROM group_join_questions WHERE group_id = ?1",
+	"DELETE FROM group_join_answers WHERE member_id IN (SELECT id FROM group_members WHERE group_id = ?1)",
+	"DELETE FROM group_members WHERE group_id = ?1",
+	"DELETE FROM groups WHERE id = ?1",
+}
+
+// PurgeGroup permanently deletes a group with its posts, chat, events,
+// notifications and members. It returns the paths of the group's uploaded
+// files, which the caller should remove.
+func (r *SQLiteRepository) PurgeGroup(id string) ([]string, error) {
+	// Get all members to update their group counts
+	members, err := r.GetGroupMembers(id, "accepted")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var files []string
+	for _, query := range groupFileQueries {
+		rows, err := tx.Query(query, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group files: %w", err)
+		}
+		for rows.Next() {
+			var path sql.NullString
+			if err := rows.Scan(&path); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan group file: %w", err)
+			}
+			if path.String != "" {
+				files = append(files, path.String)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating group files: %w", err)
+		}
+	}
+
+	for _, statement := range groupPurgeStatements {
+		if _, err := tx.Exec(statement, id); err != nil {
+			return nil, fmt.Errorf("failed to purge group: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Update group counts for all members
+	for _, member := range members {
+		if _, err := r.UpdateUserGroupCount(member.UserID, false); err != nil {
+			return nil, fmt.Errorf("failed to update user group count for user %s: %w", member.UserID, err)
+		}
+	}
+
+	return files, nil
+}
+
 // GetGroupMemberCount gets the count of accepted members in a group
 func (r *SQLiteRepository) GetGroupMemberCount(groupID string) (int, error) {
 	query := `
@@ -715,6 +1164,20 @@ func (r *SQLiteRepository) RemoveMember(groupID, userID string) error {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
+	// Delete their answers to the join questions
+	_, err = tx.Exec("DELETE FROM group_join_answers WHERE member_id = ?", member.ID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to remove join answers: %w", err)
+	}
+
+	// Delete their notification settings for the group
+	_, err = tx.Exec("DELETE FROM group_notification_settings WHERE group_id = ? AND user_id = ?", groupID, userID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to remove notification settings: %w", err)
+	}
+
 	// Delete the member
 	_, err = tx.Exec(
 		"DELETE FROM group_members WHERE group_id = ? AND user_id = ?",
@@ -746,8 +1209,9 @@ func (r *SQLiteRepository) RemoveMember(groupID, userID string) error {
 func (r *SQLiteRepository) IsGroupMember(groupID, userID string) (bool, error) {
 	query := `
 		SELECT COUNT(*) 
-		FROM group_members 
-		WHERE group_id = ? AND user_id = ? AND status = 'accepted'
+		FROM group_members gm
+		JOIN groups g ON g.id = gm.group_id AND g.archived_at IS NULL
+		WHERE gm.group_id = ? AND gm.user_id = ? AND gm.status = 'accepted'
 	`
 
 	var count int
@@ -762,9 +1226,10 @@ func (r *SQLiteRepository) IsGroupMember(groupID, userID string) (bool, error) {
 // GetMemberRole gets a member's role in a group
 func (r *SQLiteRepository) GetMemberRole(groupID, userID string) (string, error) {
 	query := `
-		SELECT role 
-		FROM group_members 
-		WHERE group_id = ? AND user_id = ? AND status = 'accepted'
+		SELECT gm.role 
+		FROM group_members gm
+		JOIN groups g ON g.id = gm.group_id AND g.archived_at IS NULL
+		WHERE gm.group_id = ? AND gm.user_id = ? AND gm.status = 'accepted'
 	`
 
 	var role string
@@ -792,10 +1257,14 @@ func (r *SQLiteRepository) CreateGroupPost(post *models.GroupPost) error {
 
 	query := `
 		INSERT INTO group_posts (
-			id, group_id, user_id, content, image_path, video_path, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			id, group_id, user_id, content, image_path, video_path, status, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
+	if post.Status == "" {
+		post.Status = models.GroupPostApproved
+	}
+
 	_, err = r.db.Exec(
 		query,
 		post.ID,
@@ -804,6 +1273,7 @@ func (r *SQLiteRepository) CreateGroupPost(post *models.GroupPost) error {
 		post.Content,
 		post.ImagePath,
 		post.VideoPath,
+		post.Status,
 		post.CreatedAt,
 		post.UpdatedAt,
 	)
@@ -814,20 +1284,68 @@ func (r *SQLiteRepository) CreateGroupPost(post *models.GroupPost) error {
 	return nil
 }
 
-// GetGroupPosts gets all posts in a group with pagination
+// groupPostColumns are the group_posts columns scanGroupPost reads, from a
+// query that aliases the table as gp
+const groupPostColumns = `
+	gp.id, gp.group_id, gp.user_id, gp.content, gp.image_path, gp.video_path,
+	gp.likes_count, gp.comments_count, gp.status, gp.pinned_at, gp.pin_expires_at,
+	gp.pinned_by, gp.is_hidden, gp.created_at, gp.updated_at`
+
+// scanGroupPost reads the groupPostColumns of a row, followed by any extra
+// destinations, and works out whether the post is pinned as of now
+func scanGroupPost(row interface{ Scan(...interface{}) error }, now time.Time, extra ...interface{}) (*models.GroupPost, error) {
+	var post models.GroupPost
+	var imagePath, videoPath, pinnedBy sql.NullString
+	var pinnedAt, pinExpiresAt sql.NullTime
+
+	dest := []interface{}{
+		&post.ID,
+		&post.GroupID,
+		&post.UserID,
+		&post.Content,
+		&imagePath,
+		&videoPath,
+		&post.LikesCount,
+		&post.CommentsCount,
+		&post.Status,
+		&pinnedAt,
+		&pinExpiresAt,
+		&pinnedBy,
+		&post.IsHidden,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	post.ImagePath = imagePath
+	post.VideoPath = videoPath
+	post.PinnedBy = pinnedBy.String
+	post.PinnedAt = pinnedAt.Time
+	post.PinExpiresAt = pinExpiresAt.Time
+	post.IsPinned = pinnedAt.Valid && (!pinExpiresAt.Valid || pinExpiresAt.Time.After(now))
+
+	return &post, nil
+}
+
+// GetGroupPosts gets the approved posts in a group with pagination. Pinned
+// posts come first, most recently pinned first, until their pin expires.
 func (r *SQLiteRepository) GetGroupPosts(groupID string, currentUserID string, limit, offset int) ([]*models.GroupPost, error) {
 	query := `
-        SELECT gp.id, gp.group_id, gp.user_id, gp.content, gp.image_path, gp.video_path, 
-               gp.likes_count, gp.comments_count, gp.created_at, gp.updated_at,
+        SELECT ` + groupPostColumns + `,
                CASE WHEN pl.user_id IS NOT NULL THEN 1 ELSE 0 END as is_liked
         FROM group_posts gp
         LEFT JOIN post_likes pl ON pl.post_id = gp.id AND pl.user_id = ?
-        WHERE gp.group_id = ?
-        ORDER BY gp.created_at DESC
+        WHERE gp.group_id = ? AND gp.is_hidden = 0 AND gp.status = ?
+        ORDER BY CASE WHEN gp.pinned_at IS NOT NULL AND (gp.pin_expires_at IS NULL OR gp.pin_expires_at > ?)
+                      THEN gp.pinned_at END DESC,
+                 gp.created_at DESC
         LIMIT ? OFFSET ?
     `
 
-	rows, err := r.db.Query(query, currentUserID, groupID, limit, offset)
+	now := time.Now()
+	rows, err := r.db.Query(query, currentUserID, groupID, models.GroupPostApproved, now.UTC(), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group posts: %w", err)
 	}
@@ -836,29 +1354,11 @@ func (r *SQLiteRepository) GetGroupPosts(groupID string, currentUserID string, l
 	var posts []*models.GroupPost
 
 	for rows.Next() {
-		var post models.GroupPost
-		var imagePath, videoPath sql.NullString
 		var isLiked bool
-
-		err := rows.Scan(
-			&post.ID,
-			&post.GroupID,
-			&post.UserID,
-			&post.Content,
-			&imagePath,
-			&videoPath,
-			&post.LikesCount,
-			&post.CommentsCount,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-			&isLiked,
-		)
+		post, err := scanGroupPost(rows, now, &isLiked)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan post row: %w", err)
 		}
-
-		post.ImagePath = imagePath
-		post.VideoPath = videoPath
 		post.Isliked = isLiked
 
 		// Get user data
@@ -874,7 +1374,7 @@ func (r *SQLiteRepository) GetGroupPosts(groupID string, currentUserID string, l
 			Avatar:    userData.Avatar,
 		}
 
-		posts = append(posts, &post)
+		posts = append(posts, post)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -887,35 +1387,18 @@ func (r *SQLiteRepository) GetGroupPosts(groupID string, currentUserID string, l
 // GetGroupPostByID gets a post by ID
 func (r *SQLiteRepository) GetGroupPostByID(id int64) (*models.GroupPost, error) {
 	query := `
-		SELECT id, group_id, user_id, content, image_path, video_path, likes_count, comments_count, created_at, updated_at
-		FROM group_posts
-		WHERE id = ?
+		SELECT ` + groupPostColumns + `
+		FROM group_posts gp
+		WHERE gp.id = ?
 	`
 
-	var post models.GroupPost
-	var imagePath, videoPath sql.NullString
-
-	err := r.db.QueryRow(query, id).Scan(
-		&post.ID,
-		&post.GroupID,
-		&post.UserID,
-		&post.Content,
-		&imagePath,
-		&videoPath,
-		&post.LikesCount,
-		&post.CommentsCount,
-		&post.CreatedAt,
-		&post.UpdatedAt,
-	)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("post not found: %w", err)
-		}
-		return nil, fmt.Errorf("failed to get post: %w", err)
-	}
-
-	post.ImagePath = imagePath
-	post.VideoPath = videoPath
+	post, err := scanGroupPost(r.db.QueryRow(query, id), time.Now())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("post not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
 
 	// Get user data
 	userData, err := r.GetUserBasicByID(post.UserID)
@@ -930,16 +1413,319 @@ func (r *SQLiteRepository) GetGroupPostByID(id int64) (*models.GroupPost, error)
 		Avatar:    userData.Avatar,
 	}
 
-	return &post, nil
+	return post, nil
+}
+
+// GetPendingGroupPosts gets the posts in a group that are waiting for review,
+// oldest first
+func (r *SQLiteRepository) GetPendingGroupPosts(groupID string) ([]*models.GroupPost, error) {
+	query := `
+		SELECT ` + groupPostColumns + `
+		FROM group_posts gp
+		WHERE gp.group_id = ? AND gp.status = ?
+		ORDER BY gp.created_at ASC
+	`
+
+	rows, err := r.db.Query(query, groupID, models.GroupPostPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending posts: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var posts []*models.GroupPost
+	for rows.Next() {
+		post, err := scanGroupPost(rows, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan post row: %w", err)
+		}
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating post rows: %w", err)
+	}
+
+	return posts, nil
+}
+
+// UpdateGroupPostStatus sets a post's review state
+func (r *SQLiteRepository) UpdateGroupPostStatus(id int64, status string) error {
+	_, err := r.db.Exec("UPDATE group_posts SET status = ?, updated_at = ? WHERE id = ?", status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update post status: %w", err)
+	}
+
+	return nil
+}
+
+// PinGroupPost pins a post to the top of its group until the expiry, or until
+// it's unpinned if there's none
+func (r *SQLiteRepository) PinGroupPost(id int64, userID string, expiresAt *time.Time) error {
+	var expires interface{}
+	if expiresAt != nil {
+		expires = expiresAt.UTC()
+	}
+
+	_, err := r.db.Exec(
+		"UPDATE group_posts SET pinned_at = ?, pin_expires_at = ?, pinned_by = ? WHERE id = ?",
+		time.Now().UTC(), expires, userID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to pin post: %w", err)
+	}
+
+	return nil
+}
+
+// UnpinGroupPost unpins a post
+func (r *SQLiteRepository) UnpinGroupPost(id int64) error {
+	_, err := r.db.Exec("UPDATE group_posts SET pinned_at = NULL, pin_expires_at = NULL, pinned_by = NULL WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to unpin post: %w", err)
+	}
+
+	return nil
 }
 
-// DeleteGroupPost deletes a post
+// CountPinnedGroupPosts counts a group's posts whose pin hasn't expired
+func (r *SQLiteRepository) CountPinnedGroupPosts(groupID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM group_posts
+		WHERE group_id = ? AND pinned_at IS NOT NULL AND is_hidden = 0 AND (pin_expires_at IS NULL OR pin_expires_at > ?)
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, groupID, time.Now().UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pinned posts: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteGroupPost deletes a post with its comments, likes and bookmarks
 func (r *SQLiteRepository) DeleteGroupPost(id int64) error {
-	_, err := r.db.Exec("DELETE FROM group_posts WHERE id = ?", id)
+	tx, err := r.db.Begin()
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM comments WHERE post_id = ?",
+		"DELETE FROM post_likes WHERE post_id = ?",
+		"DELETE FROM bookmarks WHERE post_id = ?",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("failed to delete post data: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM group_posts WHERE id = ?", id); err != nil {
 		return fmt.Errorf("failed to delete post: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ToggleGroupPostLike likes a group post for the user, or takes their like
+// back if they already liked it. It returns whether the post is now liked and
+// its new likes count.
+func (r *SQLiteRepository) ToggleGroupPostLike(postID int64, userID string) (bool, int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM post_likes WHERE post_id = ? AND user_id = ?", postID, userID)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to remove like: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to remove like: %w", err)
+	}
+
+	isLiked := removed == 0
+	if isLiked {
+		if _, err := tx.Exec("INSERT INTO post_likes (post_id, user_id) VALUES (?, ?)", postID, userID); err != nil {
+			return false, 0, fmt.Errorf("failed to add like: %w", err)
+		}
+		_, err = tx.Exec("UPDATE group_posts SET likes_count = likes_count + 1 WHERE id = ?", postID)
+	} else {
+		_, err = tx.Exec("UPDATE group_posts SET likes_count = likes_count - 1 WHERE id = ? AND likes_count > 0", postID)
+	}
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to update likes count: %w", err)
+	}
+
+	var likesCount int64
+	if err := tx.QueryRow("SELECT likes_count FROM group_posts WHERE id = ?", postID).Scan(&likesCount); err != nil {
+		return false, 0, fmt.Errorf("failed to get likes count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return isLiked, likesCount, nil
+}
+
+// CreateGroupPostComment adds a comment to a group post and returns the post's
+// new comments count
+func (r *SQLiteRepository) CreateGroupPostComment(comment *models.Comment) (int64, error) {
+	now := time.Now()
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO comments (post_id, user_id, content, image_path, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, comment.PostID, comment.UserID, comment.Content, comment.ImagePath.String, comment.CreatedAt, comment.UpdatedAt).Scan(&comment.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	var commentsCount int64
+	err = tx.QueryRow(`
+		UPDATE group_posts SET comments_count = comments_count + 1, updated_at = ?
+		WHERE id = ?
+		RETURNING comments_count
+	`, now, comment.PostID).Scan(&commentsCount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update comments count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return commentsCount, nil
+}
+
+// GetGroupPostComments gets the visible comments on a group post, newest first
+func (r *SQLiteRepository) GetGroupPostComments(postID int64) ([]*models.Comment, error) {
+	query := `
+		SELECT id, post_id, user_id, content, image_path, is_anonymized, created_at, updated_at
+		FROM comments
+		WHERE post_id = ? AND is_hidden = 0
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		var comment models.Comment
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.PostID,
+			&comment.UserID,
+			&comment.Content,
+			&comment.ImagePath,
+			&comment.IsAnonymized,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan comment row: %w", err)
+		}
+		comments = append(comments, &comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment rows: %w", err)
+	}
+
+	return comments, nil
+}
+
+// GetGroupPostComment gets a comment by ID
+func (r *SQLiteRepository) GetGroupPostComment(id int64) (*models.Comment, error) {
+	query := `
+		SELECT id, post_id, user_id, content, image_path, is_anonymized, created_at, updated_at
+		FROM comments
+		WHERE id = ?
+	`
+
+	var comment models.Comment
+	err := r.db.QueryRow(query, id).Scan(
+		&comment.ID,
+		&comment.PostID,
+		&comment.UserID,
+		&comment.Content,
+		&comment.ImagePath,
+		&comment.IsAnonymized,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// DeleteGroupPostComment deletes a comment from a group post and returns the
+// post's new comments count
+func (r *SQLiteRepository) DeleteGroupPostComment(comment *models.Comment) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM comments WHERE id = ?", comment.ID); err != nil {
+		return 0, fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	var commentsCount int64
+	err = tx.QueryRow(`
+		UPDATE group_posts SET comments_count = MAX(comments_count - 1, 0)
+		WHERE id = ?
+		RETURNING comments_count
+	`, comment.PostID).Scan(&commentsCount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update comments count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return commentsCount, nil
+}
+
+// AnonymizeMemberComments hides who wrote the user's comments on the group's
+// posts, keeping the comments themselves
+func (r *SQLiteRepository) AnonymizeMemberComments(groupID, userID string) error {
+	_, err := r.db.Exec(`
+		UPDATE comments SET is_anonymized = 1
+		WHERE user_id = ? AND post_id IN (SELECT id FROM group_posts WHERE group_id = ?)
+	`, userID, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize comments: %w", err)
+	}
+
 	return nil
 }
 
@@ -947,8 +1733,8 @@ func (r *SQLiteRepository) DeleteGroupPost(id int64) error {
 func (r *SQLiteRepository) AddChatMessage(message *models.GroupChatMessage) error {
 	query := `
 		INSERT INTO group_chat_messages (
-			group_id, user_id, content, created_at
-		) VALUES (?, ?, ?, ?)
+			group_id, user_id, content, reply_to_id, created_at
+		) VALUES (?, ?, ?, ?, ?)
 	`
 
 	result, err := r.db.Exec(
@@ -956,6 +1742,7 @@ func (r *SQLiteRepository) AddChatMessage(message *models.GroupChatMessage) erro
 		message.GroupID,
 		message.UserID,
 		message.Content,
+		message.ReplyToID,
 		time.Now(),
 	)
 	if err != nil {
@@ -971,17 +1758,42 @@ func (r *SQLiteRepository) AddChatMessage(message *models.GroupChatMessage) erro
 	return nil
 }
 
-// GetGroupChatMessages gets messages from a group chat with pagination
-func (r *SQLiteRepository) GetGroupChatMessages(groupID string, limit, offset int) ([]*models.GroupChatMessage, error) {
-	query := `
-		SELECT id, group_id, user_id, content, created_at
-		FROM group_chat_messages
-		WHERE group_id = ?
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?
-	`
+// chatMessageColumns are the group_chat_messages columns scanChatMessage reads
+const chatMessageColumns = `id, group_id, user_id, content, created_at,
+	COALESCE(reply_to_id, 0), edited_at, pinned_at, COALESCE(pinned_by, '')`
+
+// scanChatMessage scans a row of chatMessageColumns into a message
+func scanChatMessage(row interface{ Scan(...interface{}) error }) (*models.GroupChatMessage, error) {
+	var message models.GroupChatMessage
+	var editedAt, pinnedAt sql.NullTime
+
+	err := row.Scan(
+		&message.ID,
+		&message.GroupID,
+		&message.UserID,
+		&message.Content,
+		&message.CreatedAt,
+		&message.ReplyToID,
+		&editedAt,
+		&pinnedAt,
+		&message.PinnedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	message.EditedAt = editedAt.Time
+	message.IsEdited = editedAt.Valid
+	message.PinnedAt = pinnedAt.Time
+	message.IsPinned = pinnedAt.Valid
+
+	return &message, nil
+}
 
-	rows, err := r.db.Query(query, groupID, limit, offset)
+// queryChatMessages runs a query for chatMessageColumns and adds each
+// message's author
+func (r *SQLiteRepository) queryChatMessages(query string, args ...interface{}) ([]*models.GroupChatMessage, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chat messages: %w", err)
 	}
@@ -990,15 +1802,7 @@ func (r *SQLiteRepository) GetGroupChatMessages(groupID string, limit, offset in
 	var messages []*models.GroupChatMessage
 
 	for rows.Next() {
-		var message models.GroupChatMessage
-
-		err := rows.Scan(
-			&message.ID,
-			&message.GroupID,
-			&message.UserID,
-			&message.Content,
-			&message.CreatedAt,
-		)
+		message, err := scanChatMessage(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message row: %w", err)
 		}
@@ -1010,13 +1814,30 @@ func (r *SQLiteRepository) GetGroupChatMessages(groupID string, limit, offset in
 		}
 		message.User = user
 
-		messages = append(messages, &message)
+		messages = append(messages, message)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating message rows: %w", err)
 	}
 
+	return messages, nil
+}
+
+// GetGroupChatMessages gets messages from a group chat with pagination,
+// leaving out those hidden by a moderator
+func (r *SQLiteRepository) GetGroupChatMessages(groupID string, limit, offset int) ([]*models.GroupChatMessage, error) {
+	messages, err := r.queryChatMessages(`
+		SELECT `+chatMessageColumns+`
+		FROM group_chat_messages
+		WHERE group_id = ? AND is_hidden = 0
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`, groupID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
 	// Reverse the order to get oldest messages first
 	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
 		messages[i], messages[j] = messages[j], messages[i]
@@ -1025,6 +1846,87 @@ func (r *SQLiteRepository) GetGroupChatMessages(groupID string, limit, offset in
 	return messages, nil
 }
 
+// GetChatMessageByID gets a group chat message, or nil if there's none or
+// it was hidden by a moderator
+func (r *SQLiteRepository) GetChatMessageByID(id int64) (*models.GroupChatMessage, error) {
+	message, err := scanChatMessage(r.db.QueryRow("SELECT "+chatMessageColumns+" FROM group_chat_messages WHERE id = ? AND is_hidden = 0", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat message: %w", err)
+	}
+
+	user, err := r.GetUserBasicByID(message.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	message.User = user
+
+	return message, nil
+}
+
+// UpdateChatMessageContent changes a chat message's content and marks it
+// edited
+func (r *SQLiteRepository) UpdateChatMessageContent(id int64, content string) error {
+	_, err := r.db.Exec(
+		"UPDATE group_chat_messages SET content = ?, edited_at = ? WHERE id = ?",
+		content, time.Now().UTC(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update chat message: %w", err)
+	}
+	return nil
+}
+
+// DeleteChatMessage deletes a chat message. Replies to it are kept.
+func (r *SQLiteRepository) DeleteChatMessage(id int64) error {
+	_, err := r.db.Exec("DELETE FROM group_chat_messages WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete chat message: %w", err)
+	}
+	return nil
+}
+
+// PinChatMessage pins a chat message
+func (r *SQLiteRepository) PinChatMessage(id int64, userID string) error {
+	_, err := r.db.Exec(
+		"UPDATE group_chat_messages SET pinned_at = ?, pinned_by = ? WHERE id = ?",
+		time.Now().UTC(), userID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to pin chat message: %w", err)
+	}
+	return nil
+}
+
+// UnpinChatMessage unpins a chat message
+func (r *SQLiteRepository) UnpinChatMessage(id int64) error {
+	_, err := r.db.Exec("UPDATE group_chat_messages SET pinned_at = NULL, pinned_by = NULL WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to unpin chat message: %w", err)
+	}
+	return nil
+}
+
+// GetPinnedChatMessages gets the group's pinned chat messages, most recently
+// pinned first
+func (r *SQLiteRepository) GetPinnedChatMessages(groupID string) ([]*models.GroupChatMessage, error) {
+	messages, err := r.queryChatMessages(`
+		SELECT `+chatMessageColumns+`
+		FROM group_chat_messages
+		WHERE group_id = ? AND pinned_at IS NOT NULL AND is_hidden = 0
+		ORDER BY pinned_at DESC
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if messages == nil {
+		messages = []*models.GroupChatMessage{}
+	}
+	return messages, nil
+}
+
 // GetUserBasicByID gets basic user information by ID
 func (r *SQLiteRepository) GetUserBasicByID(userID string) (*models.UserBasic, error) {
 	query := `
@@ -1152,3 +2054,806 @@ func (r *SQLiteRepository) getNextAvailableID() (int64, error) {
 
 	return maxID + 1, nil
 }
+
+// GetGroupCreatorID gets the ID of the user who created the group, or an empty
+// string if the group doesn't exist
+func (r *SQLiteRepository) GetGroupCreatorID(groupID string) (string, error) {
+	var creatorID string
+	err := r.db.QueryRow("SELECT creator_id FROM groups WHERE id = ? AND archived_at IS NULL", groupID).Scan(&creatorID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get group creator: %w", err)
+	}
+	return creatorID, nil
+}
+
+// GetGroupRoles gets the custom roles of a group, oldest first
+func (r *SQLiteRepository) GetGroupRoles(groupID string) ([]*models.GroupRole, error) {
+	rows, err := r.db.Query(
+		"SELECT id, group_id, name, created_at FROM group_roles WHERE group_id = ? ORDER BY created_at, name",
+		groupID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*models.GroupRole
+	for rows.Next() {
+		role := &models.GroupRole{}
+		if err := rows.Scan(&role.ID, &role.GroupID, &role.Name, &role.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// CreateGroupRole adds a custom role to a group
+func (r *SQLiteRepository) CreateGroupRole(role *models.GroupRole) error {
+	role.ID = role.GroupID + ":" + strings.ToLower(role.Name)
+	role.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(
+		"INSERT INTO group_roles (id, group_id, name, created_at) VALUES (?, ?, ?, ?)",
+		role.ID, role.GroupID, role.Name, role.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create group role: %w", err)
+	}
+	return nil
+}
+
+// DeleteGroupRole removes a custom role from a group along with its
+// permissions. Members who had it go back to being plain members.
+func (r *SQLiteRepository) DeleteGroupRole(groupID, name string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"UPDATE group_members SET role = ?, updated_at = ? WHERE group_id = ? AND role = ?",
+		models.GroupRoleMember, time.Now(), groupID, name,
+	); err != nil {
+		return fmt.Errorf("failed to reset members' role: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM group_role_permissions WHERE group_id = ? AND role = ?", groupID, name); err != nil {
+		return fmt.Errorf("failed to delete role permissions: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM group_roles WHERE group_id = ? AND name = ?", groupID, name); err != nil {
+		return fmt.Errorf("failed to delete group role: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetRolePermissions gets the permission overrides of all of a group's roles
+func (r *SQLiteRepository) GetRolePermissions(groupID string) ([]*models.GroupRolePermission, error) {
+	rows, err := r.db.Query(
+		"SELECT id, group_id, role, action, allowed, updated_at FROM group_role_permissions WHERE group_id = ?",
+		groupID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	var permissions []*models.GroupRolePermission
+	for rows.Next() {
+		permission := &models.GroupRolePermission{}
+		if err := rows.Scan(
+			&permission.ID,
+			&permission.GroupID,
+			&permission.Role,
+			&permission.Action,
+			&permission.Allowed,
+			&permission.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+// GetRolePermission gets a role's override for one action, or nil if the role
+// uses its default
+func (r *SQLiteRepository) GetRolePermission(groupID, role, action string) (*models.GroupRolePermission, error) {
+	permission := &models.GroupRolePermission{}
+	err := r.db.QueryRow(
+		"SELECT id, group_id, role, action, allowed, updated_at FROM group_role_permissions WHERE id = ?",
+		groupID+":"+strings.ToLower(role)+":"+action,
+	).Scan(
+		&permission.ID,
+		&permission.GroupID,
+		&permission.Role,
+		&permission.Action,
+		&permission.Allowed,
+		&permission.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permission: %w", err)
+	}
+	return permission, nil
+}
+
+// SetRolePermissions saves overrides of whether a role may perform actions,
+// by action
+func (r *SQLiteRepository) SetRolePermissions(groupID, role string, permissions map[string]bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for action, allowed := range permissions {
+		_, err := tx.Exec(`
+			INSERT INTO group_role_permissions (id, group_id, role, action, allowed, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET allowed = excluded.allowed, updated_at = excluded.updated_at
+		`, groupID+":"+strings.ToLower(role)+":"+action, groupID, role, action, allowed, now)
+		if err != nil {
+			return fmt.Errorf("failed to save role permission: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// inviteLinkColumns are the group_invite_links columns scanInviteLink reads
+const inviteLinkColumns = "id, group_id, token, created_by, expires_at, max_uses, uses, requires_approval, revoked_at, created_at"
+
+// scanInviteLink scans a row of inviteLinkColumns into an invite link
+func scanInviteLink(row interface{ Scan(...interface{}) error }) (*models.GroupInviteLink, error) {
+	var link models.GroupInviteLink
+	var expiresAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&link.ID, &link.GroupID, &link.Token, &link.CreatedBy, &expiresAt,
+		&link.MaxUses, &link.Uses, &link.RequiresApproval, &revokedAt, &link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	link.ExpiresAt = expiresAt.Time
+	link.RevokedAt = revokedAt.Time
+	return &link, nil
+}
+
+// CreateInviteLink adds an invite link to a group
+func (r *SQLiteRepository) CreateInviteLink(link *models.GroupInviteLink) error {
+	if link.ID == "" {
+		link.ID = uuid.New().String()
+	}
+	link.CreatedAt = time.Now()
+
+	var expiresAt interface{}
+	if !link.ExpiresAt.IsZero() {
+		expiresAt = link.ExpiresAt.UTC()
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO group_invite_links (id, group_id, token, created_by, expires_at, max_uses, requires_approval, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, link.ID, link.GroupID, link.Token, link.CreatedBy, expiresAt, link.MaxUses, link.RequiresApproval, link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invite link: %w", err)
+	}
+
+	return nil
+}
+
+// GetInviteLink gets an invite link by its ID
+func (r *SQLiteRepository) GetInviteLink(id string) (*models.GroupInviteLink, error) {
+	link, err := scanInviteLink(r.db.QueryRow("SELECT "+inviteLinkColumns+" FROM group_invite_links WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite link: %w", err)
+	}
+	return link, nil
+}
+
+// GetInviteLinkByToken gets an invite link by its token
+func (r *SQLiteRepository) GetInviteLinkByToken(token string) (*models.GroupInviteLink, error) {
+	link, err := scanInviteLink(r.db.QueryRow("SELECT "+inviteLinkColumns+" FROM group_invite_links WHERE token = ?", token))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite link: %w", err)
+	}
+	return link, nil
+}
+
+// GetGroupInviteLinks gets a group's invite links, newest first
+func (r *SQLiteRepository) GetGroupInviteLinks(groupID string) ([]*models.GroupInviteLink, error) {
+	rows, err := r.db.Query("SELECT "+inviteLinkColumns+" FROM group_invite_links WHERE group_id = ? ORDER BY created_at DESC", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*models.GroupInviteLink{}
+	for rows.Next() {
+		link, err := scanInviteLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invite link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invite links: %w", err)
+	}
+
+	return links, nil
+}
+
+// RevokeInviteLink stops an invite link from being redeemed
+func (r *SQLiteRepository) RevokeInviteLink(id string) error {
+	_, err := r.db.Exec("UPDATE group_invite_links SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke invite link: %w", err)
+	}
+	return nil
+}
+
+// RedeemInviteLink counts a use of an invite link, records who used it and
+// with what outcome, and adds the member, or updates the status of the
+// existing member, along with their answers to the join questions, all at
+// once. It returns false, changing nothing, if the link has been revoked, has
+// expired or has no uses left.
+func (r *SQLiteRepository) RedeemInviteLink(linkID, outcome string, member *models.GroupMember, answers []*models.GroupJoinAnswer) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	result, err := tx.Exec(`
+		UPDATE group_invite_links SET uses = uses + 1
+		WHERE id = ? AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > ?)
+			AND (max_uses = 0 OR uses < max_uses)
+	`, linkID, now.UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to use invite link: %w", err)
+	}
+	used, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to use invite link: %w", err)
+	}
+	if used == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO group_invite_link_uses (link_id, user_id, outcome, created_at) VALUES (?, ?, ?, ?)",
+		linkID, member.UserID, outcome, now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record invite link use: %w", err)
+	}
+
+	var previousStatus string
+	err = tx.QueryRow(
+		"SELECT id, status FROM group_members WHERE group_id = ? AND user_id = ?",
+		member.GroupID, member.UserID,
+	).Scan(&member.ID, &previousStatus)
+	switch {
+	case err == sql.ErrNoRows:
+		member.ID = uuid.New().String()
+		member.CreatedAt = now
+		member.UpdatedAt = now
+		_, err = tx.Exec(`
+			INSERT INTO group_members (
+				id, group_id, user_id, role, status, invited_by, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, member.ID, member.GroupID, member.UserID, member.Role, member.Status, member.InvitedBy, member.CreatedAt, member.UpdatedAt)
+		if err != nil {
+			return false, fmt.Errorf("failed to add member: %w", err)
+		}
+	case err != nil:
+		return false, fmt.Errorf("failed to get member: %w", err)
+	default:
+		_, err = tx.Exec(
+			"UPDATE group_members SET status = ?, updated_at = ? WHERE id = ?",
+			member.Status, now, member.ID,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to update member status: %w", err)
+		}
+	}
+
+	// Replace any answers left from an earlier request
+	if _, err := tx.Exec("DELETE FROM group_join_answers WHERE member_id = ?", member.ID); err != nil {
+		return false, fmt.Errorf("failed to clear join answers: %w", err)
+	}
+	for _, answer := range answers {
+		answer.MemberID = member.ID
+		answer.ID = fmt.Sprintf("%s:%d", member.ID, answer.Position)
+		_, err := tx.Exec(
+			"INSERT INTO group_join_answers (id, member_id, position, question, answer) VALUES (?, ?, ?, ?, ?)",
+			answer.ID, answer.MemberID, answer.Position, answer.Question, answer.Answer,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to save join answer: %w", err)
+		}
+	}
+
+	if member.Status == "accepted" && previousStatus != "accepted" {
+		_, err = tx.Exec(`
+			INSERT INTO user_stats (user_id, groups_joined) VALUES (?, 1)
+			ON CONFLICT(user_id) DO UPDATE SET groups_joined = groups_joined + 1
+		`, member.UserID)
+		if err != nil {
+			return false, fmt.Errorf("failed to update user group count: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetInviteLinkUses gets who redeemed an invite link, most recent first
+func (r *SQLiteRepository) GetInviteLinkUses(linkID string) ([]*models.GroupInviteLinkUse, error) {
+	rows, err := r.db.Query(`
+		SELECT id, link_id, user_id, outcome, created_at
+		FROM group_invite_link_uses
+		WHERE link_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite link uses: %w", err)
+	}
+	defer rows.Close()
+
+	uses := []*models.GroupInviteLinkUse{}
+	for rows.Next() {
+		var use models.GroupInviteLinkUse
+		if err := rows.Scan(&use.ID, &use.LinkID, &use.UserID, &use.Outcome, &use.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan invite link use: %w", err)
+		}
+		uses = append(uses, &use)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invite link uses: %w", err)
+	}
+
+	return uses, nil
+}
+
+// BanUser bans a user from a group, replacing any ban they already have
+func (r *SQLiteRepository) BanUser(ban *models.GroupBan) error {
+	ban.ID = ban.GroupID + ":" + ban.UserID
+	ban.CreatedAt = time.Now()
+
+	var expiresAt interface{}
+	if !ban.ExpiresAt.IsZero() {
+		expiresAt = ban.ExpiresAt.UTC()
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO group_bans (id, group_id, user_id, banned_by, reason, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			banned_by = excluded.banned_by,
+			reason = excluded.reason,
+			expires_at = excluded.expires_at,
+			created_at = excluded.created_at
+	`, ban.ID, ban.GroupID, ban.UserID, ban.BannedBy, ban.Reason, expiresAt, ban.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to ban user: %w", err)
+	}
+
+	return nil
+}
+
+// UnbanUser lifts a user's ban from a group
+func (r *SQLiteRepository) UnbanUser(groupID, userID string) error {
+	_, err := r.db.Exec("DELETE FROM group_bans WHERE id = ?", groupID+":"+userID)
+	if err != nil {
+		return fmt.Errorf("failed to unban user: %w", err)
+	}
+	return nil
+}
+
+// scanGroupBan scans a group_bans row into a ban
+func scanGroupBan(row interface{ Scan(...interface{}) error }) (*models.GroupBan, error) {
+	var ban models.GroupBan
+	var expiresAt sql.NullTime
+	err := row.Scan(&ban.ID, &ban.GroupID, &ban.UserID, &ban.BannedBy, &ban.Reason, &expiresAt, &ban.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	ban.ExpiresAt = expiresAt.Time
+	return &ban, nil
+}
+
+// GetActiveBan gets the user's ban from a group, unless they have none or it
+// has expired
+func (r *SQLiteRepository) GetActiveBan(groupID, userID string) (*models.GroupBan, error) {
+	ban, err := scanGroupBan(r.db.QueryRow(`
+		SELECT id, group_id, user_id, banned_by, COALESCE(reason, ''), expires_at, created_at
+		FROM group_bans
+		WHERE id = ? AND (expires_at IS NULL OR expires_at > ?)
+	`, groupID+":"+userID, time.Now().UTC()))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ban: %w", err)
+	}
+	return ban, nil
+}
+
+// GetActiveBans gets a group's bans that haven't expired, newest first
+func (r *SQLiteRepository) GetActiveBans(groupID string) ([]*models.GroupBan, error) {
+	rows, err := r.db.Query(`
+		SELECT id, group_id, user_id, banned_by, COALESCE(reason, ''), expires_at, created_at
+		FROM group_bans
+		WHERE group_id = ? AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY created_at DESC
+	`, groupID, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bans: %w", err)
+	}
+	defer rows.Close()
+
+	bans := []*models.GroupBan{}
+	for rows.Next() {
+		ban, err := scanGroupBan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ban: %w", err)
+		}
+		bans = append(bans, ban)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+// GetJoinQuestions gets a group's join questions in order
+func (r *SQLiteRepository) GetJoinQuestions(groupID string) ([]string, error) {
+	rows, err := r.db.Query("SELECT question FROM group_join_questions WHERE group_id = ? ORDER BY position", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join questions: %w", err)
+	}
+	defer rows.Close()
+
+	questions := []string{}
+	for rows.Next() {
+		var question string
+		if err := rows.Scan(&question); err != nil {
+			return nil, fmt.Errorf("failed to scan join question: %w", err)
+		}
+		questions = append(questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating join questions: %w", err)
+	}
+
+	return questions, nil
+}
+
+// SetJoinQuestions replaces a group's join questions
+func (r *SQLiteRepository) SetJoinQuestions(groupID string, questions []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM group_join_questions WHERE group_id = ?", groupID); err != nil {
+		return fmt.Errorf("failed to clear join questions: %w", err)
+	}
+
+	for i, question := range questions {
+		_, err := tx.Exec(
+			"INSERT INTO group_join_questions (id, group_id, position, question, created_at) VALUES (?, ?, ?, ?, ?)",
+			fmt.Sprintf("%s:%d", groupID, i), groupID, i, question, time.Now(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add join question: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SaveJoinAnswers stores a pending member's answers to the join questions
+func (r *SQLiteRepository) SaveJoinAnswers(memberID string, answers []*models.GroupJoinAnswer) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, answer := range answers {
+		answer.MemberID = memberID
+		answer.ID = fmt.Sprintf("%s:%d", memberID, answer.Position)
+		_, err := tx.Exec(
+			"INSERT INTO group_join_answers (id, member_id, position, question, answer) VALUES (?, ?, ?, ?, ?)",
+			answer.ID, answer.MemberID, answer.Position, answer.Question, answer.Answer,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save join answer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetJoinAnswers gets a member's answers to the join questions in order
+func (r *SQLiteRepository) GetJoinAnswers(memberID string) ([]*models.GroupJoinAnswer, error) {
+	rows, err := r.db.Query(
+		"SELECT id, member_id, position, question, answer FROM group_join_answers WHERE member_id = ? ORDER BY position",
+		memberID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join answers: %w", err)
+	}
+	defer rows.Close()
+
+	answers := []*models.GroupJoinAnswer{}
+	for rows.Next() {
+		var answer models.GroupJoinAnswer
+		if err := rows.Scan(&answer.ID, &answer.MemberID, &answer.Position, &answer.Question, &answer.Answer); err != nil {
+			return nil, fmt.Errorf("failed to scan join answer: %w", err)
+		}
+		answers = append(answers, &answer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating join answers: %w", err)
+	}
+
+	return answers, nil
+}
+
+// MarkChatRead moves the user's read cursor in the group's chat forward to the
+// message. It reports whether the cursor moved; it never moves back.
+func (r *SQLiteRepository) MarkChatRead(groupID, userID string, messageID int64) (bool, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO group_chat_reads (id, group_id, user_id, last_read_message_id, read_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			last_read_message_id = excluded.last_read_message_id,
+			read_at = excluded.read_at
+		WHERE excluded.last_read_message_id > group_chat_reads.last_read_message_id
+	`, groupID+":"+userID, groupID, userID, messageID, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to mark chat read: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// GetLatestChatMessageID gets the ID of the group's latest chat message, or 0
+// if there's none
+func (r *SQLiteRepository) GetLatestChatMessageID(groupID string) (int64, error) {
+	var id int64
+	err := r.db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM group_chat_messages WHERE group_id = ?", groupID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest chat message: %w", err)
+	}
+	return id, nil
+}
+
+// GetChatReadCursors gets how far each of the group's members has read its
+// chat
+func (r *SQLiteRepository) GetChatReadCursors(groupID string) ([]*models.GroupChatRead, error) {
+	rows, err := r.db.Query(`
+		SELECT r.id, r.group_id, r.user_id, r.last_read_message_id, r.read_at
+		FROM group_chat_reads r
+		JOIN group_members gm ON gm.group_id = r.group_id AND gm.user_id = r.user_id AND gm.status = 'accepted'
+		WHERE r.group_id = ?
+		ORDER BY r.last_read_message_id DESC
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat read cursors: %w", err)
+	}
+	defer rows.Close()
+
+	cursors := []*models.GroupChatRead{}
+	for rows.Next() {
+		var cursor models.GroupChatRead
+		if err := rows.Scan(&cursor.ID, &cursor.GroupID, &cursor.UserID, &cursor.LastReadMessageID, &cursor.ReadAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat read cursor: %w", err)
+		}
+		cursors = append(cursors, &cursor)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating chat read cursors: %w", err)
+	}
+
+	return cursors, nil
+}
+
+// unreadChatMessagesQuery selects the chat messages a member hasn't read:
+// those by others, since they joined, past their read cursor
+const unreadChatMessagesQuery = `
+	FROM group_members gm
+	JOIN group_chat_messages m ON m.group_id = gm.group_id
+		AND m.user_id != gm.user_id
+		AND m.created_at >= gm.created_at
+		AND m.is_hidden = 0
+	LEFT JOIN group_chat_reads r ON r.id = gm.group_id || ':' || gm.user_id
+	WHERE gm.user_id = ? AND gm.status = 'accepted'
+		AND m.id > COALESCE(r.last_read_message_id, 0)
+`
+
+// GetUnreadChatCounts gets how many chat messages the user hasn't read in each
+// of their groups. Groups without unread messages are left out.
+func (r *SQLiteRepository) GetUnreadChatCounts(userID string) (map[string]int, error) {
+	rows, err := r.db.Query("SELECT gm.group_id, COUNT(m.id) "+unreadChatMessagesQuery+" GROUP BY gm.group_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread chat counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var groupID string
+		var count int
+		if err := rows.Scan(&groupID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan unread chat count: %w", err)
+		}
+		counts[groupID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unread chat counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountUnreadChatMessages counts the chat messages the user hasn't read in the
+// group, and gets the ID of the first of them, or 0 if there's none
+func (r *SQLiteRepository) CountUnreadChatMessages(groupID, userID string) (int, int64, error) {
+	var count int
+	var firstID int64
+	err := r.db.QueryRow(
+		"SELECT COUNT(m.id), COALESCE(MIN(m.id), 0) "+unreadChatMessagesQuery+" AND gm.group_id = ?",
+		userID, groupID,
+	).Scan(&count, &firstID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count unread chat messages: %w", err)
+	}
+	return count, firstID, nil
+}
+
+// CountChatMessages counts the messages in the group's chat that members can
+// see, leaving out those hidden by moderation
+func (r *SQLiteRepository) CountChatMessages(groupID string) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM group_chat_messages WHERE group_id = ? AND is_hidden = 0", groupID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count chat messages: %w", err)
+	}
+	return count, nil
+}
+
+// scanNotificationSetting scans a group_notification_settings row into a
+// notification setting
+func scanNotificationSetting(row interface{ Scan(...interface{}) error }) (*models.GroupNotificationSetting, error) {
+	var setting models.GroupNotificationSetting
+	var mutedUntil sql.NullTime
+	err := row.Scan(&setting.ID, &setting.GroupID, &setting.UserID, &setting.Level, &setting.ChatEnabled, &mutedUntil, &setting.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	setting.MutedUntil = mutedUntil.Time
+	return &setting, nil
+}
+
+// GetNotificationSetting gets the user's notification setting for a group, or
+// nil if they haven't changed it
+func (r *SQLiteRepository) GetNotificationSetting(groupID, userID string) (*models.GroupNotificationSetting, error) {
+	setting, err := scanNotificationSetting(r.db.QueryRow(`
+		SELECT id, group_id, user_id, level, chat_enabled, muted_until, updated_at
+		FROM group_notification_settings
+		WHERE id = ?
+	`, groupID+":"+userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification setting: %w", err)
+	}
+	return setting, nil
+}
+
+// GetNotificationSettings gets the notification settings members changed for
+// a group, keyed by user ID
+func (r *SQLiteRepository) GetNotificationSettings(groupID string) (map[string]*models.GroupNotificationSetting, error) {
+	rows, err := r.db.Query(`
+		SELECT id, group_id, user_id, level, chat_enabled, muted_until, updated_at
+		FROM group_notification_settings
+		WHERE group_id = ?
+	`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := make(map[string]*models.GroupNotificationSetting)
+	for rows.Next() {
+		setting, err := scanNotificationSetting(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification setting: %w", err)
+		}
+		settings[setting.UserID] = setting
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveNotificationSetting creates or replaces the user's notification setting
+// for a group
+func (r *SQLiteRepository) SaveNotificationSetting(setting *models.GroupNotificationSetting) error {
+	setting.ID = setting.GroupID + ":" + setting.UserID
+	setting.UpdatedAt = time.Now()
+
+	var mutedUntil interface{}
+	if !setting.MutedUntil.IsZero() {
+		mutedUntil = setting.MutedUntil.UTC()
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO group_notification_settings (id, group_id, user_id, level, chat_enabled, muted_until, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			level = excluded.level,
+			chat_enabled = excluded.chat_enabled,
+			muted_until = excluded.muted_until,
+			updated_at = excluded.updated_at
+	`, setting.ID, setting.GroupID, setting.UserID, setting.Level, setting.ChatEnabled, mutedUntil, setting.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification setting: %w", err)
+	}
+
+	return nil
+}
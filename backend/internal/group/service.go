@@ -5,9 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"mime/multipart"
+	"strconv"
 	"time"
 
+	"github.com/Athooh/social-network/internal/contentfilter"
+	"github.com/Athooh/social-network/internal/handle"
+	"github.com/Athooh/social-network/internal/mute"
 	notifications "github.com/Athooh/social-network/internal/notifcations"
+	"github.com/Athooh/social-network/internal/privacy"
 	"github.com/Athooh/social-network/pkg/filestore"
 	"github.com/Athooh/social-network/pkg/logger"
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
@@ -24,10 +29,19 @@ type Service interface {
 	GetAllGroups(userID string, limit, offset int) ([]*models.Group, error)
 	UpdateGroup(id, userID, name, description string, isPublic bool, banner, profilePic *multipart.FileHeader) (*models.Group, error)
 	DeleteGroup(id, userID string) error
+	RestoreGroup(id, userID string) error
+	TransferOwnership(groupID, ownerID, newOwnerID string) error
+	GetGroupSettings(groupID, userID string) (*GroupSettings, error)
+	UpdateGroupSettings(groupID, userID string, update *GroupSettingsUpdate) (*GroupSettings, error)
+
+	// Group discovery operations
+	GetGroupCategories() []string
+	DiscoverGroups(userID string, filter DiscoverFilter) ([]*models.Group, error)
+	RecommendGroups(userID string, limit int) ([]*models.Group, error)
 
 	// Group membership operations
 	InviteToGroup(groupID, inviterID, inviteeID string) error
-	JoinGroup(groupID, userID string) error
+	JoinGroup(groupID, userID string, answers []string) error
 	LeaveGroup(groupID, userID string) error
 	AcceptInvitation(groupID, userID string) error
 	RejectInvitation(groupID, userID string) error
@@ -36,15 +50,60 @@ type Service interface {
 	UpdateMemberRole(groupID, adminID, userID, role string) error
 	RemoveMember(groupID, adminID, userID string) error
 	GetGroupMembers(groupID, userID string, status string) ([]*models.GroupMember, error)
+	BanMember(groupID, adminID, userID, reason string, expiresAt *time.Time) error
+	UnbanMember(groupID, adminID, userID string) error
+	GetGroupBans(groupID, userID string) ([]*models.GroupBan, error)
+	GetJoinQuestions(groupID string) ([]string, error)
+	SetJoinQuestions(groupID, userID string, questions []string) ([]string, error)
 
 	// Group posts operations
 	CreateGroupPost(groupID, userID, content string, image, video *multipart.FileHeader) (*models.GroupPost, error)
 	GetGroupPosts(groupID, userID string, limit, offset int) ([]*models.GroupPost, error)
 	DeleteGroupPost(postID int64, userID string) error
+	RemoveGroupPost(postID int64) error
+
+	// Group post review and pinning operations
+	GetPendingGroupPosts(groupID, userID string) ([]*models.GroupPost, error)
+	ReviewGroupPost(postID int64, reviewerID string, approve bool) error
+	PinGroupPost(postID int64, userID string, expiresAt *time.Time) error
+	UnpinGroupPost(postID int64, userID string) error
+
+	// Group post likes and comments operations
+	LikeGroupPost(postID int64, userID string) (bool, int64, error)
+	CreateGroupPostComment(postID int64, userID, content string, image *multipart.FileHeader) (*models.Comment, error)
+	GetGroupPostComments(postID int64, userID string) ([]*models.Comment, error)
+	DeleteGroupPostComment(commentID int64, userID string) error
 
 	// Group chat operations
-	SendChatMessage(groupID, userID, content string) (*models.GroupChatMessage, error)
+	SendChatMessage(groupID, userID, content string, replyToID int64) (*models.GroupChatMessage, error)
+	EditChatMessage(messageID int64, userID, content string) (*models.GroupChatMessage, error)
+	DeleteChatMessage(messageID int64, userID string) error
+	PinChatMessage(messageID int64, userID string) error
+	UnpinChatMessage(messageID int64, userID string) error
+	GetPinnedChatMessages(groupID, userID string) ([]*models.GroupChatMessage, error)
 	GetGroupChatMessages(groupID, userID string, limit, offset int) ([]*models.GroupChatMessage, error)
+	GetUnreadChatMessages(groupID, userID string, limit int) (*UnreadChatMessages, error)
+	MarkChatRead(groupID, userID string, messageID int64) error
+	GetChatReadCursors(groupID, userID string) ([]*models.GroupChatRead, error)
+
+	// Invite link operations
+	CreateInviteLink(groupID, userID string, options InviteLinkOptions) (*models.GroupInviteLink, error)
+	GetInviteLinks(groupID, userID string) ([]*models.GroupInviteLink, error)
+	RevokeInviteLink(linkID, userID string) error
+	GetInviteLinkUses(linkID, userID string) ([]*models.GroupInviteLinkUse, error)
+	RedeemInviteLink(token, userID string, answers []string) (*InviteRedemption, error)
+
+	// Role and permission operations
+	CanPerform(groupID, userID, action string) (bool, error)
+	GetPermissionMatrix(groupID, userID string) (*PermissionMatrix, error)
+	UpdateRolePermissions(groupID, userID, role string, permissions map[string]bool) (*PermissionMatrix, error)
+	CreateRole(groupID, userID, name string) (*models.GroupRole, error)
+	DeleteRole(groupID, userID, name string) error
+
+	// Notification setting operations
+	GetNotificationSettings(groupID, userID string) (*models.GroupNotificationSetting, error)
+	UpdateNotificationSettings(groupID, userID, level string, chatEnabled bool, mutedUntil *time.Time) (*models.GroupNotificationSetting, error)
+	FilterNotified(groupID string, userIDs []string, kind string) []string
 }
 
 // GroupService implements the Service interface
@@ -54,11 +113,19 @@ type GroupService struct {
 	log           *logger.Logger
 	wsHub         *websocket.Hub
 	notifications *Notifications
+	privacy       privacy.Service
+	handles       handle.Service
+	contentFilter *contentfilter.Pipeline
+	// Invite link redemptions per user, to stop tokens being guessed
+	inviteRedemptions *attemptLimiter
+	// How long deleted groups can be restored before they're purged
+	archiveRetention time.Duration
 }
 
-// NewService creates a new group service
-func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Logger, wsHub *websocket.Hub, notificationRepo notifications.Service) *GroupService {
-	notifications := NewNotifications(repo, wsHub, log, notificationRepo)
+// NewService creates a new group service. Deleted groups can be restored for
+// archiveRetention, after which they're purged.
+func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Logger, wsHub *websocket.Hub, notificationRepo notifications.Service, mutes mute.Service, privacySvc privacy.Service, handles handle.Service, contentFilter *contentfilter.Pipeline, archiveRetention time.Duration) *GroupService {
+	notifications := NewNotifications(repo, wsHub, log, notificationRepo, mutes)
 
 	return &GroupService{
 		repo:          repo,
@@ -66,6 +133,12 @@ func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Log
 		log:           log,
 		wsHub:         wsHub,
 		notifications: notifications,
+		privacy:       privacySvc,
+		handles:       handles,
+		contentFilter: contentFilter,
+
+		inviteRedemptions: newAttemptLimiter(inviteRedeemLimit, inviteRedeemWindow),
+		archiveRetention:  archiveRetention,
 	}
 }
 
@@ -129,6 +202,11 @@ func (s *GroupService) GetGroup(id, userID string) (*models.Group, error) {
 		return nil, err
 	}
 
+	// Deleted groups are only shown to their creator, who can restore them
+	if !group.ArchivedAt.IsZero() && group.CreatorID != userID {
+		return nil, errors.New("group not found")
+	}
+
 	// Check if user is a member
 	isMember, err := s.repo.IsGroupMember(id, userID)
 	if err != nil {
@@ -155,32 +233,53 @@ func (s *GroupService) GetGroup(id, userID string) (*models.Group, error) {
 	return group, nil
 }
 
-// GetUserGroups gets all groups a user is a member of
+// GetUserGroups gets all groups a user is a member of. Users looking at their
+// own groups also see how many chat messages they haven't read in each.
 func (s *GroupService) GetUserGroups(userID, viewerID string) ([]*models.Group, error) {
-	return s.repo.GetUserGroups(userID, viewerID)
+	groups, err := s.repo.GetUserGroups(userID, viewerID)
+	if err != nil || userID != viewerID {
+		return groups, err
+	}
+
+	unread, err := s.repo.GetUnreadChatCounts(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		group.UnreadCount = unread[group.ID]
+	}
+
+	return groups, nil
 }
 
-// GetAllGroups gets all public groups and private groups the user is a member of
+// GetAllGroups gets public groups, groups the user is a member of and private
+// groups listed by name. Private groups the user isn't in only show their
+// name.
 func (s *GroupService) GetAllGroups(userID string, limit, offset int) ([]*models.Group, error) {
-	group, err := s.repo.GetAllGroups(userID, limit, offset)
+	groups, err := s.repo.GetAllGroups(userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
-	return group, nil
+
+	for i, group := range groups {
+		if !group.IsPublic && !group.IsMember {
+			groups[i] = &models.Group{
+				ID:           group.ID,
+				Name:         group.Name,
+				MemberStatus: group.MemberStatus,
+			}
+		}
+	}
+
+	return groups, nil
 }
 
 // UpdateGroup updates a group's information
 func (s *GroupService) UpdateGroup(id, userID, name, description string, isPublic bool, banner, profilePic *multipart.FileHeader) (*models.Group, error) {
-	// Check if user is admin or creator
-	role, err := s.repo.GetMemberRole(id, userID)
-	if err != nil {
+	if err := s.requirePermission(id, userID, models.GroupActionEditSettings, "you don't have permission to edit this group"); err != nil {
 		return nil, err
 	}
 
-	if role != "admin" {
-		return nil, errors.New("only group admins can update the group")
-	}
-
 	// Get current group
 	group, err := s.repo.GetGroupByID(id)
 	if err != nil {
@@ -236,8 +335,12 @@ func (s *GroupService) DeleteGroup(id, userID string) error {
 		return errors.New("only the group creator can delete the group")
 	}
 
-	// Delete the group
-	if err := s.repo.DeleteGroup(id); err != nil {
+	if !group.ArchivedAt.IsZero() {
+		return errors.New("group is already deleted")
+	}
+
+	// Archive the group; it's purged once it can no longer be restored
+	if err := s.repo.ArchiveGroup(id); err != nil {
 		return err
 	}
 
@@ -249,14 +352,26 @@ func (s *GroupService) DeleteGroup(id, userID string) error {
 
 // InviteToGroup invites a user to a group
 func (s *GroupService) InviteToGroup(groupID, inviterID, inviteeID string) error {
-	// Check if inviter is a member
-	isMember, err := s.repo.IsGroupMember(groupID, inviterID)
+	if err := s.requirePermission(groupID, inviterID, models.GroupActionInvite, "you don't have permission to invite people to this group"); err != nil {
+		return err
+	}
+
+	// Check the invitee accepts invitations from the inviter
+	canInvite, err := s.privacy.Allows(inviteeID, inviterID, privacy.SettingInvite)
 	if err != nil {
 		return err
 	}
 
-	if !isMember {
-		return errors.New("only group members can invite others")
+	if !canInvite {
+		return errors.New("this user doesn't accept invitations from you")
+	}
+
+	banned, err := s.isBanned(groupID, inviteeID)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return errors.New("this user is banned from this group")
 	}
 
 	// Check if invitee is already a member or has a pending invitation
@@ -277,7 +392,7 @@ func (s *GroupService) InviteToGroup(groupID, inviterID, inviteeID string) error
 	member := &models.GroupMember{
 		GroupID:   groupID,
 		UserID:    inviteeID,
-		Role:      "member",
+		Role:      models.GroupRoleMember,
 		Status:    "pending",
 		InvitedBy: inviterID,
 	}
@@ -308,8 +423,17 @@ func (s *GroupService) InviteToGroup(groupID, inviterID, inviteeID string) error
 	return nil
 }
 
-// JoinGroup sends a request to join a group
-func (s *GroupService) JoinGroup(groupID, userID string) error {
+// JoinGroup sends a request to join a group, with an answer to each of the
+// group's join questions
+func (s *GroupService) JoinGroup(groupID, userID string, answers []string) error {
+	banned, err := s.isBanned(groupID, userID)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return errors.New("you are banned from this group")
+	}
+
 	// Check if user is already a member or has a pending request
 	existingMember, err := s.repo.GetMemberByID(groupID, userID)
 	if err != nil {
@@ -332,27 +456,26 @@ func (s *GroupService) JoinGroup(groupID, userID string) error {
 		return err
 	}
 
-	// If group is public, automatically accept
-	status := "pending"
-
-	// Create member
-	member := &models.GroupMember{
-		GroupID: groupID,
-		UserID:  userID,
-		Role:    "member",
-		Status:  status,
+	if !group.ArchivedAt.IsZero() {
+		return errors.New("group not found")
 	}
 
-	if err := s.repo.AddMember(member); err != nil {
+	joinAnswers, err := s.joinAnswers(groupID, answers)
+	if err != nil {
 		return err
 	}
 
+	return s.requestToJoin(group, userID, joinAnswers)
+}
+
+// notifyJoinRequest tells the group's creator the user asked to join
+func (s *GroupService) notifyJoinRequest(group *models.Group, userID string) error {
 	newNotification := &notifications.NewNotification{
 		UserId:          group.CreatorID,
 		SenderId:        sql.NullString{String: userID, Valid: true},
 		NotficationType: "joinRequest",
 		Message:         fmt.Sprintf("Wants to join %s", group.Name),
-		TargetGroupID:   sql.NullString{String: groupID, Valid: true},
+		TargetGroupID:   sql.NullString{String: group.ID, Valid: true},
 	}
 	requesterInfo, err := s.repo.GetUserBasicByID(userID)
 	if err != nil {
@@ -382,8 +505,28 @@ func (s *GroupService) LeaveGroup(groupID, userID string) error {
 		return err
 	}
 
-	if group.CreatorID == userID {
-		return errors.New("the group creator cannot leave the group, please delete the group or transfer ownership")
+	role, err := s.repo.GetMemberRole(groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	// A leaving creator or admin hands over to the next admin, promoting a
+	// moderator if the group would be left without one
+	if group.CreatorID == userID || role == models.GroupRoleAdmin {
+		successorID, err := s.successorAdmin(group, userID)
+		if err != nil {
+			return err
+		}
+
+		if group.CreatorID == userID {
+			if successorID == "" {
+				return errors.New("there's no admin or moderator to take over the group, please transfer ownership or delete the group")
+			}
+			if err := s.repo.TransferOwnership(groupID, successorID); err != nil {
+				return err
+			}
+			s.notifications.NotifyGroupOwnershipTransferred(group, userID, successorID)
+		}
 	}
 
 	// Remove the member
@@ -465,16 +608,10 @@ func (s *GroupService) RejectInvitation(groupID, userID string) error {
 
 // AcceptJoinRequest accepts a request to join a group
 func (s *GroupService) AcceptJoinRequest(groupID, adminID, userID string) error {
-	// Check if admin is an admin
-	role, err := s.repo.GetMemberRole(groupID, adminID)
-	if err != nil {
+	if err := s.requirePermission(groupID, adminID, models.GroupActionApproveJoin, "you don't have permission to accept join requests"); err != nil {
 		return err
 	}
 
-	if role != "admin" {
-		return errors.New("only group admins can accept join requests")
-	}
-
 	// Check if join request exists
 	member, err := s.repo.GetMemberByID(groupID, userID)
 	if err != nil {
@@ -485,7 +622,6 @@ func (s *GroupService) AcceptJoinRequest(groupID, adminID, userID string) error
 		return errors.New("join request not found")
 	}
 
-	fmt.Println(member.Status, member.InvitedBy)
 	if member.Status != "pending" || member.InvitedBy != "" {
 		return errors.New("no pending join request found")
 	}
@@ -509,16 +645,10 @@ func (s *GroupService) AcceptJoinRequest(groupID, adminID, userID string) error
 
 // RejectJoinRequest rejects a request to join a group
 func (s *GroupService) RejectJoinRequest(groupID, adminID, userID string) error {
-	// Check if admin is an admin
-	role, err := s.repo.GetMemberRole(groupID, adminID)
-	if err != nil {
+	if err := s.requirePermission(groupID, adminID, models.GroupActionApproveJoin, "you don't have permission to reject join requests"); err != nil {
 		return err
 	}
 
-	if role != "admin" {
-		return errors.New("only group admins can reject join requests")
-	}
-
 	// Check if join request exists
 	member, err := s.repo.GetMemberByID(groupID, userID)
 	if err != nil {
@@ -552,16 +682,10 @@ func (s *GroupService) RejectJoinRequest(groupID, adminID, userID string) error
 
 // UpdateMemberRole updates a member's role
 func (s *GroupService) UpdateMemberRole(groupID, adminID, userID, role string) error {
-	// Check if admin is an admin
-	adminRole, err := s.repo.GetMemberRole(groupID, adminID)
-	if err != nil {
+	if err := s.requirePermission(groupID, adminID, models.GroupActionEditSettings, "you don't have permission to change member roles"); err != nil {
 		return err
 	}
 
-	if adminRole != "admin" {
-		return errors.New("only group admins can update member roles")
-	}
-
 	// Check if user is a member
 	isMember, err := s.repo.IsGroupMember(groupID, userID)
 	if err != nil {
@@ -582,9 +706,10 @@ func (s *GroupService) UpdateMemberRole(groupID, adminID, userID, role string) e
 		return errors.New("cannot change the role of the group creator")
 	}
 
-	// Validate role
-	if role != "admin" && role != "moderator" && role != "member" {
-		return errors.New("invalid role")
+	// Validate role against the built-in and the group's own roles
+	role, err = s.resolveRole(groupID, role)
+	if err != nil {
+		return err
 	}
 
 	// Update role
@@ -600,16 +725,10 @@ func (s *GroupService) UpdateMemberRole(groupID, adminID, userID, role string) e
 
 // RemoveMember removes a member from a group
 func (s *GroupService) RemoveMember(groupID, adminID, userID string) error {
-	// Check if admin is an admin
-	adminRole, err := s.repo.GetMemberRole(groupID, adminID)
-	if err != nil {
+	if err := s.requirePermission(groupID, adminID, models.GroupActionRemoveMember, "you don't have permission to remove members"); err != nil {
 		return err
 	}
 
-	if adminRole != "admin" {
-		return errors.New("only group admins can remove members")
-	}
-
 	// Check if user is a member
 	isMember, err := s.repo.IsGroupMember(groupID, userID)
 	if err != nil {
@@ -635,6 +754,13 @@ func (s *GroupService) RemoveMember(groupID, adminID, userID string) error {
 		return err
 	}
 
+	// Keep their comments, but hide who wrote them if the group asks for it
+	if group.AnonymizeRemovedComments {
+		if err := s.repo.AnonymizeMemberComments(groupID, userID); err != nil {
+			return err
+		}
+	}
+
 	// Notify about member removal
 	s.notifyGroupMemberRemoved(group, userID, adminID)
 
@@ -649,14 +775,11 @@ func (s *GroupService) GetGroupMembers(groupID, userID string, status string) ([
 		return nil, err
 	}
 
-	role, err := s.repo.GetMemberRole(groupID, userID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Only admins can see pending members
-	if status == "pending" && role != "admin" {
-		return nil, errors.New("only group admins can view pending members")
+	// Only those who approve join requests can see pending members
+	if status == "pending" {
+		if err := s.requirePermission(groupID, userID, models.GroupActionApproveJoin, "you don't have permission to view pending members"); err != nil {
+			return nil, err
+		}
 	}
 
 	// Non-members can't view member list
@@ -670,25 +793,44 @@ func (s *GroupService) GetGroupMembers(groupID, userID string, status string) ([
 		return nil, err
 	}
 
+	// Show reviewers what those asking to join answered
+	if status == "pending" {
+		for _, member := range members {
+			if member.InvitedBy != "" {
+				continue
+			}
+			if member.JoinAnswers, err = s.repo.GetJoinAnswers(member.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return members, nil
 }
 
 // CreateGroupPost creates a new post in a group
 func (s *GroupService) CreateGroupPost(groupID, userID, content string, image, video *multipart.FileHeader) (*models.GroupPost, error) {
-	// Check if user is a member
-	isMember, err := s.repo.IsGroupMember(groupID, userID)
-	if err != nil {
+	if err := s.requirePermission(groupID, userID, models.GroupActionPost, "you don't have permission to post in this group"); err != nil {
 		return nil, err
 	}
 
-	if !isMember {
-		return nil, errors.New("only group members can create posts")
+	// Run the text through the content filters before saving anything
+	filterContent := contentfilter.Content{UserID: userID, Kind: contentfilter.KindGroupPost, Text: content}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
+	status, err := s.newPostStatus(groupID, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	post := &models.GroupPost{
 		GroupID:   groupID,
 		UserID:    userID,
 		Content:   content,
+		Status:    status,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -718,6 +860,8 @@ func (s *GroupService) CreateGroupPost(groupID, userID, content string, image, v
 		return nil, err
 	}
 
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(post.ID, 10))
+
 	// Get user data
 	user, err := s.repo.GetUserBasicByID(userID)
 	if err != nil {
@@ -730,8 +874,12 @@ func (s *GroupService) CreateGroupPost(groupID, userID, content string, image, v
 		Avatar:    user.Avatar,
 	}
 
-	// Notify about post creation
-	s.notifyGroupPostCreated(post)
+	// Held posts only go to those who review them until they're approved
+	if post.Status == models.GroupPostPending {
+		s.notifyGroupPostPending(post)
+	} else {
+		s.notifyGroupPostCreated(post)
+	}
 
 	return post, nil
 }
@@ -783,47 +931,84 @@ func (s *GroupService) DeleteGroupPost(postID int64, userID string) error {
 		return errors.New("post not found")
 	}
 
-	// Check if user is the post creator or an admin
+	// Check if user is the post creator or may delete others' posts
 	if post.UserID != userID {
-		role, err := s.repo.GetMemberRole(post.GroupID, userID)
-		if err != nil {
+		if err := s.requirePermission(post.GroupID, userID, models.GroupActionDeletePosts, "you don't have permission to delete this post"); err != nil {
 			return err
 		}
+	}
 
-		if role != "admin" && role != "moderator" {
-			return errors.New("only the post creator, moderators, or admins can delete posts")
-		}
+	return s.removeGroupPost(post)
+}
+
+// RemoveGroupPost deletes a group post whoever wrote it, for moderation
+func (s *GroupService) RemoveGroupPost(postID int64) error {
+	post, err := s.repo.GetGroupPostByID(postID)
+	if err != nil {
+		return err
+	}
+
+	if post == nil {
+		return errors.New("post not found")
 	}
 
-	// Delete the post
-	if err := s.repo.DeleteGroupPost(postID); err != nil {
+	return s.removeGroupPost(post)
+}
+
+// removeGroupPost deletes a group post, then its uploaded files
+func (s *GroupService) removeGroupPost(post *models.GroupPost) error {
+	if err := s.repo.DeleteGroupPost(post.ID); err != nil {
 		return err
 	}
 
+	for _, file := range []string{post.ImagePath.String, post.VideoPath.String} {
+		if file == "" {
+			continue
+		}
+		if err := s.fileStore.DeleteFile(file); err != nil {
+			s.log.Warn("Failed to delete file %s of group post %d: %v", file, post.ID, err)
+		}
+	}
+
 	return nil
 }
 
-// SendChatMessage sends a message to a group chat
-func (s *GroupService) SendChatMessage(groupID, userID, content string) (*models.GroupChatMessage, error) {
-	// Check if user is a member
-	isMember, err := s.repo.IsGroupMember(groupID, userID)
-	if err != nil {
+// SendChatMessage sends a message to a group chat, replying to another of its
+// messages unless replyToID is 0
+func (s *GroupService) SendChatMessage(groupID, userID, content string, replyToID int64) (*models.GroupChatMessage, error) {
+	if err := s.requirePermission(groupID, userID, models.GroupActionChat, "you don't have permission to send messages in this group"); err != nil {
 		return nil, err
 	}
 
-	if !isMember {
-		return nil, errors.New("only group members can send messages")
-	}
-
 	if content == "" {
 		return nil, errors.New("message content is required")
 	}
 
+	var replyTo *models.GroupChatMessage
+	if replyToID != 0 {
+		var err error
+		if replyTo, err = s.repo.GetChatMessageByID(replyToID); err != nil {
+			return nil, err
+		}
+		if replyTo == nil || replyTo.GroupID != groupID {
+			return nil, errors.New("the message you're replying to was not found")
+		}
+	}
+
+	// Run the text through the content filters before saving anything
+	filterContent := contentfilter.Content{UserID: userID, Kind: contentfilter.KindGroupMessage, Text: content}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
 	// Create message
 	message := &models.GroupChatMessage{
 		GroupID:   groupID,
 		UserID:    userID,
 		Content:   content,
+		ReplyToID: replyToID,
+		ReplyTo:   replyTo,
 		CreatedAt: time.Now(),
 	}
 
@@ -832,6 +1017,13 @@ func (s *GroupService) SendChatMessage(groupID, userID, content string) (*models
 		return nil, err
 	}
 
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(message.ID, 10))
+
+	// Their own message is read by the sender
+	if _, err := s.repo.MarkChatRead(groupID, userID, message.ID); err != nil {
+		s.log.Warn("Failed to mark group chat read for sender: %v", err)
+	}
+
 	// Get user info
 	user, err := s.repo.GetUserBasicByID(userID)
 	if err != nil {
@@ -842,6 +1034,13 @@ func (s *GroupService) SendChatMessage(groupID, userID, content string) (*models
 	// Notify about new message
 	s.notifyGroupChatMessage(message)
 
+	mentioned, err := s.chatMentions(message)
+	if err != nil {
+		s.log.Warn("Failed to resolve mentions in group chat message %d: %v", message.ID, err)
+	} else if len(mentioned) > 0 {
+		s.notifications.NotifyGroupChatMention(message, mentioned)
+	}
+
 	return message, nil
 }
 
@@ -863,6 +1062,10 @@ func (s *GroupService) GetGroupChatMessages(groupID, userID string, limit, offse
 		return nil, err
 	}
 
+	if err := s.attachReplies(messages); err != nil {
+		return nil, err
+	}
+
 	return messages, nil
 }
 
@@ -916,6 +1119,16 @@ func (s *GroupService) notifyGroupPostCreated(post *models.GroupPost) {
 	s.notifications.NotifyGroupPostCreated(post)
 }
 
+// notifyGroupPostPending notifies those who review posts about a held post
+func (s *GroupService) notifyGroupPostPending(post *models.GroupPost) {
+	reviewerIDs, err := s.membersWhoCan(post.GroupID, models.GroupActionApprovePosts)
+	if err != nil {
+		s.log.Error("Failed to get post reviewers: %v", err)
+		return
+	}
+	s.notifications.NotifyGroupPostPending(post, reviewerIDs)
+}
+
 // notifyGroupChatMessage notifies about group chat message
 func (s *GroupService) notifyGroupChatMessage(message *models.GroupChatMessage) {
 	s.notifications.NotifyGroupChatMessage(message)
@@ -0,0 +1,108 @@
+package group
+
+import (
+	"errors"
+	"strings"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// GroupSettings are the options that shape how a group works
+type GroupSettings struct {
+	AnonymizeRemovedComments bool     `json:"anonymizeRemovedComments"`
+	RequirePostApproval      bool     `json:"requirePostApproval"`
+	Category                 string   `json:"category"`
+	Tags                     []string `json:"tags"`
+	ListedByName             bool     `json:"listedByName"`
+}
+
+// GroupSettingsUpdate is a partial update of a group's settings. Nil fields
+// are left as they are.
+type GroupSettingsUpdate struct {
+	AnonymizeRemovedComments *bool     `json:"anonymizeRemovedComments"`
+	RequirePostApproval      *bool     `json:"requirePostApproval"`
+	Category                 *string   `json:"category"` // empty clears it
+	Tags                     *[]string `json:"tags"`
+	ListedByName             *bool     `json:"listedByName"`
+}
+
+// settingsOf gets a group's settings
+func settingsOf(group *models.Group) *GroupSettings {
+	return &GroupSettings{
+		AnonymizeRemovedComments: group.AnonymizeRemovedComments,
+		RequirePostApproval:      group.RequirePostApproval,
+		Category:                 group.Category,
+		Tags:                     group.Tags,
+		ListedByName:             group.ListedByName,
+	}
+}
+
+// GetGroupSettings gets the group's settings. Only members can see them.
+func (s *GroupService) GetGroupSettings(groupID, userID string) (*GroupSettings, error) {
+	isMember, err := s.repo.IsGroupMember(groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("only group members can view group settings")
+	}
+
+	group, err := s.repo.GetGroupByID(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return settingsOf(group), nil
+}
+
+// UpdateGroupSettings changes the group's settings. Members need permission to
+// edit the group's settings.
+func (s *GroupService) UpdateGroupSettings(groupID, userID string, update *GroupSettingsUpdate) (*GroupSettings, error) {
+	if err := s.requirePermission(groupID, userID, models.GroupActionEditSettings, "you don't have permission to edit this group"); err != nil {
+		return nil, err
+	}
+
+	group, err := s.repo.GetGroupByID(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.AnonymizeRemovedComments != nil {
+		group.AnonymizeRemovedComments = *update.AnonymizeRemovedComments
+	}
+	if update.RequirePostApproval != nil {
+		group.RequirePostApproval = *update.RequirePostApproval
+	}
+	if update.Category != nil {
+		category := strings.ToLower(strings.TrimSpace(*update.Category))
+		if category != "" && !isGroupCategory(category) {
+			return nil, errors.New("unknown group category")
+		}
+		group.Category = category
+	}
+	if update.ListedByName != nil {
+		group.ListedByName = *update.ListedByName
+	}
+
+	var tags []string
+	if update.Tags != nil {
+		if tags, err = normalizeTags(*update.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateGroup(group); err != nil {
+		return nil, err
+	}
+
+	if update.Tags != nil {
+		if err := s.repo.SetGroupTags(groupID, tags); err != nil {
+			return nil, err
+		}
+		group.Tags = tags
+	}
+
+	s.notifyGroupUpdated(group, userID)
+
+	return settingsOf(group), nil
+}
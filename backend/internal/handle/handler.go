@@ -0,0 +1,188 @@
+package handle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/internal/profile"
+	"github.com/Athooh/social-network/pkg/httputil"
+	"github.com/Athooh/social-network/pkg/logger"
+)
+
+// byHandlePath is the path profiles are looked up by handle under
+const byHandlePath = "/api/users/by-handle/"
+
+// Handler handles HTTP requests for user handles
+type Handler struct {
+	service    Service
+	profileSvc profile.Service
+	log        *logger.Logger
+}
+
+// NewHandler creates a new handle handler
+func NewHandler(service Service, profileSvc profile.Service, log *logger.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		profileSvc: profileSvc,
+		log:        log,
+	}
+}
+
+// HandleMyHandle handles getting and changing the current user's handle
+func (h *Handler) HandleMyHandle(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handle, err := h.service.GetHandle(userID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, "Failed to get handle")
+			return
+		}
+		h.sendJSON(w, http.StatusOK, map[string]interface{}{"handle": handle})
+
+	case http.MethodPut:
+		var req struct {
+			Handle string `json:"handle"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		handle, err := h.service.ChangeHandle(userID, req.Handle)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrHandleTaken):
+				h.sendError(w, http.StatusConflict, err.Error())
+			case errors.Is(err, ErrHandleChangeLimit):
+				h.sendError(w, http.StatusTooManyRequests, err.Error())
+			case errors.Is(err, ErrInvalidHandle), errors.Is(err, ErrHandleReserved):
+				h.sendError(w, http.StatusBadRequest, err.Error())
+			default:
+				h.log.Error("Failed to change handle: %v", err)
+				h.sendError(w, http.StatusInternalServerError, "Failed to change handle")
+			}
+			return
+		}
+		h.sendJSON(w, http.StatusOK, map[string]interface{}{"handle": handle})
+
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// GetProfileByHandle handles getting a profile by handle. Old handles still in
+// their grace period redirect to the user's current handle.
+func (h *Handler) GetProfileByHandle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+		return
+	}
+
+	viewerID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || viewerID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	handle := strings.Trim(strings.TrimPrefix(r.URL.Path, byHandlePath), "/")
+	if handle == "" {
+		h.sendError(w, http.StatusBadRequest, "Handle is required")
+		return
+	}
+
+	resolution, err := h.service.Resolve(handle)
+	if err != nil {
+		h.log.Error("Failed to resolve handle %s: %v", handle, err)
+		h.sendError(w, http.StatusInternalServerError, "Failed to resolve handle")
+		return
+	}
+	if resolution == nil {
+		h.sendError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if resolution.Redirected {
+		http.Redirect(w, r, byHandlePath+url.PathEscape(resolution.Handle), http.StatusFound)
+		return
+	}
+
+	canView, err := h.profileSvc.ValidateProfileViewRequest(viewerID, resolution.UserID)
+	if err != nil {
+		h.log.Error("Failed to validate view request: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+	if !canView {
+		h.sendError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	targetProfile, err := h.profileSvc.GetProfileForViewer(viewerID, resolution.UserID)
+	if err != nil {
+		h.log.Error("Failed to fetch target profile: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Server error")
+		return
+	}
+	h.profileSvc.RecordProfileVisit(viewerID, resolution.UserID)
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Profile fetch successful",
+		"profile": targetProfile,
+	})
+}
+
+// ResolveMentions handles resolving the @mentions in a text to users
+func (h *Handler) ResolveMentions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+		return
+	}
+
+	if userID, ok := auth.GetUserIDFromContext(r.Context()); !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	mentions, err := h.service.ResolveMentions(req.Text)
+	if err != nil {
+		h.log.Error("Failed to resolve mentions: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Failed to resolve mentions")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{"mentions": mentions})
+}
+
+// Helper method to send JSON responses
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	httputil.SendJSON(w, status, data)
+}
+
+// Helper method to send error responses
+func (h *Handler) sendError(w http.ResponseWriter, status int, message string) {
+	var isWarning bool = false
+	if status >= 500 {
+		isWarning = true
+	}
+	httputil.SendError(w, status, message, isWarning)
+}
@@ -0,0 +1,273 @@
+package handle
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Repository defines the interface for handle data access
+type Repository interface {
+	GetByID(id string) (*models.UserHandle, error)
+	GetCurrent(userID string) (*models.UserHandle, error)
+	GetCurrentByIDs(ids []string) ([]*models.UserHandle, error)
+	CountChangesSince(userID string, since time.Time) (int, error)
+	ClaimHandle(userID, handle string, graceCutoff time.Time) error
+	GetCandidate(userID string) (*HandleCandidate, error)
+	GetUsersWithoutHandle() ([]*HandleCandidate, error)
+}
+
+// HandleCandidate is a user without a handle, with the names a handle can be
+// derived from
+type HandleCandidate struct {
+	UserID    string
+	Nickname  string
+	Username  string
+	FirstName string
+	LastName  string
+}
+
+// SQLiteRepository implements Repository interface for SQLite
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite repository
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// handleColumns lists the columns scanned by scanHandle
+const handleColumns = "id, user_id, handle, is_current, created_at, released_at"
+
+// scanHandle scans a user handle row
+func scanHandle(scanner interface{ Scan(...interface{}) error }) (*models.UserHandle, error) {
+	handle := &models.UserHandle{}
+	var releasedAt sql.NullTime
+	if err := scanner.Scan(
+		&handle.ID,
+		&handle.UserID,
+		&handle.Handle,
+		&handle.IsCurrent,
+		&handle.CreatedAt,
+		&releasedAt,
+	); err != nil {
+		return nil, err
+	}
+	if releasedAt.Valid {
+		handle.ReleasedAt = releasedAt.Time
+	}
+	return handle, nil
+}
+
+// GetByID gets a handle, current or released, by its lowercase form. It
+// returns nil if nobody holds or held it.
+func (r *SQLiteRepository) GetByID(id string) (*models.UserHandle, error) {
+	row := r.db.QueryRow("SELECT "+handleColumns+" FROM user_handles WHERE id = ?", id)
+	handle, err := scanHandle(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get handle: %w", err)
+	}
+	return handle, nil
+}
+
+// GetCurrent gets the user's current handle, or nil if they have none
+func (r *SQLiteRepository) GetCurrent(userID string) (*models.UserHandle, error) {
+	row := r.db.QueryRow("SELECT "+handleColumns+" FROM user_handles WHERE user_id = ? AND is_current = TRUE", userID)
+	handle, err := scanHandle(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current handle: %w", err)
+	}
+	return handle, nil
+}
+
+// GetCurrentByIDs gets the current handles among the given lowercase handles
+func (r *SQLiteRepository) GetCurrentByIDs(ids []string) ([]*models.UserHandle, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(
+		"SELECT "+handleColumns+" FROM user_handles WHERE is_current = TRUE AND id IN ("+placeholders+")",
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get handles: %w", err)
+	}
+	defer rows.Close()
+
+	var handles []*models.UserHandle
+	for rows.Next() {
+		handle, err := scanHandle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan handle: %w", err)
+		}
+		handles = append(handles, handle)
+	}
+
+	return handles, rows.Err()
+}
+
+// CountChangesSince counts how many times the user switched to a different
+// handle since the given time
+func (r *SQLiteRepository) CountChangesSince(userID string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM user_handle_changes WHERE user_id = ? AND changed_at > ?",
+		userID, since.UTC(),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count handle changes: %w", err)
+	}
+	return count, nil
+}
+
+// ClaimHandle makes the handle the user's current one and releases their
+// previous handle, recording the change. Handles held by someone else, or
+// released by someone else after graceCutoff, can't be claimed. A handle the
+// user held before is taken back as it was, keeping its history.
+func (r *SQLiteRepository) ClaimHandle(userID, handle string, graceCutoff time.Time) error {
+	id := strings.ToLower(handle)
+	now := time.Now().UTC()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var ownerID string
+	var isCurrent bool
+	var releasedAt sql.NullTime
+	err = tx.QueryRow("SELECT user_id, is_current, released_at FROM user_handles WHERE id = ?", id).
+		Scan(&ownerID, &isCurrent, &releasedAt)
+	exists := err == nil
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("failed to check handle: %w", err)
+	case ownerID != userID && (isCurrent || releasedAt.Time.After(graceCutoff)):
+		return ErrHandleTaken
+	case ownerID != userID:
+		// A stale handle someone else released: take it over
+		if _, err := tx.Exec("DELETE FROM user_handles WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to free handle: %w", err)
+		}
+		exists = false
+	}
+
+	result, err := tx.Exec(
+		"UPDATE user_handles SET is_current = FALSE, released_at = ? WHERE user_id = ? AND is_current = TRUE AND id != ?",
+		now, userID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release previous handle: %w", err)
+	}
+	released, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to release previous handle: %w", err)
+	}
+
+	if exists {
+		_, err = tx.Exec(
+			"UPDATE user_handles SET handle = ?, is_current = TRUE, released_at = NULL WHERE id = ?",
+			handle, id,
+		)
+	} else {
+		_, err = tx.Exec(
+			"INSERT INTO user_handles (id, user_id, handle, is_current, created_at) VALUES (?, ?, ?, TRUE, ?)",
+			id, userID, handle, now,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save handle: %w", err)
+	}
+
+	// Switching away from a handle counts as a change; getting a first handle
+	// or changing only the case doesn't
+	if released > 0 {
+		if _, err := tx.Exec(
+			"INSERT INTO user_handle_changes (user_id, changed_at) VALUES (?, ?)",
+			userID, now,
+		); err != nil {
+			return fmt.Errorf("failed to record handle change: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// candidateQuery selects the names a user's handle can be derived from
+const candidateQuery = `
+	SELECT u.id, COALESCE(u.nickname, ''), COALESCE(p.username, ''), u.first_name, u.last_name
+	FROM users u
+	LEFT JOIN user_profiles p ON p.user_id = u.id
+`
+
+// GetCandidate gets the names the user's handle can be derived from, or nil if
+// the user doesn't exist
+func (r *SQLiteRepository) GetCandidate(userID string) (*HandleCandidate, error) {
+	candidate := &HandleCandidate{}
+	err := r.db.QueryRow(candidateQuery+" WHERE u.id = ?", userID).Scan(
+		&candidate.UserID,
+		&candidate.Nickname,
+		&candidate.Username,
+		&candidate.FirstName,
+		&candidate.LastName,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return candidate, nil
+}
+
+// GetUsersWithoutHandle gets the users who have no current handle
+func (r *SQLiteRepository) GetUsersWithoutHandle() ([]*HandleCandidate, error) {
+	query := candidateQuery + `
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_handles h WHERE h.user_id = u.id AND h.is_current = TRUE
+		)
+		ORDER BY u.created_at, u.id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users without handle: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*HandleCandidate
+	for rows.Next() {
+		candidate := &HandleCandidate{}
+		if err := rows.Scan(
+			&candidate.UserID,
+			&candidate.Nickname,
+			&candidate.Username,
+			&candidate.FirstName,
+			&candidate.LastName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, rows.Err()
+}
@@ -0,0 +1,294 @@
+package handle
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+const (
+	minHandleLength    = 3
+	maxHandleLength    = 30
+	handleGracePeriod  = 30 * 24 * time.Hour // old handles redirect for this long
+	handleChangeWindow = 30 * 24 * time.Hour
+	maxHandleChanges   = 2 // changes allowed per handleChangeWindow
+)
+
+var (
+	// handlePattern matches a valid handle
+	handlePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
+	// mentionPattern matches an @mention not preceded by a word character, so
+	// email addresses aren't taken for mentions
+	mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@])@([A-Za-z0-9_]{3,30})\b`)
+
+	// invalidHandleChars matches what's removed when deriving a handle from a name
+	invalidHandleChars = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// reservedHandles can't be claimed, mostly because they clash with routes or
+// group mentions
+var reservedHandles = map[string]bool{
+	"admin": true, "administrator": true, "api": true, "all": true,
+	"everyone": true, "explore": true, "group": true, "groups": true,
+	"help": true, "here": true, "home": true, "login": true,
+	"logout": true, "me": true, "messages": true, "mod": true,
+	"moderator": true, "notifications": true, "null": true, "profile": true,
+	"register": true, "root": true, "settings": true, "support": true,
+	"system": true, "undefined": true,
+}
+
+// Service errors
+var (
+	ErrHandleTaken       = errors.New("this handle is already taken")
+	ErrHandleReserved    = errors.New("this handle is reserved")
+	ErrInvalidHandle     = errors.New("handles must be 3 to 30 letters, digits or underscores")
+	ErrHandleChangeLimit = errors.New("you've changed your handle too many times recently, try again later")
+)
+
+// Resolution is the user a handle points to
+type Resolution struct {
+	UserID     string `json:"userId"`
+	Handle     string `json:"handle"`     // the user's current handle
+	Redirected bool   `json:"redirected"` // the handle used to be theirs
+}
+
+// Mention is an @mention resolved to a user
+type Mention struct {
+	Handle string `json:"handle"`
+	UserID string `json:"userId"`
+}
+
+// Service defines the handle service interface
+type Service interface {
+	GetHandle(userID string) (*models.UserHandle, error)
+	ChangeHandle(userID, handle string) (*models.UserHandle, error)
+	Resolve(handle string) (*Resolution, error)
+	ResolveMentions(text string) ([]*Mention, error)
+
+	// Assigning handles derived from the user's names
+	AssignHandle(userID string) error
+	BackfillHandles() error
+}
+
+// HandleService implements the Service interface
+type HandleService struct {
+	repo Repository
+	log  *logger.Logger
+}
+
+// NewService creates a new handle service
+func NewService(repo Repository, log *logger.Logger) Service {
+	return &HandleService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// normalize gets the lowercase form handles are unique by
+func normalize(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// validateHandle checks a handle the user picked
+func validateHandle(handle string) error {
+	if !handlePattern.MatchString(handle) {
+		return ErrInvalidHandle
+	}
+	if reservedHandles[strings.ToLower(handle)] {
+		return ErrHandleReserved
+	}
+	return nil
+}
+
+// GetHandle gets the user's current handle, or nil if they have none
+func (s *HandleService) GetHandle(userID string) (*models.UserHandle, error) {
+	return s.repo.GetCurrent(userID)
+}
+
+// ChangeHandle changes the user's handle. The old one keeps redirecting to
+// them for the grace period. Changing only the case is always allowed.
+func (s *HandleService) ChangeHandle(userID, handle string) (*models.UserHandle, error) {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if err := validateHandle(handle); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetCurrent(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != nil && current.Handle == handle {
+		return current, nil
+	}
+
+	if current == nil || current.ID != normalize(handle) {
+		changes, err := s.repo.CountChangesSince(userID, time.Now().Add(-handleChangeWindow))
+		if err != nil {
+			return nil, err
+		}
+		if changes >= maxHandleChanges {
+			return nil, ErrHandleChangeLimit
+		}
+	}
+
+	if err := s.repo.ClaimHandle(userID, handle, time.Now().Add(-handleGracePeriod)); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetCurrent(userID)
+}
+
+// Resolve finds the user a handle points to. Handles released within the grace
+// period resolve to their old owner. It returns nil for unknown handles.
+func (s *HandleService) Resolve(handle string) (*Resolution, error) {
+	found, err := s.repo.GetByID(normalize(handle))
+	if err != nil || found == nil {
+		return nil, err
+	}
+
+	if found.IsCurrent {
+		return &Resolution{UserID: found.UserID, Handle: found.Handle}, nil
+	}
+
+	if time.Since(found.ReleasedAt) > handleGracePeriod {
+		return nil, nil
+	}
+
+	current, err := s.repo.GetCurrent(found.UserID)
+	if err != nil || current == nil {
+		return nil, err
+	}
+
+	return &Resolution{UserID: found.UserID, Handle: current.Handle, Redirected: true}, nil
+}
+
+// ResolveMentions finds the users @mentioned in a text, in order of first
+// mention. Mentions of unknown or old handles are ignored.
+func (s *HandleService) ResolveMentions(text string) ([]*Mention, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		id := normalize(match[1])
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	handles, err := s.repo.GetCurrentByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*models.UserHandle, len(handles))
+	for _, handle := range handles {
+		byID[handle.ID] = handle
+	}
+
+	mentions := []*Mention{}
+	for _, id := range ids {
+		if handle, ok := byID[id]; ok {
+			mentions = append(mentions, &Mention{Handle: handle.Handle, UserID: handle.UserID})
+		}
+	}
+
+	return mentions, nil
+}
+
+// AssignHandle gives a user without a handle one derived from their names
+func (s *HandleService) AssignHandle(userID string) error {
+	current, err := s.repo.GetCurrent(userID)
+	if err != nil || current != nil {
+		return err
+	}
+
+	candidate, err := s.repo.GetCandidate(userID)
+	if err != nil {
+		return err
+	}
+	if candidate == nil {
+		return errors.New("user not found")
+	}
+
+	return s.assignDerivedHandle(candidate)
+}
+
+// BackfillHandles gives every user without a handle one derived from their
+// nickname, profile username or name, adding a number on collisions
+func (s *HandleService) BackfillHandles() error {
+	candidates, err := s.repo.GetUsersWithoutHandle()
+	if err != nil {
+		s.log.Error("Failed to get users without handle: %v", err)
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if err := s.assignDerivedHandle(candidate); err != nil {
+			s.log.Error("Failed to assign handle to user %s: %v", candidate.UserID, err)
+			return err
+		}
+	}
+
+	if len(candidates) > 0 {
+		s.log.Info("Assigned handles to %d users", len(candidates))
+	}
+
+	return nil
+}
+
+// assignDerivedHandle claims the first free handle derived from the user's
+// names: the base itself, then the base followed by 2, 3 and so on
+func (s *HandleService) assignDerivedHandle(candidate *HandleCandidate) error {
+	base := deriveHandleBase(candidate)
+	graceCutoff := time.Now().Add(-handleGracePeriod)
+
+	for n := 1; ; n++ {
+		handle := base
+		if n > 1 {
+			suffix := strconv.Itoa(n)
+			if len(base)+len(suffix) > maxHandleLength {
+				handle = base[:maxHandleLength-len(suffix)]
+			}
+			handle += suffix
+		}
+
+		if reservedHandles[handle] {
+			continue
+		}
+
+		err := s.repo.ClaimHandle(candidate.UserID, handle, graceCutoff)
+		if !errors.Is(err, ErrHandleTaken) {
+			return err
+		}
+	}
+}
+
+// deriveHandleBase turns the first usable of the user's nickname, profile
+// username or full name into a handle
+func deriveHandleBase(candidate *HandleCandidate) string {
+	names := []string{
+		candidate.Nickname,
+		candidate.Username,
+		candidate.FirstName + "_" + candidate.LastName,
+	}
+
+	for _, name := range names {
+		base := invalidHandleChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "")
+		base = strings.Trim(base, "_")
+		if len(base) > maxHandleLength {
+			base = base[:maxHandleLength]
+		}
+		if len(base) >= minHandleLength {
+			return base
+		}
+	}
+
+	return "user"
+}
@@ -0,0 +1,125 @@
+package mute
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/pkg/httputil"
+	"github.com/Athooh/social-network/pkg/logger"
+)
+
+// Handler handles HTTP requests for mutes
+type Handler struct {
+	service Service
+	log     *logger.Logger
+}
+
+// NewHandler creates a new mute handler
+func NewHandler(service Service, log *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		log:     log,
+	}
+}
+
+// HandleMutes handles listing, adding and removing the user's mutes
+func (h *Handler) HandleMutes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetMutes(w, r)
+	case http.MethodPost:
+		h.Mute(w, r)
+	case http.MethodDelete:
+		h.Unmute(w, r)
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// GetMutes handles listing everyone the user has muted or restricted
+func (h *Handler) GetMutes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	mutes, err := h.service.GetMutes(userID)
+	if err != nil {
+		h.sendError(w, http.StatusInternalServerError, "Failed to get mutes")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, mutes)
+}
+
+// Mute handles muting or restricting a user
+func (h *Handler) Mute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		UserID string `json:"userId"`
+		Type   string `json:"type"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.Mute(userID, request.UserID, request.Type); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "User muted",
+	})
+}
+
+// Unmute handles removing a mute or restriction. The user and type are given
+// by the "userId" and "type" query parameters.
+func (h *Handler) Unmute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	mutedUserID := r.URL.Query().Get("userId")
+	if mutedUserID == "" {
+		h.sendError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	if err := h.service.Unmute(userID, mutedUserID, r.URL.Query().Get("type")); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "User unmuted",
+	})
+}
+
+// Helper method to send JSON responses
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	httputil.SendJSON(w, status, data)
+}
+
+// Helper method to send error responses
+func (h *Handler) sendError(w http.ResponseWriter, status int, message string) {
+	var isWarning bool = false
+	if status >= 500 {
+		isWarning = true
+	}
+	httputil.SendError(w, status, message, isWarning)
+}
@@ -0,0 +1,143 @@
+package mute
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Repository defines the interface for mute data access
+type Repository interface {
+	SaveMute(mute *models.UserMute) error
+	DeleteMute(userID, mutedUserID, muteType string) error
+	GetMutes(userID string) ([]*MutedUser, error)
+	HasMuted(userID, mutedUserID, muteType string) (bool, error)
+	GetMutingUserIDs(mutedUserID, muteType string, userIDs []string) ([]string, error)
+}
+
+// SQLiteRepository implements Repository interface for SQLite
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite repository
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// muteID builds the ID that keeps one row per user, muted user and type
+func muteID(userID, mutedUserID, muteType string) string {
+	return userID + ":" + mutedUserID + ":" + muteType
+}
+
+// SaveMute stores a mute. Muting someone twice with the same type is a no-op.
+func (r *SQLiteRepository) SaveMute(mute *models.UserMute) error {
+	mute.ID = muteID(mute.UserID, mute.MutedUserID, mute.Type)
+	mute.CreatedAt = time.Now()
+
+	query := `
+		INSERT OR IGNORE INTO user_mutes (id, user_id, muted_user_id, type, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	if _, err := r.db.Exec(query, mute.ID, mute.UserID, mute.MutedUserID, mute.Type, mute.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save mute: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMute removes a mute
+func (r *SQLiteRepository) DeleteMute(userID, mutedUserID, muteType string) error {
+	if _, err := r.db.Exec("DELETE FROM user_mutes WHERE id = ?", muteID(userID, mutedUserID, muteType)); err != nil {
+		return fmt.Errorf("failed to delete mute: %w", err)
+	}
+	return nil
+}
+
+// GetMutes gets everyone the user has muted, with the types muted for each
+// and the most recently muted first
+func (r *SQLiteRepository) GetMutes(userID string) ([]*MutedUser, error) {
+	query := `
+		SELECT u.id, u.first_name, u.last_name, COALESCE(u.avatar, ''),
+			GROUP_CONCAT(m.type), MAX(m.created_at)
+		FROM user_mutes m
+		JOIN users u ON u.id = m.muted_user_id
+		WHERE m.user_id = ?
+		GROUP BY u.id
+		ORDER BY MAX(m.created_at) DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mutes: %w", err)
+	}
+	defer rows.Close()
+
+	var mutes []*MutedUser
+	for rows.Next() {
+		var mutedUser MutedUser
+		var firstName, lastName, types, mutedAt string
+		if err := rows.Scan(&mutedUser.UserID, &firstName, &lastName, &mutedUser.Avatar, &types, &mutedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan mute: %w", err)
+		}
+		mutedUser.Name = firstName + " " + lastName
+		mutedUser.Types = strings.Split(types, ",")
+		mutes = append(mutes, &mutedUser)
+	}
+
+	return mutes, rows.Err()
+}
+
+// HasMuted checks whether the user has muted someone with the given type
+func (r *SQLiteRepository) HasMuted(userID, mutedUserID, muteType string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM user_mutes WHERE id = ?)",
+		muteID(userID, mutedUserID, muteType),
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check mute: %w", err)
+	}
+	return exists, nil
+}
+
+// GetMutingUserIDs gets which of the given users have muted mutedUserID with
+// the given type
+func (r *SQLiteRepository) GetMutingUserIDs(mutedUserID, muteType string, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	query := fmt.Sprintf(`
+		SELECT user_id FROM user_mutes
+		WHERE muted_user_id = ? AND type = ? AND user_id IN (%s)
+	`, placeholders)
+
+	args := make([]interface{}, 0, len(userIDs)+2)
+	args = append(args, mutedUserID, muteType)
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get muting users: %w", err)
+	}
+	defer rows.Close()
+
+	var muting []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan muting user: %w", err)
+		}
+		muting = append(muting, userID)
+	}
+
+	return muting, rows.Err()
+}
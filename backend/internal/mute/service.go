@@ -0,0 +1,151 @@
+package mute
+
+import (
+	"errors"
+
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+	"github.com/Athooh/social-network/pkg/user"
+)
+
+// MutedUser is someone the user has muted, with every type muted
+type MutedUser struct {
+	UserID string   `json:"userId"`
+	Name   string   `json:"name"`
+	Avatar string   `json:"avatar"`
+	Types  []string `json:"types"`
+}
+
+// validMuteTypes lists the mute types a user can set
+var validMuteTypes = map[string]bool{
+	models.MuteTypePosts:         true,
+	models.MuteTypeStatus:        true,
+	models.MuteTypeNotifications: true,
+	models.MuteTypeRestrict:      true,
+}
+
+// Service defines the mute service interface. Mutes are never revealed to
+// the muted user; other services only use them to filter what the muting
+// user sees.
+type Service interface {
+	Mute(userID, mutedUserID, muteType string) error
+	Unmute(userID, mutedUserID, muteType string) error
+	GetMutes(userID string) ([]*MutedUser, error)
+
+	// Checks used by other services
+	IsMuted(userID, mutedUserID, muteType string) bool
+	FilterMuted(userIDs []string, mutedUserID, muteType string) []string
+}
+
+// MuteService implements the Service interface
+type MuteService struct {
+	repo     Repository
+	userRepo user.Repository
+	log      *logger.Logger
+}
+
+// NewService creates a new mute service
+func NewService(repo Repository, userRepo user.Repository, log *logger.Logger) Service {
+	return &MuteService{
+		repo:     repo,
+		userRepo: userRepo,
+		log:      log,
+	}
+}
+
+// Mute mutes or restricts another user
+func (s *MuteService) Mute(userID, mutedUserID, muteType string) error {
+	if !validMuteTypes[muteType] {
+		return errors.New("invalid mute type")
+	}
+	if mutedUserID == "" {
+		return errors.New("user ID is required")
+	}
+	if userID == mutedUserID {
+		return errors.New("you cannot mute yourself")
+	}
+
+	if _, err := s.userRepo.GetByID(mutedUserID); err != nil {
+		return errors.New("user not found")
+	}
+
+	mute := &models.UserMute{
+		UserID:      userID,
+		MutedUserID: mutedUserID,
+		Type:        muteType,
+	}
+	if err := s.repo.SaveMute(mute); err != nil {
+		s.log.Error("Failed to save mute: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Unmute removes a mute or restriction
+func (s *MuteService) Unmute(userID, mutedUserID, muteType string) error {
+	if !validMuteTypes[muteType] {
+		return errors.New("invalid mute type")
+	}
+
+	if err := s.repo.DeleteMute(userID, mutedUserID, muteType); err != nil {
+		s.log.Error("Failed to delete mute: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetMutes gets everyone the user has muted or restricted
+func (s *MuteService) GetMutes(userID string) ([]*MutedUser, error) {
+	mutes, err := s.repo.GetMutes(userID)
+	if err != nil {
+		s.log.Error("Failed to get mutes: %v", err)
+		return nil, err
+	}
+	if mutes == nil {
+		mutes = []*MutedUser{}
+	}
+	return mutes, nil
+}
+
+// IsMuted checks whether userID has muted mutedUserID with the given type. A
+// failed check counts as not muted, so notifications are never lost to it.
+func (s *MuteService) IsMuted(userID, mutedUserID, muteType string) bool {
+	if userID == "" || mutedUserID == "" || userID == mutedUserID {
+		return false
+	}
+
+	muted, err := s.repo.HasMuted(userID, mutedUserID, muteType)
+	if err != nil {
+		s.log.Warn("Failed to check mute: %v", err)
+		return false
+	}
+	return muted
+}
+
+// FilterMuted drops the users who have muted mutedUserID with the given type.
+// On a failed check every user is kept.
+func (s *MuteService) FilterMuted(userIDs []string, mutedUserID, muteType string) []string {
+	muting, err := s.repo.GetMutingUserIDs(mutedUserID, muteType, userIDs)
+	if err != nil {
+		s.log.Warn("Failed to check mutes: %v", err)
+		return userIDs
+	}
+	if len(muting) == 0 {
+		return userIDs
+	}
+
+	skip := make(map[string]bool, len(muting))
+	for _, userID := range muting {
+		skip[userID] = true
+	}
+
+	filtered := make([]string, 0, len(userIDs)-len(muting))
+	for _, userID := range userIDs {
+		if !skip[userID] {
+			filtered = append(filtered, userID)
+		}
+	}
+	return filtered
+}
@@ -2,6 +2,7 @@ package post
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -44,6 +45,7 @@ type PostResponse struct {
 	VideoURL   string               `json:"videoUrl,omitempty"`
 	Privacy    string               `json:"privacy"`
 	LikesCount int                  `json:"likesCount"`
+	ViewsCount int                  `json:"viewsCount"`
 	Comments   []CommentResponse    `json:"comments"`
 	CreatedAt  string               `json:"createdAt"`
 	UpdatedAt  string               `json:"updatedAt"`
@@ -73,6 +75,8 @@ type PostWithCommentsResponse struct {
 	CreatedAt  string               `json:"createdAt"`
 	UpdatedAt  string               `json:"updatedAt"`
 	LikesCount int                  `json:"likesCount"`
+	ViewsCount int                  `json:"viewsCount"`
+	Boosted    bool                 `json:"boosted"`
 	Comments   []CommentResponse    `json:"comments"`
 	UserData   *models.PostUserData `json:"userData"`
 }
@@ -97,6 +101,16 @@ func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	privacy := r.FormValue("privacy")
 	viewers := r.Form["viewers"]
 
+	var audienceListID int64
+	if listIDStr := r.FormValue("audienceListId"); listIDStr != "" {
+		listID, err := strconv.ParseInt(listIDStr, 10, 64)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid audience list ID")
+			return
+		}
+		audienceListID = listID
+	}
+
 	if privacy == "" {
 		h.sendError(w, http.StatusBadRequest, "Privacy field is missing")
 		return
@@ -123,17 +137,14 @@ func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create post
-	post, err := h.service.CreatePost(userID, content, privacy, imageFile, videoFile)
+	post, err := h.service.CreatePost(userID, content, privacy, viewers, audienceListID, imageFile, videoFile)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if len(viewers) > 0 {
-		if err := h.service.SetPostViewers(post.ID, userID, viewers); err != nil {
-			h.sendError(w, http.StatusInternalServerError, err.Error())
+		if errors.Is(err, ErrInvalidAudience) {
+			h.sendError(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
 	// Prepare response
@@ -193,6 +204,7 @@ func (h *Handler) GetPost(w http.ResponseWriter, r *http.Request) {
 		Content:    post.Content,
 		Privacy:    post.Privacy,
 		LikesCount: int(post.LikesCount),
+		ViewsCount: int(post.ViewsCount),
 		CreatedAt:  post.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:  post.UpdatedAt.Format(time.RFC3339),
 		Comments:   make([]CommentResponse, 0, len(comments)),
@@ -270,6 +282,7 @@ func (h *Handler) GetUserPosts(w http.ResponseWriter, r *http.Request) {
 			Content:    post.Content,
 			Privacy:    post.Privacy,
 			LikesCount: int(post.LikesCount),
+			ViewsCount: int(post.ViewsCount),
 			CreatedAt:  post.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:  post.UpdatedAt.Format(time.RFC3339),
 			Comments:   make([]CommentResponse, 0, len(comments)),
@@ -411,6 +424,7 @@ func (h *Handler) GetPublicPosts(w http.ResponseWriter, r *http.Request) {
 			Content:    post.Content,
 			Privacy:    post.Privacy,
 			LikesCount: int(post.LikesCount),
+			ViewsCount: int(post.ViewsCount),
 			CreatedAt:  post.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:  post.UpdatedAt.Format(time.RFC3339),
 			Comments:   make([]CommentResponse, 0, len(comments)),
@@ -603,6 +617,144 @@ func (h *Handler) SetPostViewers(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// HandleAudienceLists handles listing, creating, renaming and deleting audience lists
+func (h *Handler) HandleAudienceLists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		lists, err := h.service.GetAudienceLists(userID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, lists)
+
+	case http.MethodPost:
+		var request struct {
+			Name      string   `json:"name"`
+			MemberIDs []string `json:"memberIds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		list, err := h.service.CreateAudienceList(userID, request.Name)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if len(request.MemberIDs) > 0 {
+			if err := h.service.AddAudienceListMembers(list.ID, userID, request.MemberIDs); err != nil {
+				h.sendError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			list.MemberCount = len(request.MemberIDs)
+		}
+		h.sendJSON(w, http.StatusCreated, list)
+
+	case http.MethodPut:
+		listID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid audience list ID")
+			return
+		}
+
+		var request struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		list, err := h.service.RenameAudienceList(listID, userID, request.Name)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, list)
+
+	case http.MethodDelete:
+		listID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid audience list ID")
+			return
+		}
+
+		if err := h.service.DeleteAudienceList(listID, userID); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// HandleAudienceListMembers handles listing, adding and removing audience list members
+func (h *Handler) HandleAudienceListMembers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID <= "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	listID, err := strconv.ParseInt(r.URL.Query().Get("listId"), 10, 64)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid audience list ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		members, err := h.service.GetAudienceListMembers(listID, userID)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, members)
+
+	case http.MethodPost:
+		var request struct {
+			UserIDs []string `json:"userIds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		if err := h.service.AddAudienceListMembers(listID, userID, request.UserIDs); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		memberID := r.URL.Query().Get("userId")
+		if memberID == "" {
+			h.sendError(w, http.StatusBadRequest, "User ID is required")
+			return
+		}
+
+		if err := h.service.RemoveAudienceListMember(listID, userID, memberID); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
 // CreateComment handles creating a new comment on a post
 func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
@@ -814,6 +966,8 @@ func (h *Handler) GetFeedPosts(w http.ResponseWriter, r *http.Request) {
 			Content:    post.Content,
 			Privacy:    post.Privacy,
 			LikesCount: int(post.LikesCount),
+			ViewsCount: int(post.ViewsCount),
+			Boosted:    post.Boosted,
 			CreatedAt:  post.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:  post.UpdatedAt.Format(time.RFC3339),
 			Comments:   make([]CommentResponse, 0, len(comments)),
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Athooh/social-network/internal/mute"
 	notifications "github.com/Athooh/social-network/internal/notifcations"
 	"github.com/Athooh/social-network/pkg/logger"
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
@@ -19,37 +20,19 @@ type NotificationService struct {
 	userRepo         user.Repository
 	log              *logger.Logger
 	notificationSRVC notifications.Service
+	mutes            mute.Service
 }
 
-func NewNotificationService(hub *websocket.Hub, userRepo user.Repository, notifcationSRVC notifications.Service, log *logger.Logger) *NotificationService {
+func NewNotificationService(hub *websocket.Hub, userRepo user.Repository, notifcationSRVC notifications.Service, mutes mute.Service, log *logger.Logger) *NotificationService {
 	return &NotificationService{
 		hub:              hub,
 		userRepo:         userRepo,
 		log:              log,
 		notificationSRVC: notifcationSRVC,
+		mutes:            mutes,
 	}
 }
 
-func (s *NotificationService) NotifyPostCreated(post interface{}, userID, userName string) error {
-	event := events.Event{
-		Type: events.PostCreated,
-		Payload: events.PostCreatedPayload{
-			Post:     post,
-			UserID:   userID,
-			UserName: userName,
-		},
-	}
-
-	// Convert event to JSON and send it to the broadcast channel
-	eventJSON, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	s.hub.Broadcast <- eventJSON
-	return nil
-}
-
 // NotifyPostCreatedToSpecificUsers sends notifications about a new post to specific users
 func (s *NotificationService) NotifyPostCreatedToSpecificUsers(post *models.Post, userID string, userName string, recipientIDs []string) error {
 	// Create event payload
@@ -65,8 +48,8 @@ func (s *NotificationService) NotifyPostCreatedToSpecificUsers(post *models.Post
 		Payload: payload,
 	}
 
-	// Send to each specific recipient
-	for _, recipientID := range recipientIDs {
+	// Send to each specific recipient, except those who muted the author's posts
+	for _, recipientID := range s.mutes.FilterMuted(recipientIDs, userID, models.MuteTypePosts) {
 		// Don't notify the post creator
 		if recipientID == userID {
 			continue
@@ -184,6 +167,11 @@ func (s *NotificationService) SendCommentNotificationToOwner(userID, commenterID
 		return
 	}
 
+	// The post owner has silenced notifications from the commenter
+	if s.mutes.IsMuted(userID, commenterID, models.MuteTypeNotifications) {
+		return
+	}
+
 	// Fetch commenter details
 	commenter, err := s.userRepo.GetByID(commenterID)
 	if err != nil {
@@ -229,3 +217,56 @@ func (s *NotificationService) SendCommentNotificationToOwner(userID, commenterID
 
 	s.hub.BroadcastToUser(userID, event)
 }
+
+// SendFavoritePostNotification notifies a follower who asked to hear about the
+// author's posts that the author has posted
+func (s *NotificationService) SendFavoritePostNotification(post *models.Post, subscriberID, authorID string) {
+	if s.mutes.IsMuted(subscriberID, authorID, models.MuteTypeNotifications) {
+		return
+	}
+
+	author, err := s.userRepo.GetByID(authorID)
+	if err != nil {
+		s.log.Error("Failed to fetch post author details: %v", err)
+		return
+	}
+	authorName := author.FirstName + " " + author.LastName
+
+	notification := &notifications.NewNotification{
+		UserId:          subscriberID,
+		NotficationType: "favoritePost",
+		SenderId:        sql.NullString{String: authorID, Valid: true},
+		Message:         fmt.Sprintf("%s shared a new post.", authorName),
+	}
+	if err := s.notificationSRVC.CreateNotification(notification); err != nil {
+		s.log.Error("Failed to create favorite post notification: %v", err)
+		return
+	}
+
+	if s.hub == nil {
+		return
+	}
+
+	// Retrieve the newly created notification to get its ID and CreatedAt
+	notifications, err := s.notificationSRVC.GetNotifications(subscriberID, 1, 0)
+	if err != nil || len(notifications) == 0 {
+		s.log.Error("Failed to retrieve newly created notification: %v", err)
+		return
+	}
+	dbNotification := notifications[0]
+
+	s.hub.BroadcastToUser(subscriberID, events.Event{
+		Type: events.HeaderNotificationUpdate,
+		Payload: map[string]interface{}{
+			"id":           dbNotification.ID,
+			"type":         notification.NotficationType,
+			"senderId":     authorID,
+			"senderName":   authorName,
+			"senderAvatar": author.Avatar,
+			"postId":       post.ID,
+			"message":      notification.Message,
+			"createdAt":    dbNotification.CreatedAt.Format(time.RFC3339),
+			"isRead":       dbNotification.IsRead,
+		},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
@@ -11,7 +12,7 @@ import (
 
 // Repository defines the interface for post data access
 type Repository interface {
-	CreatePost(post *models.Post) error
+	CreatePost(post *models.Post, viewerIDs []string) error
 	GetPostByID(id int64) (*models.Post, error)
 	GetPostsByUserID(userID string) ([]*models.Post, error)
 	GetPublicPosts(limit, offset int) ([]*models.Post, error)
@@ -23,11 +24,24 @@ type Repository interface {
 	RemovePostViewer(postID int64, userID string) error
 	GetPostViewers(postID int64) ([]string, error)
 	CanViewPost(postID int64, userID string) (bool, error)
+	GetPostAudience(postID int64) ([]string, error)
+	SetPostAudienceList(postID, listID int64) error
+
+	// Audience list methods
+	CreateAudienceList(list *models.AudienceList) error
+	GetAudienceListByID(listID int64) (*models.AudienceList, error)
+	GetAudienceListsByUserID(userID string) ([]*models.AudienceList, error)
+	UpdateAudienceList(list *models.AudienceList) error
+	DeleteAudienceList(listID int64) error
+	AddAudienceListMember(listID int64, userID string) error
+	RemoveAudienceListMember(listID int64, userID string) error
+	GetAudienceListMembers(listID int64) ([]*models.PostUserData, error)
+	IsFollower(followerID, followingID string) (bool, error)
 
 	// Comment methods
 	CreateComment(comment *models.Comment) error
 	UpdatePostCommentCount(postId int64, increase bool) (int, error)
-	GetCommentsByPostID(postID int64) ([]*models.Comment, error)
+	GetCommentsByPostID(postID int64, viewerID string) ([]*models.Comment, error)
 	DeleteComment(id int64) error
 
 	// Like-related methods
@@ -35,20 +49,28 @@ type Repository interface {
 	UnlikePost(postID int64, userID string) error
 	HasLiked(postID int64, userID string) (bool, error)
 	GetLikesCount(postID int64) (int, error)
-	GetFeedPosts(userID string, limit, offset int) ([]*models.Post, error)
+	GetFeedPosts(userID string, excludeIDs []int64, limit, offset int) ([]*models.Post, error)
+	GetFavoriteFeedPosts(userID string, since time.Time, limit int) ([]*models.Post, error)
 
 	// User data method
 	GetUserDataByID(userID string) (*models.PostUserData, error)
 
 	// New method
 	GetUserFollowers(userID string) ([]string, error)
+	GetPostSubscribers(userID string) ([]string, error)
 	UpdateUserStats(userID string, statsType string, increment bool) (int, error)
 	getNextAvailableID() (int64, error)
 }
 
 // SQLiteRepository implements Repository interface for SQLite
 type SQLiteRepository struct {
-	db *sql.DB
+	db          *sql.DB
+	permissions PermissionChecker
+}
+
+// PermissionChecker checks what members may do in their groups
+type PermissionChecker interface {
+	CanPerform(groupID, userID, action string) (bool, error)
 }
 
 // NewSQLiteRepository creates a new SQLite repository
@@ -56,8 +78,14 @@ func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
 	return &SQLiteRepository{db: db}
 }
 
-// CreatePost creates a new post
-func (r *SQLiteRepository) CreatePost(post *models.Post) error {
+// SetPermissionChecker sets what decides who moderates group posts
+func (r *SQLiteRepository) SetPermissionChecker(permissions PermissionChecker) {
+	r.permissions = permissions
+}
+
+// CreatePost creates a new post along with the users who can view it, if it's
+// private
+func (r *SQLiteRepository) CreatePost(post *models.Post, viewerIDs []string) error {
 	now := time.Now()
 	post.CreatedAt = now
 	post.UpdatedAt = now
@@ -67,11 +95,17 @@ func (r *SQLiteRepository) CreatePost(post *models.Post) error {
 	}
 	post.ID = newid
 	query := `
-		INSERT INTO posts (id, user_id, content, image_path, video_path, privacy, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO posts (id, user_id, content, image_path, video_path, privacy, audience_list_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err = r.db.Exec(
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
 		query,
 		post.ID,
 		post.UserID,
@@ -79,6 +113,7 @@ func (r *SQLiteRepository) CreatePost(post *models.Post) error {
 		post.ImagePath.String,
 		post.VideoPath.String,
 		post.Privacy,
+		post.AudienceListID,
 		post.CreatedAt,
 		post.UpdatedAt,
 	)
@@ -86,19 +121,25 @@ func (r *SQLiteRepository) CreatePost(post *models.Post) error {
 		return err
 	}
 
-	return nil
+	for _, viewerID := range viewerIDs {
+		if _, err := tx.Exec("INSERT INTO post_viewers (post_id, user_id) VALUES (?, ?)", post.ID, viewerID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // GetPostByID retrieves a post by ID
 func (r *SQLiteRepository) GetPostByID(id int64) (*models.Post, error) {
 	query := `
-		SELECT id, user_id, content, image_path, video_path, privacy, likes_count, created_at, updated_at
+		SELECT id, user_id, content, image_path, video_path, privacy, likes_count, views_count, audience_list_id, is_hidden, group_id, created_at, updated_at
 		FROM (
-			SELECT id, user_id, content, image_path, video_path, privacy, likes_count, created_at, updated_at
+			SELECT id, user_id, content, image_path, video_path, privacy, likes_count, views_count, audience_list_id, is_hidden, '' as group_id, created_at, updated_at
 			FROM posts
 			WHERE id = ?
 			UNION ALL
-			SELECT id, user_id, content, image_path, video_path, 'public' as privacy, likes_count, created_at, updated_at
+			SELECT id, user_id, content, image_path, video_path, 'public' as privacy, likes_count, 0 as views_count, 0 as audience_list_id, is_hidden, group_id, created_at, updated_at
 			FROM group_posts
 			WHERE id = ?
 		)
@@ -116,6 +157,10 @@ func (r *SQLiteRepository) GetPostByID(id int64) (*models.Post, error) {
 		&videoPath,
 		&post.Privacy,
 		&post.LikesCount,
+		&post.ViewsCount,
+		&post.AudienceListID,
+		&post.IsHidden,
+		&post.GroupID,
 		&post.CreatedAt,
 		&post.UpdatedAt,
 	)
@@ -140,9 +185,9 @@ func (r *SQLiteRepository) GetPostByID(id int64) (*models.Post, error) {
 // GetPostsByUserID retrieves all posts by a user
 func (r *SQLiteRepository) GetPostsByUserID(userID string) ([]*models.Post, error) {
 	query := `
-		SELECT id, user_id, content, image_path, video_path, privacy, likes_count, created_at, updated_at
+		SELECT id, user_id, content, image_path, video_path, privacy, likes_count, views_count, created_at, updated_at
 		FROM posts
-		WHERE user_id = ?
+		WHERE user_id = ? AND is_hidden = 0
 		ORDER BY created_at DESC
 	`
 
@@ -164,6 +209,7 @@ func (r *SQLiteRepository) GetPostsByUserID(userID string) ([]*models.Post, erro
 			&videoPath,
 			&post.Privacy,
 			&post.LikesCount,
+			&post.ViewsCount,
 			&post.CreatedAt,
 			&post.UpdatedAt,
 		)
@@ -189,9 +235,9 @@ func (r *SQLiteRepository) GetPostsByUserID(userID string) ([]*models.Post, erro
 // GetPublicPosts retrieves public posts with pagination
 func (r *SQLiteRepository) GetPublicPosts(limit, offset int) ([]*models.Post, error) {
 	query := `
-		SELECT id, user_id, content, image_path, video_path, privacy, likes_count, created_at, updated_at
+		SELECT id, user_id, content, image_path, video_path, privacy, likes_count, views_count, created_at, updated_at
 		FROM posts
-		WHERE privacy = 'public'
+		WHERE privacy = 'public' AND is_hidden = 0
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`
@@ -214,6 +260,7 @@ func (r *SQLiteRepository) GetPublicPosts(limit, offset int) ([]*models.Post, er
 			&videoPath,
 			&post.Privacy,
 			&post.LikesCount,
+			&post.ViewsCount,
 			&post.CreatedAt,
 			&post.UpdatedAt,
 		)
@@ -252,11 +299,27 @@ func (r *SQLiteRepository) UpdatePost(post *models.Post) error {
 	return err
 }
 
-// DeletePost deletes a post
+// DeletePost deletes a post with its comments, likes, viewers and bookmarks
 func (r *SQLiteRepository) DeletePost(id int64) error {
-	query := "DELETE FROM posts WHERE id = ?"
-	_, err := r.db.Exec(query, id)
-	return err
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		"DELETE FROM comments WHERE post_id = ?",
+		"DELETE FROM post_likes WHERE post_id = ?",
+		"DELETE FROM post_viewers WHERE post_id = ?",
+		"DELETE FROM bookmarks WHERE post_id = ?",
+		"DELETE FROM posts WHERE id = ?",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // AddPostViewer adds a user who can view a private post
@@ -312,6 +375,19 @@ func (r *SQLiteRepository) CanViewPost(postID int64, userID string) (bool, error
 		return false, errors.New("post not found")
 	}
 
+	// Posts hidden by moderation can only be seen by their author and moderators
+	if post.IsHidden && post.UserID != userID {
+		isModerator, err := r.isModerator(userID, post.GroupID)
+		if err != nil || !isModerator {
+			return false, err
+		}
+	}
+
+	// Group posts can only be seen by the group's members, once approved
+	if post.GroupID != "" {
+		return r.canViewGroupPost(postID, userID)
+	}
+
 	// If the user is the post creator, they can always view it
 	if post.UserID == userID {
 		return true, nil
@@ -331,19 +407,273 @@ func (r *SQLiteRepository) CanViewPost(postID int64, userID string) (bool, error
 		}
 		return count > 0, nil
 	case models.PrivacyPrivate:
-		// Check if the user is in the post_viewers table
-		query := "SELECT COUNT(*) FROM post_viewers WHERE post_id = ? AND user_id = ?"
-		var count int
-		err := r.db.QueryRow(query, postID, userID).Scan(&count)
+		// Check if the user is in the post_viewers table or, for posts shared
+		// with an audience list, is currently on that list and still follows the author
+		query := `
+			SELECT EXISTS (
+				SELECT 1 FROM post_viewers WHERE post_id = ? AND user_id = ?
+			) OR EXISTS (
+				SELECT 1 FROM audience_list_members alm
+				JOIN followers f ON f.follower_id = alm.user_id AND f.following_id = ?
+				WHERE alm.list_id = ? AND alm.user_id = ?
+			)
+		`
+		var canView bool
+		err := r.db.QueryRow(query, postID, userID, post.UserID, post.AudienceListID, userID).Scan(&canView)
 		if err != nil {
 			return false, err
 		}
-		return count > 0, nil
+		return canView, nil
 	default:
 		return false, errors.New("invalid privacy setting")
 	}
 }
 
+// isModerator checks whether the user is a site moderator or, for a group
+// post, may delete the group's posts
+func (r *SQLiteRepository) isModerator(userID, groupID string) (bool, error) {
+	var isModerator bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM users WHERE id = ? AND is_moderator = 1)", userID).Scan(&isModerator)
+	if err != nil || isModerator {
+		return isModerator, err
+	}
+
+	if groupID == "" || r.permissions == nil {
+		return false, nil
+	}
+	return r.permissions.CanPerform(groupID, userID, models.GroupActionDeletePosts)
+}
+
+// canViewGroupPost checks that a group post is approved and the user is an
+// accepted member of its group, which hasn't been deleted
+func (r *SQLiteRepository) canViewGroupPost(postID int64, userID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM group_posts gp
+			JOIN groups g ON g.id = gp.group_id AND g.archived_at IS NULL
+			JOIN group_members gm ON gm.group_id = gp.group_id AND gm.user_id = ? AND gm.status = 'accepted'
+			WHERE gp.id = ? AND gp.status = ?
+		)
+	`
+	var canView bool
+	err := r.db.QueryRow(query, userID, postID, models.GroupPostApproved).Scan(&canView)
+	if err != nil {
+		return false, err
+	}
+	return canView, nil
+}
+
+// GetPostAudience gets every user who can currently view a private post, combining
+// explicit post viewers with the following members of the post's audience list
+func (r *SQLiteRepository) GetPostAudience(postID int64) ([]string, error) {
+	query := `
+		SELECT pv.user_id FROM post_viewers pv WHERE pv.post_id = ?
+		UNION
+		SELECT alm.user_id
+		FROM posts p
+		JOIN audience_list_members alm ON alm.list_id = p.audience_list_id
+		JOIN followers f ON f.follower_id = alm.user_id AND f.following_id = p.user_id
+		WHERE p.id = ? AND p.audience_list_id > 0
+	`
+	rows, err := r.db.Query(query, postID, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// SetPostAudienceList attaches an audience list to a post (0 clears it)
+func (r *SQLiteRepository) SetPostAudienceList(postID, listID int64) error {
+	query := "UPDATE posts SET audience_list_id = ?, updated_at = ? WHERE id = ?"
+	_, err := r.db.Exec(query, listID, time.Now(), postID)
+	return err
+}
+
+// CreateAudienceList creates a new audience list
+func (r *SQLiteRepository) CreateAudienceList(list *models.AudienceList) error {
+	now := time.Now()
+	list.CreatedAt = now
+	list.UpdatedAt = now
+
+	query := `
+		INSERT INTO audience_lists (user_id, name, is_close_friends, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	return r.db.QueryRow(
+		query,
+		list.UserID,
+		list.Name,
+		list.IsCloseFriends,
+		list.CreatedAt,
+		list.UpdatedAt,
+	).Scan(&list.ID)
+}
+
+// GetAudienceListByID retrieves an audience list by ID
+func (r *SQLiteRepository) GetAudienceListByID(listID int64) (*models.AudienceList, error) {
+	query := `
+		SELECT al.id, al.user_id, al.name, al.is_close_friends, al.created_at, al.updated_at,
+			(SELECT COUNT(*) FROM audience_list_members alm WHERE alm.list_id = al.id)
+		FROM audience_lists al
+		WHERE al.id = ?
+	`
+
+	list := &models.AudienceList{}
+	err := r.db.QueryRow(query, listID).Scan(
+		&list.ID,
+		&list.UserID,
+		&list.Name,
+		&list.IsCloseFriends,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+		&list.MemberCount,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetAudienceListsByUserID retrieves all audience lists owned by a user
+func (r *SQLiteRepository) GetAudienceListsByUserID(userID string) ([]*models.AudienceList, error) {
+	query := `
+		SELECT al.id, al.user_id, al.name, al.is_close_friends, al.created_at, al.updated_at,
+			(SELECT COUNT(*) FROM audience_list_members alm WHERE alm.list_id = al.id)
+		FROM audience_lists al
+		WHERE al.user_id = ?
+		ORDER BY al.is_close_friends DESC, al.name ASC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []*models.AudienceList
+	for rows.Next() {
+		list := &models.AudienceList{}
+		if err := rows.Scan(
+			&list.ID,
+			&list.UserID,
+			&list.Name,
+			&list.IsCloseFriends,
+			&list.CreatedAt,
+			&list.UpdatedAt,
+			&list.MemberCount,
+		); err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+
+	return lists, rows.Err()
+}
+
+// UpdateAudienceList updates an audience list's name
+func (r *SQLiteRepository) UpdateAudienceList(list *models.AudienceList) error {
+	list.UpdatedAt = time.Now()
+	query := "UPDATE audience_lists SET name = ?, updated_at = ? WHERE id = ?"
+	_, err := r.db.Exec(query, list.Name, list.UpdatedAt, list.ID)
+	return err
+}
+
+// DeleteAudienceList deletes an audience list, its members and detaches it from posts
+func (r *SQLiteRepository) DeleteAudienceList(listID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM audience_list_members WHERE list_id = ?", listID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE posts SET audience_list_id = 0 WHERE audience_list_id = ?", listID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM audience_lists WHERE id = ?", listID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddAudienceListMember adds a user to an audience list if not already present
+func (r *SQLiteRepository) AddAudienceListMember(listID int64, userID string) error {
+	query := `
+		INSERT INTO audience_list_members (list_id, user_id, created_at)
+		SELECT ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM audience_list_members WHERE list_id = ? AND user_id = ?
+		)
+	`
+	_, err := r.db.Exec(query, listID, userID, time.Now(), listID, userID)
+	return err
+}
+
+// RemoveAudienceListMember removes a user from an audience list
+func (r *SQLiteRepository) RemoveAudienceListMember(listID int64, userID string) error {
+	query := "DELETE FROM audience_list_members WHERE list_id = ? AND user_id = ?"
+	_, err := r.db.Exec(query, listID, userID)
+	return err
+}
+
+// GetAudienceListMembers gets basic user data for every member of an audience list
+func (r *SQLiteRepository) GetAudienceListMembers(listID int64) ([]*models.PostUserData, error) {
+	query := `
+		SELECT u.id, u.first_name, u.last_name, u.avatar
+		FROM audience_list_members alm
+		JOIN users u ON u.id = alm.user_id
+		WHERE alm.list_id = ?
+		ORDER BY u.first_name, u.last_name
+	`
+
+	rows, err := r.db.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*models.PostUserData
+	for rows.Next() {
+		member := &models.PostUserData{}
+		var avatar sql.NullString
+		if err := rows.Scan(&member.ID, &member.FirstName, &member.LastName, &avatar); err != nil {
+			return nil, err
+		}
+		member.Avatar = avatar.String
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// IsFollower checks whether followerID follows followingID
+func (r *SQLiteRepository) IsFollower(followerID, followingID string) (bool, error) {
+	var exists bool
+	query := "SELECT EXISTS(SELECT 1 FROM followers WHERE follower_id = ? AND following_id = ?)"
+	err := r.db.QueryRow(query, followerID, followingID).Scan(&exists)
+	return exists, err
+}
+
 // CreateComment creates a new comment
 func (r *SQLiteRepository) CreateComment(comment *models.Comment) error {
 	now := time.Now()
@@ -369,16 +699,27 @@ func (r *SQLiteRepository) CreateComment(comment *models.Comment) error {
 	return err
 }
 
-// GetCommentsByPostID retrieves all comments for a post
-func (r *SQLiteRepository) GetCommentsByPostID(postID int64) ([]*models.Comment, error) {
+// GetCommentsByPostID retrieves all comments for a post that the viewer can
+// see. Comments by someone the post owner has restricted are only visible to
+// the commenter and the owner.
+func (r *SQLiteRepository) GetCommentsByPostID(postID int64, viewerID string) ([]*models.Comment, error) {
 	query := `
-		SELECT id, post_id, user_id, content, image_path, created_at, updated_at
-		FROM comments
-		WHERE post_id = ?
-		ORDER BY created_at DESC
+		SELECT c.id, c.post_id, c.user_id, c.content, c.image_path, c.created_at, c.updated_at
+		FROM comments c
+		LEFT JOIN (SELECT id, user_id FROM posts UNION ALL SELECT id, user_id FROM group_posts) p ON p.id = c.post_id
+		WHERE c.post_id = ? AND c.is_hidden = 0 AND (
+			c.user_id = ?
+			OR p.user_id IS NULL
+			OR p.user_id = ?
+			OR NOT EXISTS (
+				SELECT 1 FROM user_mutes m
+				WHERE m.user_id = p.user_id AND m.muted_user_id = c.user_id AND m.type = ?
+			)
+		)
+		ORDER BY c.created_at DESC
 	`
 
-	rows, err := r.db.Query(query, postID)
+	rows, err := r.db.Query(query, postID, viewerID, viewerID, models.MuteTypeRestrict)
 	if err != nil {
 		return nil, err
 	}
@@ -520,23 +861,75 @@ func (r *SQLiteRepository) GetLikesCount(postID int64) (int, error) {
 	return count, err
 }
 
-// GetFeedPosts gets posts visible to the user
-func (r *SQLiteRepository) GetFeedPosts(userID string, limit, offset int) ([]*models.Post, error) {
-	query := `
-		SELECT DISTINCT p.* 
-		FROM posts p
-		LEFT JOIN followers f ON p.user_id = f.following_id
-		LEFT JOIN post_viewers pv ON p.id = pv.post_id
-		WHERE 
-			p.privacy = 'public'
-			OR p.user_id = ?
-			OR (p.privacy = 'almost_private' AND f.follower_id = ?)
-			OR (p.privacy = 'private' AND pv.user_id = ?)
+// feedPostsQuery selects the posts visible to a user, leaving out authors
+// whose posts the user has muted. Every placeholder takes the user's ID except
+// the last, which takes models.MuteTypePosts.
+const feedPostsQuery = `
+	SELECT p.id, p.user_id, p.content, p.image_path, p.video_path, p.privacy,
+		p.likes_count, p.comments_count, p.views_count, p.created_at, p.updated_at
+	FROM posts p
+	WHERE p.is_hidden = 0 AND (
+		p.privacy = 'public'
+		OR p.user_id = ?
+		OR (p.privacy = 'almost_private' AND EXISTS (
+			SELECT 1 FROM followers f WHERE f.following_id = p.user_id AND f.follower_id = ?
+		))
+		OR (p.privacy = 'private' AND EXISTS (
+			SELECT 1 FROM post_viewers pv WHERE pv.post_id = p.id AND pv.user_id = ?
+		))
+		OR (p.privacy = 'private' AND p.audience_list_id > 0 AND EXISTS (
+			SELECT 1 FROM audience_list_members alm
+			JOIN followers f ON f.follower_id = alm.user_id AND f.following_id = p.user_id
+			WHERE alm.list_id = p.audience_list_id AND alm.user_id = ?
+		))
+	) AND NOT EXISTS (
+		SELECT 1 FROM user_mutes m
+		WHERE m.user_id = ? AND m.muted_user_id = p.user_id AND m.type = ?
+	)
+`
+
+// GetFeedPosts gets posts visible to the user, leaving out authors whose posts
+// the user has muted and the posts with the excluded IDs
+func (r *SQLiteRepository) GetFeedPosts(userID string, excludeIDs []int64, limit, offset int) ([]*models.Post, error) {
+	query := feedPostsQuery
+	args := []interface{}{userID, userID, userID, userID, userID, models.MuteTypePosts}
+
+	if len(excludeIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(excludeIDs)), ",")
+		query += " AND p.id NOT IN (" + placeholders + ")"
+		for _, id := range excludeIDs {
+			args = append(args, id)
+		}
+	}
+
+	query += `
 		ORDER BY p.created_at DESC
 		LIMIT ? OFFSET ?
 	`
+	args = append(args, limit, offset)
+
+	return r.queryFeedPosts(query, args...)
+}
+
+// GetFavoriteFeedPosts gets the newest feed posts made since the given time by
+// users the user has marked as favorites
+func (r *SQLiteRepository) GetFavoriteFeedPosts(userID string, since time.Time, limit int) ([]*models.Post, error) {
+	query := feedPostsQuery + `
+		AND p.created_at >= ?
+		AND EXISTS (
+			SELECT 1 FROM followers fav
+			WHERE fav.follower_id = ? AND fav.following_id = p.user_id AND fav.is_favorite = 1
+		)
+		ORDER BY p.created_at DESC
+		LIMIT ?
+	`
+
+	return r.queryFeedPosts(query, userID, userID, userID, userID, userID, models.MuteTypePosts, since, userID, limit)
+}
 
-	rows, err := r.db.Query(query, userID, userID, userID, limit, offset)
+// queryFeedPosts runs a query built on feedPostsQuery
+func (r *SQLiteRepository) queryFeedPosts(query string, args ...interface{}) ([]*models.Post, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -557,6 +950,7 @@ func (r *SQLiteRepository) GetFeedPosts(userID string, limit, offset int) ([]*mo
 			&post.Privacy,
 			&post.LikesCount,
 			&post.CommentsCount,
+			&post.ViewsCount,
 			&post.CreatedAt,
 			&post.UpdatedAt,
 		)
@@ -718,6 +1112,32 @@ func (r *SQLiteRepository) GetUserFollowers(userID string) ([]string, error) {
 	return followerIDs, nil
 }
 
+// GetPostSubscribers returns the IDs of followers who marked the user as a
+// favorite and asked to be notified of their posts
+func (r *SQLiteRepository) GetPostSubscribers(userID string) ([]string, error) {
+	query := `
+		SELECT follower_id FROM followers
+		WHERE following_id = ? AND is_favorite = 1 AND notify_on_post = 1
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriberIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		subscriberIDs = append(subscriberIDs, id)
+	}
+
+	return subscriberIDs, rows.Err()
+}
+
 // UpdateUserStats updates or inserts a user's statistics
 func (r *SQLiteRepository) UpdateUserStats(userID string, statsType string, increment bool) (int, error) {
 	// If statsType is posts_count, get the actual count from posts table
@@ -2,25 +2,55 @@ package post
 
 import (
 	"errors"
+	"fmt"
 	"mime/multipart"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Athooh/social-network/internal/analytics"
+	"github.com/Athooh/social-network/internal/contentfilter"
 	"github.com/Athooh/social-network/pkg/filestore"
 	"github.com/Athooh/social-network/pkg/logger"
 	models "github.com/Athooh/social-network/pkg/models/dbTables"
 )
 
+const (
+	closeFriendsListName      = "Close friends"
+	maxAudienceListNameLength = 50
+
+	// Favorites' posts from this window are pinned to the top of the feed
+	favoriteBoostWindow = 24 * time.Hour
+	maxBoostedPosts     = 3
+)
+
+// ErrInvalidAudience is returned when a new post's viewers or audience list
+// can't be used
+var ErrInvalidAudience = errors.New("invalid post audience")
+
 // Service defines the post service interface
 type Service interface {
-	CreatePost(userID string, content, privacy string, image, video *multipart.FileHeader) (*models.Post, error)
+	CreatePost(userID string, content, privacy string, viewerIDs []string, audienceListID int64, image, video *multipart.FileHeader) (*models.Post, error)
 	GetPost(postID int64, userID string) (*models.Post, error)
 	GetUserPosts(userID, viewerID string) ([]*models.Post, error)
 	GetPublicPosts(limit, offset int) ([]*models.Post, error)
 	UpdatePost(postID int64, userID string, content, privacy string, image, video *multipart.FileHeader) (*models.Post, error)
 	DeletePost(postID int64, userID string) error
+	RemovePost(postID int64) error
+	CanViewPost(postID int64, userID string) (bool, error)
 
 	// Privacy management
 	SetPostViewers(postID int64, userID string, viewerIDs []string) error
+	SetPostAudienceList(postID int64, userID string, listID int64) error
+
+	// Audience lists
+	CreateAudienceList(userID, name string) (*models.AudienceList, error)
+	GetAudienceLists(userID string) ([]*models.AudienceList, error)
+	RenameAudienceList(listID int64, userID, name string) (*models.AudienceList, error)
+	DeleteAudienceList(listID int64, userID string) error
+	AddAudienceListMembers(listID int64, userID string, memberIDs []string) error
+	RemoveAudienceListMember(listID int64, userID, memberID string) error
+	GetAudienceListMembers(listID int64, userID string) ([]*models.PostUserData, error)
 
 	// Comments
 	CreateComment(postID int64, userID string, content string, image *multipart.FileHeader) (*models.Comment, error)
@@ -43,49 +73,62 @@ type PostService struct {
 	fileStore       *filestore.FileStore
 	log             *logger.Logger
 	notificationSvc *NotificationService
+	contentFilter   *contentfilter.Pipeline
+	impressions     *analytics.Recorder
 }
 
 // NewService creates a new post service
-func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Logger, notificationSvc *NotificationService) Service {
+func NewService(repo Repository, fileStore *filestore.FileStore, log *logger.Logger, notificationSvc *NotificationService, contentFilter *contentfilter.Pipeline, impressions *analytics.Recorder) Service {
 	return &PostService{
 		repo:            repo,
 		fileStore:       fileStore,
 		log:             log,
 		notificationSvc: notificationSvc,
+		contentFilter:   contentFilter,
+		impressions:     impressions,
 	}
 }
 
-// NotifyPostCreated sends notifications about a new post to appropriate users
+// NotifyPostCreated delivers a new post to the users who can see it and
+// follow the author, and notifies the followers who asked to hear about the
+// author's posts
 func (s *PostService) NotifyPostCreated(post *models.Post, userID string, userName string) error {
 	if s.notificationSvc == nil {
 		return nil
 	}
 
-	// For publNewNotificationic posts, notify all users
-	if post.Privacy == models.PrivacyPublic {
-		return s.notificationSvc.NotifyPostCreated(post, userID, userName)
+	var recipients []string
+	var err error
+	if post.Privacy == models.PrivacyPrivate {
+		// Only the post's audience
+		recipients, err = s.repo.GetPostAudience(post.ID)
+	} else {
+		// Public and almost private posts go to followers
+		recipients, err = s.repo.GetUserFollowers(userID)
+	}
+	if err != nil {
+		s.log.Error("Failed to get post recipients for notification: %v", err)
+		return err
 	}
 
-	// For private posts, only notify allowed viewers
-	if post.Privacy == models.PrivacyPrivate {
-		viewers, err := s.repo.GetPostViewers(post.ID)
-		if err != nil {
-			s.log.Error("Failed to get post viewers for notification: %v", err)
-			return err
-		}
+	if err := s.notificationSvc.NotifyPostCreatedToSpecificUsers(post, userID, userName, recipients); err != nil {
+		return err
+	}
 
-		return s.notificationSvc.NotifyPostCreatedToSpecificUsers(post, userID, userName, viewers)
+	subscribers, err := s.repo.GetPostSubscribers(userID)
+	if err != nil {
+		s.log.Error("Failed to get post subscribers for notification: %v", err)
+		return err
 	}
 
-	// For almost private posts, notify friends/followers
-	if post.Privacy == models.PrivacyAlmostPrivate {
-		followers, err := s.repo.GetUserFollowers(userID)
-		if err != nil {
-			s.log.Error("Failed to get user followers for notification: %v", err)
-			return err
+	canView := make(map[string]bool, len(recipients))
+	for _, recipientID := range recipients {
+		canView[recipientID] = true
+	}
+	for _, subscriberID := range subscribers {
+		if canView[subscriberID] {
+			s.notificationSvc.SendFavoritePostNotification(post, subscriberID, userID)
 		}
-
-		return s.notificationSvc.NotifyPostCreatedToSpecificUsers(post, userID, userName, followers)
 	}
 
 	return nil
@@ -109,7 +152,7 @@ func (s *PostService) NotifyOnCommentCreateOrCount(postId int64, userID string,
 
 	// For private posts, only notify allowed viewers
 	if post.Privacy == models.PrivacyPrivate {
-		viewers, err := s.repo.GetPostViewers(post.ID)
+		viewers, err := s.repo.GetPostAudience(post.ID)
 		if err != nil {
 			s.log.Error("Failed to get post viewers for notification: %v", err)
 			return err
@@ -132,18 +175,47 @@ func (s *PostService) NotifyOnCommentCreateOrCount(postId int64, userID string,
 	return nil
 }
 
-// CreatePost creates a new post
-func (s *PostService) CreatePost(userID string, content, privacy string, image, video *multipart.FileHeader) (*models.Post, error) {
+// CreatePost creates a new post. Private posts can be shared with viewers and
+// one of the user's audience lists, which are saved with the post so its
+// audience is notified.
+func (s *PostService) CreatePost(userID string, content, privacy string, viewerIDs []string, audienceListID int64, image, video *multipart.FileHeader) (*models.Post, error) {
 	// Validate privacy setting
 	if privacy != models.PrivacyPublic && privacy != models.PrivacyAlmostPrivate && privacy != models.PrivacyPrivate {
 		return nil, errors.New("invalid privacy setting")
 	}
 
+	// Check the audience before saving anything
+	if (len(viewerIDs) > 0 || audienceListID != 0) && privacy != models.PrivacyPrivate {
+		return nil, fmt.Errorf("%w: viewers and audience lists can only be set for private posts", ErrInvalidAudience)
+	}
+	if audienceListID != 0 {
+		if _, err := s.getOwnedAudienceList(audienceListID, userID); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAudience, err)
+		}
+	}
+
+	uniqueViewerIDs := make([]string, 0, len(viewerIDs))
+	seenViewers := make(map[string]bool, len(viewerIDs))
+	for _, viewerID := range viewerIDs {
+		if viewerID != "" && viewerID != userID && !seenViewers[viewerID] {
+			seenViewers[viewerID] = true
+			uniqueViewerIDs = append(uniqueViewerIDs, viewerID)
+		}
+	}
+
+	// Run the text through the content filters before saving anything
+	filterContent := contentfilter.Content{UserID: userID, Kind: contentfilter.KindPost, Text: content}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
 	// Create post object
 	post := &models.Post{
-		UserID:  userID,
-		Content: content,
-		Privacy: privacy,
+		UserID:         userID,
+		Content:        content,
+		Privacy:        privacy,
+		AudienceListID: audienceListID,
 	}
 
 	// Handle image upload if provided
@@ -167,11 +239,13 @@ func (s *PostService) CreatePost(userID string, content, privacy string, image,
 	}
 
 	// Save post to database
-	if err := s.repo.CreatePost(post); err != nil {
+	if err := s.repo.CreatePost(post, uniqueViewerIDs); err != nil {
 		s.log.Error("Failed to create post: %v", err)
 		return nil, err
 	}
 
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(post.ID, 10))
+
 	// Get user data for the post
 	userData, err := s.repo.GetUserDataByID(userID)
 	if err != nil {
@@ -265,6 +339,8 @@ func (s *PostService) GetUserPosts(userID, viewerID string) ([]*models.Post, err
 		}
 	}
 
+	s.impressions.RecordImpressions(viewerID, viewablePosts)
+
 	return viewablePosts, nil
 }
 
@@ -309,6 +385,13 @@ func (s *PostService) UpdatePost(postID int64, userID string, content, privacy s
 		return nil, errors.New("invalid privacy setting")
 	}
 
+	// Run the new text through the content filters before saving anything
+	filterContent := contentfilter.Content{UserID: userID, Kind: contentfilter.KindPost, Text: content, IsEdit: true}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
 	// Update post fields
 	post.Content = content
 	post.Privacy = privacy
@@ -337,6 +420,8 @@ func (s *PostService) UpdatePost(postID int64, userID string, content, privacy s
 		return nil, err
 	}
 
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(post.ID, 10))
+
 	return post, nil
 }
 
@@ -358,6 +443,26 @@ func (s *PostService) DeletePost(postID int64, userID string) error {
 		return errors.New("you don't have permission to delete this post")
 	}
 
+	return s.removePost(post)
+}
+
+// RemovePost deletes a post whoever wrote it, for moderation
+func (s *PostService) RemovePost(postID int64) error {
+	post, err := s.repo.GetPostByID(postID)
+	if err != nil {
+		s.log.Error("Failed to get post for removal: %v", err)
+		return err
+	}
+
+	if post == nil || post.GroupID != "" {
+		return errors.New("post not found")
+	}
+
+	return s.removePost(post)
+}
+
+// removePost deletes a post with its files and updates its author's post count
+func (s *PostService) removePost(post *models.Post) error {
 	// Delete the post image if exists
 	if post.ImagePath.String != "" {
 		if err := s.fileStore.DeleteFile(post.ImagePath.String); err != nil {
@@ -373,14 +478,30 @@ func (s *PostService) DeletePost(postID int64, userID string) error {
 	}
 
 	// Delete the post
-	if err := s.repo.DeletePost(postID); err != nil {
+	if err := s.repo.DeletePost(post.ID); err != nil {
 		s.log.Error("Failed to delete post: %v", err)
 		return err
 	}
 
+	// Update user stats
+	newCount, err := s.repo.UpdateUserStats(post.UserID, "posts_count", false)
+	if err != nil {
+		s.log.Error("Failed to update user stats: %v", err)
+	}
+
+	// Notify user stats updated
+	if s.notificationSvc != nil {
+		go s.notificationSvc.NotifyUserStatsUpdated(post.UserID, "Posts", newCount)
+	}
+
 	return nil
 }
 
+// CanViewPost checks whether the user can see a post
+func (s *PostService) CanViewPost(postID int64, userID string) (bool, error) {
+	return s.repo.CanViewPost(postID, userID)
+}
+
 // SetPostViewers sets the users who can view a private post
 func (s *PostService) SetPostViewers(postID int64, userID string, viewerIDs []string) error {
 	// Get the post
@@ -445,6 +566,216 @@ func (s *PostService) SetPostViewers(postID int64, userID string, viewerIDs []st
 	return nil
 }
 
+// SetPostAudienceList shares a private post with one of the owner's audience lists.
+// Membership is resolved when the post is viewed, so later list edits apply to the post.
+func (s *PostService) SetPostAudienceList(postID int64, userID string, listID int64) error {
+	post, err := s.repo.GetPostByID(postID)
+	if err != nil {
+		s.log.Error("Failed to get post for setting audience list: %v", err)
+		return err
+	}
+
+	if post == nil {
+		return errors.New("post not found")
+	}
+
+	if post.UserID != userID {
+		return errors.New("you don't have permission to set the audience for this post")
+	}
+
+	if post.Privacy != models.PrivacyPrivate {
+		return errors.New("audience lists can only be set for private posts")
+	}
+
+	if listID != 0 {
+		if _, err := s.getOwnedAudienceList(listID, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.SetPostAudienceList(postID, listID); err != nil {
+		s.log.Error("Failed to set post audience list: %v", err)
+		return err
+	}
+
+	post.AudienceListID = listID
+	return nil
+}
+
+// CreateAudienceList creates a new named audience list for the user
+func (s *PostService) CreateAudienceList(userID, name string) (*models.AudienceList, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("list name is required")
+	}
+	if len(name) > maxAudienceListNameLength {
+		return nil, errors.New("list name is too long")
+	}
+
+	list := &models.AudienceList{
+		UserID: userID,
+		Name:   name,
+	}
+
+	if err := s.repo.CreateAudienceList(list); err != nil {
+		s.log.Error("Failed to create audience list: %v", err)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetAudienceLists gets the user's audience lists, creating the built-in
+// close friends list on first use
+func (s *PostService) GetAudienceLists(userID string) ([]*models.AudienceList, error) {
+	lists, err := s.repo.GetAudienceListsByUserID(userID)
+	if err != nil {
+		s.log.Error("Failed to get audience lists: %v", err)
+		return nil, err
+	}
+
+	for _, list := range lists {
+		if list.IsCloseFriends {
+			return lists, nil
+		}
+	}
+
+	closeFriends := &models.AudienceList{
+		UserID:         userID,
+		Name:           closeFriendsListName,
+		IsCloseFriends: true,
+	}
+	if err := s.repo.CreateAudienceList(closeFriends); err != nil {
+		s.log.Error("Failed to create close friends list: %v", err)
+		return nil, err
+	}
+
+	return append([]*models.AudienceList{closeFriends}, lists...), nil
+}
+
+// RenameAudienceList renames one of the user's custom audience lists
+func (s *PostService) RenameAudienceList(listID int64, userID, name string) (*models.AudienceList, error) {
+	list, err := s.getOwnedAudienceList(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.IsCloseFriends {
+		return nil, errors.New("the close friends list cannot be renamed")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("list name is required")
+	}
+	if len(name) > maxAudienceListNameLength {
+		return nil, errors.New("list name is too long")
+	}
+
+	list.Name = name
+	if err := s.repo.UpdateAudienceList(list); err != nil {
+		s.log.Error("Failed to rename audience list: %v", err)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteAudienceList deletes one of the user's custom audience lists. Posts
+// shared with the list remain visible to their explicit viewers only.
+func (s *PostService) DeleteAudienceList(listID int64, userID string) error {
+	list, err := s.getOwnedAudienceList(listID, userID)
+	if err != nil {
+		return err
+	}
+
+	if list.IsCloseFriends {
+		return errors.New("the close friends list cannot be deleted")
+	}
+
+	if err := s.repo.DeleteAudienceList(listID); err != nil {
+		s.log.Error("Failed to delete audience list: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// AddAudienceListMembers adds followers of the user to one of their audience lists
+func (s *PostService) AddAudienceListMembers(listID int64, userID string, memberIDs []string) error {
+	if _, err := s.getOwnedAudienceList(listID, userID); err != nil {
+		return err
+	}
+
+	for _, memberID := range memberIDs {
+		isFollower, err := s.repo.IsFollower(memberID, userID)
+		if err != nil {
+			s.log.Error("Failed to check follower for audience list: %v", err)
+			return err
+		}
+		if !isFollower {
+			return errors.New("only followers can be added to an audience list")
+		}
+	}
+
+	for _, memberID := range memberIDs {
+		if err := s.repo.AddAudienceListMember(listID, memberID); err != nil {
+			s.log.Error("Failed to add audience list member: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveAudienceListMember removes a user from one of the user's audience lists
+func (s *PostService) RemoveAudienceListMember(listID int64, userID, memberID string) error {
+	if _, err := s.getOwnedAudienceList(listID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.RemoveAudienceListMember(listID, memberID); err != nil {
+		s.log.Error("Failed to remove audience list member: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAudienceListMembers gets the members of one of the user's audience lists
+func (s *PostService) GetAudienceListMembers(listID int64, userID string) ([]*models.PostUserData, error) {
+	if _, err := s.getOwnedAudienceList(listID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetAudienceListMembers(listID)
+	if err != nil {
+		s.log.Error("Failed to get audience list members: %v", err)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// getOwnedAudienceList loads an audience list and checks it belongs to the user
+func (s *PostService) getOwnedAudienceList(listID int64, userID string) (*models.AudienceList, error) {
+	list, err := s.repo.GetAudienceListByID(listID)
+	if err != nil {
+		s.log.Error("Failed to get audience list: %v", err)
+		return nil, err
+	}
+
+	if list == nil {
+		return nil, errors.New("audience list not found")
+	}
+
+	if list.UserID != userID {
+		return nil, errors.New("you don't have permission to manage this audience list")
+	}
+
+	return list, nil
+}
+
 // CreateComment creates a new comment on a post
 func (s *PostService) CreateComment(postID int64, userID string, content string, image *multipart.FileHeader) (*models.Comment, error) {
 	// Check if the user can view the post (and thus comment on it)
@@ -458,6 +789,13 @@ func (s *PostService) CreateComment(postID int64, userID string, content string,
 		return nil, errors.New("you don't have permission to comment on this post")
 	}
 
+	// Run the text through the content filters before saving anything
+	filterContent := contentfilter.Content{UserID: userID, Kind: contentfilter.KindComment, Text: content}
+	decision := s.contentFilter.Check(filterContent)
+	if !decision.Allowed() {
+		return nil, decision.Err()
+	}
+
 	// Create comment object
 	comment := &models.Comment{
 		PostID:  postID,
@@ -481,6 +819,8 @@ func (s *PostService) CreateComment(postID int64, userID string, content string,
 		return nil, err
 	}
 
+	s.contentFilter.ReportFlagged(decision, filterContent, strconv.FormatInt(comment.ID, 10))
+
 	// Update user stats
 	newCount, err := s.repo.UpdatePostCommentCount(postID, true)
 	if err != nil {
@@ -509,7 +849,7 @@ func (s *PostService) GetPostComments(postID int64, userID string) ([]*models.Co
 	}
 
 	// Get comments
-	comments, err := s.repo.GetCommentsByPostID(postID)
+	comments, err := s.repo.GetCommentsByPostID(postID, userID)
 	if err != nil {
 		s.log.Error("Failed to get post comments: %v", err)
 		return nil, err
@@ -637,12 +977,27 @@ func (s *PostService) GetFeedPosts(userID string, page, pageSize int) ([]*models
 		pageSize = 10
 	}
 
-	offset := (page - 1) * pageSize
-	posts, err := s.repo.GetFeedPosts(userID, pageSize, offset)
+	// The newest posts from the user's favorites go first, and are left out
+	// of the rest of the feed so they aren't shown twice
+	boosted := s.getBoostedPosts(userID)
+	excludeIDs := make([]int64, 0, len(boosted))
+	for _, post := range boosted {
+		excludeIDs = append(excludeIDs, post.ID)
+	}
+
+	// Work out which of the boosted posts fall on this page, and where the
+	// rest of the feed picks up after them
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	onPage := boosted[min(start, len(boosted)):min(end, len(boosted))]
+	offset := max(start-len(boosted), 0)
+
+	posts, err := s.repo.GetFeedPosts(userID, excludeIDs, pageSize-len(onPage), offset)
 	if err != nil {
 		s.log.Error("Failed to get feed posts: %v", err)
 		return nil, err
 	}
+	posts = append(append([]*models.Post{}, onPage...), posts...)
 
 	// Fetch user data for each post
 	for _, post := range posts {
@@ -656,9 +1011,27 @@ func (s *PostService) GetFeedPosts(userID string, page, pageSize int) ([]*models
 		}
 	}
 
+	s.impressions.RecordImpressions(userID, posts)
+
 	return posts, nil
 }
 
+// getBoostedPosts gets the newest posts from the user's favorites, which are
+// pinned to the top of the first feed page
+func (s *PostService) getBoostedPosts(userID string) []*models.Post {
+	boosted, err := s.repo.GetFavoriteFeedPosts(userID, time.Now().Add(-favoriteBoostWindow), maxBoostedPosts)
+	if err != nil {
+		s.log.Warn("Failed to get favorite posts for feed: %v", err)
+		return nil
+	}
+
+	for _, post := range boosted {
+		post.Boosted = true
+	}
+
+	return boosted
+}
+
 // GetPostWithComments retrieves a post along with its comments
 func (s *PostService) GetPostWithComments(postID int64, userID string) (*models.Post, []*models.Comment, error) {
 	post, err := s.repo.GetPostByID(postID)
@@ -666,7 +1039,7 @@ func (s *PostService) GetPostWithComments(postID int64, userID string) (*models.
 		return nil, nil, err
 	}
 
-	comments, err := s.repo.GetCommentsByPostID(postID)
+	comments, err := s.repo.GetCommentsByPostID(postID, userID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,76 @@
+package privacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/pkg/httputil"
+	"github.com/Athooh/social-network/pkg/logger"
+)
+
+// Handler handles HTTP requests for privacy settings
+type Handler struct {
+	service Service
+	log     *logger.Logger
+}
+
+// NewHandler creates a new privacy handler
+func NewHandler(service Service, log *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		log:     log,
+	}
+}
+
+// HandleSettings handles getting and updating the current user's privacy
+// settings
+func (h *Handler) HandleSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		settings, err := h.service.GetSettings(userID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, "Failed to get privacy settings")
+			return
+		}
+		h.sendJSON(w, http.StatusOK, settings)
+
+	case http.MethodPut:
+		var update SettingsUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		settings, err := h.service.UpdateSettings(userID, &update)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, settings)
+
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// Helper method to send JSON responses
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	httputil.SendJSON(w, status, data)
+}
+
+// Helper method to send error responses
+func (h *Handler) sendError(w http.ResponseWriter, status int, message string) {
+	var isWarning bool = false
+	if status >= 500 {
+		isWarning = true
+	}
+	httputil.SendError(w, status, message, isWarning)
+}
@@ -0,0 +1,139 @@
+package privacy
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Repository defines the interface for privacy settings data access
+type Repository interface {
+	GetSettings(userID string) (*models.PrivacySetting, error)
+	SaveSettings(settings *models.PrivacySetting) error
+
+	// Relationships the audiences are based on
+	IsFollowing(followerID, followingID string) (bool, error)
+	GetFollowerIDs(userID string) ([]string, error)
+	GetFollowingIDs(userID string) ([]string, error)
+}
+
+// SQLiteRepository implements Repository interface for SQLite
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite repository
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// GetSettings gets a user's privacy settings, or nil if they never saved any
+func (r *SQLiteRepository) GetSettings(userID string) (*models.PrivacySetting, error) {
+	query := `
+		SELECT user_id, who_can_message, who_can_mention, who_can_invite,
+			who_can_see_followers, who_can_find_me, who_can_see_online, updated_at
+		FROM privacy_settings
+		WHERE user_id = ?
+	`
+
+	settings := &models.PrivacySetting{}
+	err := r.db.QueryRow(query, userID).Scan(
+		&settings.UserID,
+		&settings.WhoCanMessage,
+		&settings.WhoCanMention,
+		&settings.WhoCanInvite,
+		&settings.WhoCanSeeFollowers,
+		&settings.WhoCanFindMe,
+		&settings.WhoCanSeeOnline,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveSettings creates or replaces a user's privacy settings
+func (r *SQLiteRepository) SaveSettings(settings *models.PrivacySetting) error {
+	settings.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO privacy_settings (user_id, who_can_message, who_can_mention, who_can_invite,
+			who_can_see_followers, who_can_find_me, who_can_see_online, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			who_can_message = excluded.who_can_message,
+			who_can_mention = excluded.who_can_mention,
+			who_can_invite = excluded.who_can_invite,
+			who_can_see_followers = excluded.who_can_see_followers,
+			who_can_find_me = excluded.who_can_find_me,
+			who_can_see_online = excluded.who_can_see_online,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		settings.UserID,
+		settings.WhoCanMessage,
+		settings.WhoCanMention,
+		settings.WhoCanInvite,
+		settings.WhoCanSeeFollowers,
+		settings.WhoCanFindMe,
+		settings.WhoCanSeeOnline,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save privacy settings: %w", err)
+	}
+
+	return nil
+}
+
+// IsFollowing checks if a user follows another user
+func (r *SQLiteRepository) IsFollowing(followerID, followingID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM followers WHERE follower_id = ? AND following_id = ?)",
+		followerID, followingID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check follow: %w", err)
+	}
+	return exists, nil
+}
+
+// GetFollowerIDs gets the IDs of the users following a user
+func (r *SQLiteRepository) GetFollowerIDs(userID string) ([]string, error) {
+	return r.queryIDs("SELECT follower_id FROM followers WHERE following_id = ?", userID)
+}
+
+// GetFollowingIDs gets the IDs of the users a user follows
+func (r *SQLiteRepository) GetFollowingIDs(userID string) ([]string, error) {
+	return r.queryIDs("SELECT following_id FROM followers WHERE follower_id = ?", userID)
+}
+
+// queryIDs runs a query returning a single column of user IDs
+func (r *SQLiteRepository) queryIDs(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,226 @@
+package privacy
+
+import (
+	"errors"
+
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Setting names one of the privacy settings
+type Setting string
+
+// Privacy settings
+const (
+	SettingMessage      Setting = "message"
+	SettingMention      Setting = "mention"
+	SettingInvite       Setting = "invite"
+	SettingSeeFollowers Setting = "see_followers"
+	SettingFindMe       Setting = "find_me"
+	SettingSeeOnline    Setting = "see_online"
+)
+
+// SettingsUpdate changes some of a user's privacy settings; nil fields are
+// left as they are
+type SettingsUpdate struct {
+	WhoCanMessage      *string `json:"whoCanMessage"`
+	WhoCanMention      *string `json:"whoCanMention"`
+	WhoCanInvite       *string `json:"whoCanInvite"`
+	WhoCanSeeFollowers *string `json:"whoCanSeeFollowers"`
+	WhoCanFindMe       *string `json:"whoCanFindMe"`
+	WhoCanSeeOnline    *string `json:"whoCanSeeOnline"`
+}
+
+// validAudiences lists the values every setting accepts
+var validAudiences = map[string]bool{
+	models.AudienceEveryone:  true,
+	models.AudienceFollowers: true,
+	models.AudienceMutuals:   true,
+	models.AudienceNobody:    true,
+}
+
+// Service defines the privacy service interface
+type Service interface {
+	GetSettings(userID string) (*models.PrivacySetting, error)
+	UpdateSettings(userID string, update *SettingsUpdate) (*models.PrivacySetting, error)
+
+	// Checks used by other services
+	Allows(ownerID, viewerID string, setting Setting) (bool, error)
+	FilterAllowed(ownerID string, viewerIDs []string, setting Setting) ([]string, error)
+}
+
+// PrivacyService implements the Service interface
+type PrivacyService struct {
+	repo Repository
+	log  *logger.Logger
+}
+
+// NewService creates a new privacy service
+func NewService(repo Repository, log *logger.Logger) Service {
+	return &PrivacyService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// defaultSettings are used for users who never saved any settings
+func defaultSettings(userID string) *models.PrivacySetting {
+	return &models.PrivacySetting{
+		UserID:             userID,
+		WhoCanMessage:      models.AudienceEveryone,
+		WhoCanMention:      models.AudienceEveryone,
+		WhoCanInvite:       models.AudienceEveryone,
+		WhoCanSeeFollowers: models.AudienceEveryone,
+		WhoCanFindMe:       models.AudienceEveryone,
+		WhoCanSeeOnline:    models.AudienceEveryone,
+	}
+}
+
+// GetSettings gets a user's privacy settings, falling back to the defaults
+func (s *PrivacyService) GetSettings(userID string) (*models.PrivacySetting, error) {
+	settings, err := s.repo.GetSettings(userID)
+	if err != nil {
+		s.log.Error("Failed to get privacy settings: %v", err)
+		return nil, err
+	}
+	if settings == nil {
+		settings = defaultSettings(userID)
+	}
+	return settings, nil
+}
+
+// UpdateSettings changes the given privacy settings
+func (s *PrivacyService) UpdateSettings(userID string, update *SettingsUpdate) (*models.PrivacySetting, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []struct {
+		value  *string
+		target *string
+	}{
+		{update.WhoCanMessage, &settings.WhoCanMessage},
+		{update.WhoCanMention, &settings.WhoCanMention},
+		{update.WhoCanInvite, &settings.WhoCanInvite},
+		{update.WhoCanSeeFollowers, &settings.WhoCanSeeFollowers},
+		{update.WhoCanFindMe, &settings.WhoCanFindMe},
+		{update.WhoCanSeeOnline, &settings.WhoCanSeeOnline},
+	}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		if !validAudiences[*field.value] {
+			return nil, errors.New("privacy settings must be everyone, followers, mutuals or nobody")
+		}
+		*field.target = *field.value
+	}
+
+	if err := s.repo.SaveSettings(settings); err != nil {
+		s.log.Error("Failed to save privacy settings: %v", err)
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// audienceFor gets the audience the owner chose for a setting
+func (s *PrivacyService) audienceFor(ownerID string, setting Setting) (string, error) {
+	settings, err := s.GetSettings(ownerID)
+	if err != nil {
+		return "", err
+	}
+
+	switch setting {
+	case SettingMessage:
+		return settings.WhoCanMessage, nil
+	case SettingMention:
+		return settings.WhoCanMention, nil
+	case SettingInvite:
+		return settings.WhoCanInvite, nil
+	case SettingSeeFollowers:
+		return settings.WhoCanSeeFollowers, nil
+	case SettingFindMe:
+		return settings.WhoCanFindMe, nil
+	case SettingSeeOnline:
+		return settings.WhoCanSeeOnline, nil
+	default:
+		return "", errors.New("unknown privacy setting")
+	}
+}
+
+// Allows checks whether the owner's setting lets the viewer interact with
+// them. Users are always allowed to interact with themselves.
+func (s *PrivacyService) Allows(ownerID, viewerID string, setting Setting) (bool, error) {
+	if ownerID == viewerID {
+		return true, nil
+	}
+
+	audience, err := s.audienceFor(ownerID, setting)
+	if err != nil {
+		return false, err
+	}
+
+	switch audience {
+	case models.AudienceEveryone:
+		return true, nil
+	case models.AudienceFollowers:
+		return s.repo.IsFollowing(viewerID, ownerID)
+	case models.AudienceMutuals:
+		follows, err := s.repo.IsFollowing(viewerID, ownerID)
+		if err != nil || !follows {
+			return false, err
+		}
+		return s.repo.IsFollowing(ownerID, viewerID)
+	default:
+		return false, nil
+	}
+}
+
+// FilterAllowed keeps the viewers the owner's setting allows
+func (s *PrivacyService) FilterAllowed(ownerID string, viewerIDs []string, setting Setting) ([]string, error) {
+	audience, err := s.audienceFor(ownerID, setting)
+	if err != nil {
+		return nil, err
+	}
+
+	if audience == models.AudienceEveryone {
+		return viewerIDs, nil
+	}
+
+	allowed := make(map[string]bool)
+	if audience == models.AudienceFollowers || audience == models.AudienceMutuals {
+		followerIDs, err := s.repo.GetFollowerIDs(ownerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range followerIDs {
+			allowed[id] = true
+		}
+	}
+
+	if audience == models.AudienceMutuals {
+		followingIDs, err := s.repo.GetFollowingIDs(ownerID)
+		if err != nil {
+			return nil, err
+		}
+		following := make(map[string]bool, len(followingIDs))
+		for _, id := range followingIDs {
+			following[id] = true
+		}
+		for id := range allowed {
+			if !following[id] {
+				delete(allowed, id)
+			}
+		}
+	}
+
+	filtered := make([]string, 0, len(allowed))
+	for _, viewerID := range viewerIDs {
+		if allowed[viewerID] || viewerID == ownerID {
+			filtered = append(filtered, viewerID)
+		}
+	}
+	return filtered, nil
+}
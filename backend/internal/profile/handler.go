@@ -1,8 +1,12 @@
 package profile
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"mime/multipart"
 	"net/http"
+	"strings"
 
 	"github.com/Athooh/social-network/internal/auth"
 	httputil "github.com/Athooh/social-network/pkg/httputil"
@@ -16,23 +20,6 @@ type Handler struct {
 	uploadDir string
 }
 
-// ProfileUpdateRequest represents the request to update a profile
-type ProfileUpdateRequest struct {
-	Username   string `json:"username"`
-	FullName   string `json:"fullName"`
-	Bio        string `json:"bio"`
-	Work       string `json:"work"`
-	Education  string `json:"education"`
-	Email      string `json:"email"`
-	Phone      string `json:"phone"`
-	Website    string `json:"website"`
-	Location   string `json:"location"`
-	TechSkills string `json:"techSkills"`
-	SoftSkills string `json:"softSkills"`
-	Interests  string `json:"interests"`
-	IsPrivate  bool   `json:"isPrivate"`
-}
-
 // NewHandler creates a new chat handler
 func NewHandler(service Service, log *logger.Logger) *Handler {
 	return &Handler{
@@ -68,12 +55,13 @@ func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 		httputil.SendError(w, http.StatusForbidden, "Forbidden", true)
 		return
 	}
-	targetProfile, err := h.service.GetProfileByUserID(profileID)
+	targetProfile, err := h.service.GetProfileForViewer(userID, profileID)
 	if err != nil {
 		h.log.Error("Failed to fetch target profile" + err.Error())
 		httputil.SendError(w, http.StatusInternalServerError, "Server error", true)
 		return
 	}
+	h.service.RecordProfileVisit(userID, profileID)
 	// Return success response with updated profile data
 	httputil.SendJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
@@ -82,10 +70,13 @@ func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateProfile handles partial profile updates. The body is either JSON or,
+// to upload images along with the update, a multipart form. Only the fields
+// present in the request change.
 func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	h.log.Info("Received profile update request")
 
-	if r.Method != http.MethodPut {
+	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
 		httputil.SendError(w, http.StatusMethodNotAllowed, "Method not allowed", false)
 		return
 	}
@@ -95,91 +86,59 @@ func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		httputil.SendError(w, http.StatusUnauthorized, "Unauthorized", true)
 		return
 	}
-	err := r.ParseMultipartForm(20 << 20) // 20MB max
-	if err != nil {
-		h.log.Error("Failed to parse multipart form" + err.Error())
-		httputil.SendError(w, http.StatusBadRequest, "Failed to parse form data", false)
-		return
-	}
 
-	// Extract and validate text fields
-	profileUpdate := ProfileUpdateRequest{
-		Username:   r.FormValue("username"),
-		FullName:   r.FormValue("fullName"),
-		Bio:        r.FormValue("bio"),
-		Work:       r.FormValue("work"),
-		Education:  r.FormValue("education"),
-		Email:      r.FormValue("email"),
-		Phone:      r.FormValue("phone"),
-		Website:    r.FormValue("website"),
-		Location:   r.FormValue("location"),
-		TechSkills: r.FormValue("techSkills"),
-		SoftSkills: r.FormValue("softSkills"),
-		Interests:  r.FormValue("interests"),
-		IsPrivate:  r.FormValue("isPrivate") == "true",
-	}
-
-	var profileImageHeader *multipart.FileHeader
-	var profileImagePath string
-	if file, header, err := r.FormFile("profileImage"); err == nil {
-		defer file.Close()
-		profileImageHeader = header
-	}
-
-	var bannerImageHeader *multipart.FileHeader
-	var bannerImagePath string
-	if file, header, err := r.FormFile("bannerImage"); err == nil {
-		defer file.Close()
-		bannerImageHeader = header
-	}
-
-	// Convert the profileUpdate struct to a map for the service
-	profileData := map[string]interface{}{
-		"username":   profileUpdate.Username,
-		"fullName":   profileUpdate.FullName,
-		"bio":        profileUpdate.Bio,
-		"work":       profileUpdate.Work,
-		"education":  profileUpdate.Education,
-		"email":      profileUpdate.Email,
-		"phone":      profileUpdate.Phone,
-		"website":    profileUpdate.Website,
-		"location":   profileUpdate.Location,
-		"techSkills": profileUpdate.TechSkills,
-		"softSkills": profileUpdate.SoftSkills,
-		"interests":  profileUpdate.Interests,
-		"isPrivate":  profileUpdate.IsPrivate,
-	}
-
-	// Handle profile image if provided
-	if profileImageHeader != nil {
-		profileImagePath, err = h.service.SaveProfileImage(userID, profileImageHeader)
-		if err != nil {
-			h.log.Error("Failed to save profile image: " + err.Error())
-			httputil.SendError(w, http.StatusInternalServerError, "Failed to save profile image", false)
+	update := &ProfileUpdate{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(20 << 20); err != nil { // 20MB max
+			h.log.Error("Failed to parse multipart form" + err.Error())
+			httputil.SendError(w, http.StatusBadRequest, "Failed to parse form data", false)
 			return
 		}
 
-		if profileImagePath != "" {
-			profileData["profileImage"] = profileImagePath
-		}
-	}
-
-	// Handle banner image if provided
-	if bannerImageHeader != nil {
-		bannerImagePath, err = h.service.SaveBannerImage(userID, bannerImageHeader)
+		var err error
+		update, err = profileUpdateFromForm(r.MultipartForm)
 		if err != nil {
-			h.log.Error("Failed to save banner image: " + err.Error())
-			httputil.SendError(w, http.StatusInternalServerError, "Failed to save banner image", false)
+			httputil.SendError(w, http.StatusBadRequest, err.Error(), false)
 			return
 		}
 
-		if bannerImagePath != "" {
-			profileData["bannerImage"] = bannerImagePath
+		// Handle profile image if provided
+		if file, header, err := r.FormFile("profileImage"); err == nil {
+			file.Close()
+			profileImagePath, err := h.service.SaveProfileImage(userID, header)
+			if err != nil {
+				h.log.Error("Failed to save profile image: " + err.Error())
+				httputil.SendError(w, http.StatusInternalServerError, "Failed to save profile image", false)
+				return
+			}
+			update.ProfileImage = &profileImagePath
 		}
+
+		// Handle banner image if provided
+		if file, header, err := r.FormFile("bannerImage"); err == nil {
+			file.Close()
+			bannerImagePath, err := h.service.SaveBannerImage(userID, header)
+			if err != nil {
+				h.log.Error("Failed to save banner image: " + err.Error())
+				httputil.SendError(w, http.StatusInternalServerError, "Failed to save banner image", false)
+				return
+			}
+			update.BannerImage = &bannerImagePath
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(update); err != nil {
+		httputil.SendError(w, http.StatusBadRequest, "Invalid request body", false)
+		return
 	}
 
-	err = h.service.UpdateProfile(userID, profileData)
-	if err != nil {
+	if err := h.service.UpdateProfile(userID, update); err != nil {
+		var validationErr ValidationError
+		if errors.As(err, &validationErr) {
+			httputil.SendJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":  "Some profile fields are invalid",
+				"fields": validationErr,
+			})
+			return
+		}
 		h.log.Error("Failed to update profile: " + err.Error())
 		httputil.SendError(w, http.StatusInternalServerError, "Failed to update profile", false)
 		return
@@ -204,3 +163,68 @@ func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		"profile": updatedProfile,
 	})
 }
+
+// profileUpdateFromForm reads a profile update from a multipart form. Skill
+// lists are JSON arrays, or comma-separated strings as older clients send
+// them, and visibility is a JSON object.
+func profileUpdateFromForm(form *multipart.Form) (*ProfileUpdate, error) {
+	text := func(key string) *string {
+		if values, ok := form.Value[key]; ok && len(values) > 0 {
+			value := values[0]
+			return &value
+		}
+		return nil
+	}
+
+	list := func(key string) (*[]string, error) {
+		value := text(key)
+		if value == nil {
+			return nil, nil
+		}
+		if !strings.HasPrefix(strings.TrimSpace(*value), "[") {
+			skills := splitSkills(*value)
+			return &skills, nil
+		}
+		var skills []string
+		if err := json.Unmarshal([]byte(*value), &skills); err != nil {
+			return nil, fmt.Errorf("invalid %s list", key)
+		}
+		return &skills, nil
+	}
+
+	update := &ProfileUpdate{
+		Username:  text("username"),
+		FullName:  text("fullName"),
+		Bio:       text("bio"),
+		Work:      text("work"),
+		Education: text("education"),
+		Email:     text("email"),
+		Phone:     text("phone"),
+		Website:   text("website"),
+		Location:  text("location"),
+	}
+
+	var err error
+	if update.TechSkills, err = list("techSkills"); err != nil {
+		return nil, err
+	}
+	if update.SoftSkills, err = list("softSkills"); err != nil {
+		return nil, err
+	}
+	if update.Interests, err = list("interests"); err != nil {
+		return nil, err
+	}
+
+	if value := text("isPrivate"); value != nil {
+		isPrivate := *value == "true"
+		update.IsPrivate = &isPrivate
+	}
+
+	if value := text("visibility"); value != nil && *value != "" {
+		if err := json.Unmarshal([]byte(*value), &update.Visibility); err != nil {
+			return nil, errors.New("invalid visibility")
+		}
+	}
+
+	return update, nil
+}
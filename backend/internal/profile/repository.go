@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
 )
 
 // SQLiteRepository implements Repository interface for SQLite
@@ -15,8 +17,11 @@ type SQLiteRepository struct {
 
 // Repository interface for profile data persistence
 type Repository interface {
-	UpdateUserProfile(userID string, profileData map[string]interface{}) error
+	UpdateProfile(userID string, update *ProfileUpdate) error
 	GetUserProfileByID(userID string) (*UserProfileData, error)
+	GetSkills(userID string) (map[string][]string, error)
+	GetFieldAudiences(userID string) (map[string]string, error)
+	MigrateLegacySkills() (int, error)
 	IsUserProfilePublic(userID string) (bool, error)
 	IsUserFollowing(followerID string, followingID string) (bool, error)
 }
@@ -51,107 +56,220 @@ func (r *SQLiteRepository) IsUserFollowing(followerID string, followingID string
 	return count > 0, nil
 }
 
-func (r *SQLiteRepository) UpdateUserProfile(userID string, profileData map[string]interface{}) error {
+// UpdateProfile applies a partial profile update in one transaction. Fields
+// kept in both tables, like the bio and avatar, are written to both.
+func (r *SQLiteRepository) UpdateProfile(userID string, update *ProfileUpdate) error {
 	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 	now := time.Now()
 
-	if len(profileData) > 0 {
-		// Build the user table update query dynamically based on what data was provided
-		userFields := []string{"updated_at = ?"}
-		userValues := []interface{}{now}
+	userFields := []string{"updated_at = ?"}
+	userValues := []interface{}{now}
+	profileFields := []string{"updated_at = ?"}
+	profileValues := []interface{}{now}
 
-		// Fields from User that can be updated from profile edit
-		if nickname, ok := profileData["username"].(string); ok && nickname != "" {
-			userFields = append(userFields, "nickname = ?")
-			userValues = append(userValues, nickname)
+	setUser := func(column string, value interface{}) {
+		userFields = append(userFields, column+" = ?")
+		userValues = append(userValues, value)
+	}
+	setProfile := func(column string, value *string) {
+		if value != nil {
+			profileFields = append(profileFields, column+" = ?")
+			profileValues = append(profileValues, *value)
 		}
+	}
+
+	// The users table keeps its own copy of these
+	if update.Username != nil {
+		setUser("nickname", *update.Username)
+	}
+	if update.Bio != nil {
+		setUser("about_me", *update.Bio)
+	}
+	if update.ProfileImage != nil {
+		setUser("avatar", *update.ProfileImage)
+	}
+	if update.IsPrivate != nil {
+		setUser("is_public", !*update.IsPrivate)
+		profileFields = append(profileFields, "is_private = ?")
+		profileValues = append(profileValues, *update.IsPrivate)
+	}
 
-		if aboutMe, ok := profileData["bio"].(string); ok && aboutMe != "" {
-			userFields = append(userFields, "about_me = ?")
-			userValues = append(userValues, aboutMe)
+	setProfile("username", update.Username)
+	setProfile("full_name", update.FullName)
+	setProfile("bio", update.Bio)
+	setProfile("work", update.Work)
+	setProfile("education", update.Education)
+	setProfile("email", update.Email)
+	setProfile("phone", update.Phone)
+	setProfile("website", update.Website)
+	setProfile("location", update.Location)
+	setProfile("profile_image", update.ProfileImage)
+	setProfile("banner_image", update.BannerImage)
+
+	userQuery := fmt.Sprintf("UPDATE users SET %s WHERE id = ?", strings.Join(userFields, ", "))
+	if _, err := tx.Exec(userQuery, append(userValues, userID)...); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	profileQuery := fmt.Sprintf("UPDATE user_profiles SET %s WHERE user_id = ?", strings.Join(profileFields, ", "))
+	if _, err := tx.Exec(profileQuery, append(profileValues, userID)...); err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	skillLists := map[string]*[]string{
+		models.SkillKindTech:     update.TechSkills,
+		models.SkillKindSoft:     update.SoftSkills,
+		models.SkillKindInterest: update.Interests,
+	}
+	for kind, skills := range skillLists {
+		if skills == nil {
+			continue
+		}
+		if err := replaceSkills(tx, userID, kind, *skills); err != nil {
+			return err
 		}
+	}
 
-		if avatar, ok := profileData["profileImage"].(string); ok && avatar != "" {
-			userFields = append(userFields, "avatar = ?")
-			userValues = append(userValues, avatar)
+	for field, audience := range update.Visibility {
+		_, err := tx.Exec(`
+			INSERT INTO profile_field_audiences (id, user_id, field, audience, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET audience = excluded.audience, updated_at = excluded.updated_at
+		`, userID+":"+field, userID, field, audience, now)
+		if err != nil {
+			return fmt.Errorf("failed to save field visibility: %w", err)
 		}
+	}
+
+	return tx.Commit()
+}
 
-		if isPrivate, ok := profileData["isPrivate"].(bool); ok {
-			userFields = append(userFields, "is_public = ?")
-			userValues = append(userValues, !isPrivate) // Note: isPrivate is the inverse of isPublic
+// replaceSkills replaces one of the user's skill lists within a transaction
+func replaceSkills(tx *sql.Tx, userID, kind string, skills []string) error {
+	if _, err := tx.Exec("DELETE FROM profile_skills WHERE user_id = ? AND kind = ?", userID, kind); err != nil {
+		return fmt.Errorf("failed to clear %s skills: %w", kind, err)
+	}
+
+	now := time.Now()
+	for i, skill := range skills {
+		_, err := tx.Exec(
+			"INSERT OR IGNORE INTO profile_skills (id, user_id, kind, name, position, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+			userID+":"+kind+":"+strings.ToLower(skill), userID, kind, skill, i, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save %s skill: %w", kind, err)
 		}
+	}
 
-		// Add the WHERE clause values
-		userValues = append(userValues, userID)
+	return nil
+}
 
-		if len(userFields) > 1 {
-			userQuery := fmt.Sprintf("UPDATE users SET %s WHERE id = ?", strings.Join(userFields, ", "))
-			_, err = tx.Exec(userQuery, userValues...)
-			if err != nil {
-				return err
-			}
+// GetSkills gets the user's skill lists, by kind, in the order they were given
+func (r *SQLiteRepository) GetSkills(userID string) (map[string][]string, error) {
+	rows, err := r.db.Query(
+		"SELECT kind, name FROM profile_skills WHERE user_id = ? ORDER BY kind, position",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills: %w", err)
+	}
+	defer rows.Close()
+
+	skills := make(map[string][]string)
+	for rows.Next() {
+		var kind, name string
+		if err := rows.Scan(&kind, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan skill: %w", err)
 		}
+		skills[kind] = append(skills[kind], name)
 	}
 
-	// Update the user_profiles table
-	profileFields := []string{"updated_at = ?"}
-	profileValues := []interface{}{now}
-	// Map each field from the form to the database field
-	fieldMappings := map[string]string{
-		"username":     "username",
-		"fullName":     "full_name",
-		"bio":          "bio",
-		"work":         "work",
-		"education":    "education",
-		"email":        "email", // This is contact email, not authentication email
-		"phone":        "phone",
-		"website":      "website",
-		"location":     "location",
-		"techSkills":   "tech_skills",
-		"softSkills":   "soft_skills",
-		"interests":    "interests",
-		"bannerImage":  "banner_image",
-		"profileImage": "profile_image",
-		"isPrivate":    "is_private",
-	}
-
-	// Add each field that exists in the profileData
-	for formField, dbField := range fieldMappings {
-		if value, exists := profileData[formField]; exists {
-			// Special handling for boolean values
-			if dbField == "is_private" {
-				if boolVal, ok := value.(bool); ok {
-					profileFields = append(profileFields, dbField+" = ?")
-					profileValues = append(profileValues, boolVal)
-				}
+	return skills, rows.Err()
+}
+
+// GetFieldAudiences gets the audiences the user chose for their profile
+// fields, by field. Fields missing from the map are visible to everyone.
+func (r *SQLiteRepository) GetFieldAudiences(userID string) (map[string]string, error) {
+	rows, err := r.db.Query("SELECT field, audience FROM profile_field_audiences WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get field visibility: %w", err)
+	}
+	defer rows.Close()
+
+	fieldAudiences := make(map[string]string)
+	for rows.Next() {
+		var field, audience string
+		if err := rows.Scan(&field, &audience); err != nil {
+			return nil, fmt.Errorf("failed to scan field visibility: %w", err)
+		}
+		fieldAudiences[field] = audience
+	}
+
+	return fieldAudiences, rows.Err()
+}
+
+// MigrateLegacySkills moves the comma-separated skill columns of
+// user_profiles into profile_skills and returns how many profiles it moved
+func (r *SQLiteRepository) MigrateLegacySkills() (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT user_id, COALESCE(tech_skills, ''), COALESCE(soft_skills, ''), COALESCE(interests, '')
+		FROM user_profiles
+		WHERE COALESCE(tech_skills, '') != '' OR COALESCE(soft_skills, '') != '' OR COALESCE(interests, '') != ''
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get legacy skills: %w", err)
+	}
+
+	legacy := make(map[string]map[string]string)
+	for rows.Next() {
+		var userID, techSkills, softSkills, interests string
+		if err := rows.Scan(&userID, &techSkills, &softSkills, &interests); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan legacy skills: %w", err)
+		}
+		legacy[userID] = map[string]string{
+			models.SkillKindTech:     techSkills,
+			models.SkillKindSoft:     softSkills,
+			models.SkillKindInterest: interests,
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to get legacy skills: %w", err)
+	}
+
+	for userID, lists := range legacy {
+		for kind, list := range lists {
+			if list == "" {
 				continue
 			}
-
-			// Only update string fields when they're non-empty
-			if strVal, ok := value.(string); ok && strVal != "" {
-				profileFields = append(profileFields, dbField+" = ?")
-				profileValues = append(profileValues, strVal)
+			if err := replaceSkills(tx, userID, kind, splitSkills(list)); err != nil {
+				return 0, err
 			}
 		}
 	}
 
-	// Add WHERE clause value
-	profileValues = append(profileValues, userID)
-
-	// Execute the profile update if there are fields to update
-	if len(profileFields) > 1 { // More than just updated_at
-		profileQuery := fmt.Sprintf("UPDATE user_profiles SET %s WHERE user_id = ?", strings.Join(profileFields, ", "))
-		_, err = tx.Exec(profileQuery, profileValues...)
-		if err != nil {
-			return err
+	if len(legacy) > 0 {
+		if _, err := tx.Exec("UPDATE user_profiles SET tech_skills = '', soft_skills = '', interests = ''"); err != nil {
+			return 0, fmt.Errorf("failed to clear legacy skills: %w", err)
 		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit legacy skills: %w", err)
+	}
+
+	return len(legacy), nil
 }
 
 // SQLiteRepository implementation of GetUserProfileByID
@@ -174,22 +292,24 @@ func (r *SQLiteRepository) GetUserProfileByID(userID string) (*UserProfileData,
 		COALESCE(p.phone, '') as phone, 
 		COALESCE(p.website, '') as website, 
 		COALESCE(p.location, '') as location, 
-		COALESCE(p.tech_skills, '') as tech_skills, 
-		COALESCE(p.soft_skills, '') as soft_skills, 
-		COALESCE(p.interests, '') as interests, 
 		COALESCE(p.banner_image, '') as banner_image, 
 		COALESCE(p.profile_image, '') as profile_image, 
 		COALESCE(p.is_private, 0) as is_private, 
 		COALESCE(p.created_at, u.created_at) as profile_created_at, 
 		COALESCE(p.updated_at, u.updated_at) as profile_updated_at,
 		COALESCE(us.followers_count, 0) AS followers_count,
-		COALESCE(us.following_count, 0) AS following_count
+		COALESCE(us.following_count, 0) AS following_count,
+		COALESCE(cs.status_text, '') AS status_text,
+		COALESCE(cs.status_emoji, '') AS status_emoji,
+		COALESCE(h.handle, '') AS handle
 	FROM users u
 	LEFT JOIN user_stats us ON u.id = us.user_id
 	LEFT JOIN user_profiles p ON u.id = p.user_id
+	LEFT JOIN user_status cs ON u.id = cs.user_id AND (cs.status_expires_at IS NULL OR cs.status_expires_at > ?)
+	LEFT JOIN user_handles h ON u.id = h.user_id AND h.is_current = TRUE
 	WHERE u.id = ?`
 
-	row := r.db.QueryRow(query, userID)
+	row := r.db.QueryRow(query, time.Now().UTC(), userID)
 
 	var profileData UserProfileData
 	var createdAt, updatedAt, profileCreatedAt, profileUpdatedAt string // SQLite returns dates as strings
@@ -203,9 +323,9 @@ func (r *SQLiteRepository) GetUserProfileByID(userID string) (*UserProfileData,
 		// Profile fields
 		&profileData.Username, &profileData.FullName, &profileData.Bio, &profileData.Work,
 		&profileData.Education, &profileData.ContactEmail, &profileData.Phone, &profileData.Website,
-		&profileData.Location, &profileData.TechSkills, &profileData.SoftSkills, &profileData.Interests,
-		&profileData.BannerImage, &profileData.ProfileImage, &profileData.IsPrivate,
-		&profileCreatedAt, &profileUpdatedAt, &profileData.FollowersCount, &profileData.FollowingCount, 
+		&profileData.Location, &profileData.BannerImage, &profileData.ProfileImage, &profileData.IsPrivate,
+		&profileCreatedAt, &profileUpdatedAt, &profileData.FollowersCount, &profileData.FollowingCount,
+		&profileData.StatusText, &profileData.StatusEmoji, &profileData.Handle,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -235,5 +355,21 @@ func (r *SQLiteRepository) GetUserProfileByID(userID string) (*UserProfileData,
 		profileData.ProfileUpdatedAt = parsedProfileUpdatedAt
 	}
 
+	skills, err := r.GetSkills(userID)
+	if err != nil {
+		return nil, err
+	}
+	profileData.TechSkills = orEmpty(skills[models.SkillKindTech])
+	profileData.SoftSkills = orEmpty(skills[models.SkillKindSoft])
+	profileData.Interests = orEmpty(skills[models.SkillKindInterest])
+
 	return &profileData, nil
 }
+
+// orEmpty turns a nil list into an empty one, so it's sent as [] rather than null
+func orEmpty(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
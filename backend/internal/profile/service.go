@@ -8,22 +8,29 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/Athooh/social-network/internal/analytics"
+	"github.com/Athooh/social-network/internal/follow"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
 )
 
 // Service interface defines the operations for profile management
 type Service interface {
-	UpdateProfile(userID string, profileData map[string]interface{}) error
+	UpdateProfile(userID string, update *ProfileUpdate) error
 	SaveProfileImage(userID string, fileHeader *multipart.FileHeader) (string, error)
 	SaveBannerImage(userID string, fileHeader *multipart.FileHeader) (string, error)
 	GetProfileByUserID(userID string) (*UserProfileData, error)
+	GetProfileForViewer(viewerID string, userID string) (*UserProfileData, error)
+	MigrateLegacySkills() (int, error)
 	ValidateProfileViewRequest(userID string, targetID string) (bool, error)
+	RecordProfileVisit(visitorID string, profileID string)
 }
 
 // UserProfileData represents the combined user and profile data
 type UserProfileData struct {
 	// User data
 	ID               string    `json:"id"`
-	Email            string    `json:"email,omitempty"` // Authentication email (omitted from JSON)
+	Email            string    `json:"email,omitempty"` // Authentication email, only sent to the owner
 	FirstName        string    `json:"firstName"`
 	LastName         string    `json:"lastName"`
 	Nickname         string    `json:"nickname"`
@@ -41,9 +48,9 @@ type UserProfileData struct {
 	Phone            string    `json:"phone"`
 	Website          string    `json:"website"`
 	Location         string    `json:"location"`
-	TechSkills       string    `json:"techSkills"`
-	SoftSkills       string    `json:"softSkills"`
-	Interests        string    `json:"interests"`
+	TechSkills       []string  `json:"techSkills"`
+	SoftSkills       []string  `json:"softSkills"`
+	Interests        []string  `json:"interests"`
 	BannerImage      string    `json:"bannerImage"`
 	ProfileImage     string    `json:"profileImage"`
 	IsPrivate        bool      `json:"isPrivate"`
@@ -51,30 +58,66 @@ type UserProfileData struct {
 	ProfileUpdatedAt time.Time `json:"profileUpdatedAt"`
 	FollowersCount   int       `json:"followersCount"`
 	FollowingCount   int       `json:"followingCount"`
+	StatusText       string    `json:"statusText"`
+	StatusEmoji      string    `json:"statusEmoji"`
+	Handle           string    `json:"handle"`
+
+	// Audience of each restricted field, only sent to the owner
+	Visibility map[string]string `json:"visibility,omitempty"`
 }
 
 // ProfileService implements the Service interface
 type ProfileService struct {
 	repo      Repository
 	uploadDir string
+	visits    *analytics.Recorder
+	followSvc follow.Service
 }
 
 // NewService creates a new profile service
-func NewService(repo Repository, uploadDir string) Service {
+func NewService(repo Repository, uploadDir string, visits *analytics.Recorder, followSvc follow.Service) Service {
 	// Ensure upload directory exists
 	os.MkdirAll(uploadDir, os.ModePerm)
 	return &ProfileService{
 		repo:      repo,
 		uploadDir: uploadDir,
+		visits:    visits,
+		followSvc: followSvc,
 	}
 }
 
-// UpdateProfile updates a user's profile
-func (s *ProfileService) UpdateProfile(userID string, profileData map[string]interface{}) error {
+// UpdateProfile validates and applies a partial update of a user's profile.
+// Invalid updates return a ValidationError.
+func (s *ProfileService) UpdateProfile(userID string, update *ProfileUpdate) error {
 	if userID == "" {
 		return errors.New("user ID is required")
 	}
-	return s.repo.UpdateUserProfile(userID, profileData)
+
+	update.Normalize()
+	if err := update.Validate(); err != nil {
+		return err
+	}
+	if update.IsEmpty() {
+		return nil
+	}
+
+	wasPublic, err := s.repo.IsUserProfilePublic(userID)
+	if err != nil {
+		return fmt.Errorf("failed to check profile visibility: %w", err)
+	}
+
+	if err := s.repo.UpdateProfile(userID, update); err != nil {
+		return err
+	}
+
+	// Going public lets everyone follow, so accept the requests still waiting
+	if update.IsPrivate != nil && !*update.IsPrivate && !wasPublic && s.followSvc != nil {
+		if _, err := s.followSvc.AcceptAllFollowRequests(userID); err != nil {
+			return fmt.Errorf("failed to accept pending follow requests: %w", err)
+		}
+	}
+
+	return nil
 }
 
 // SaveProfileImage saves a profile image and returns the path
@@ -93,12 +136,102 @@ func (s *ProfileService) SaveBannerImage(userID string, fileHeader *multipart.Fi
 	return s.saveImage(userID, fileHeader, "banners")
 }
 
-// GetProfileByUserID retrieves a user's complete profile data
+// GetProfileByUserID retrieves a user's complete profile data, as the user
+// themselves sees it
 func (s *ProfileService) GetProfileByUserID(userID string) (*UserProfileData, error) {
 	if userID == "" {
 		return nil, errors.New("user ID is required")
 	}
-	return s.repo.GetUserProfileByID(userID)
+
+	profile, err := s.repo.GetUserProfileByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Visibility, err = s.repo.GetFieldAudiences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// GetProfileForViewer retrieves a user's profile as the viewer sees it, without
+// the fields the user hides from them
+func (s *ProfileService) GetProfileForViewer(viewerID string, userID string) (*UserProfileData, error) {
+	if viewerID == userID {
+		return s.GetProfileByUserID(userID)
+	}
+
+	profile, err := s.repo.GetUserProfileByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Email = "" // only the owner sees the email they log in with
+
+	fieldAudiences, err := s.repo.GetFieldAudiences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The relationship only matters if a field is limited to followers or mutuals
+	var follows, followedBack bool
+	for _, audience := range fieldAudiences {
+		if audience == models.AudienceFollowers || audience == models.AudienceMutuals {
+			if follows, err = s.repo.IsUserFollowing(viewerID, userID); err != nil {
+				return nil, err
+			}
+			if follows {
+				if followedBack, err = s.repo.IsUserFollowing(userID, viewerID); err != nil {
+					return nil, err
+				}
+			}
+			break
+		}
+	}
+
+	for field, audience := range fieldAudiences {
+		visible := audience == models.AudienceEveryone ||
+			(audience == models.AudienceFollowers && follows) ||
+			(audience == models.AudienceMutuals && follows && followedBack)
+		if !visible {
+			hideField(profile, field)
+		}
+	}
+
+	return profile, nil
+}
+
+// hideField clears a profile field the viewer isn't allowed to see
+func hideField(profile *UserProfileData, field string) {
+	switch field {
+	case "bio":
+		profile.Bio = ""
+		profile.AboutMe = ""
+	case "work":
+		profile.Work = ""
+	case "education":
+		profile.Education = ""
+	case "email":
+		profile.ContactEmail = ""
+	case "phone":
+		profile.Phone = ""
+	case "website":
+		profile.Website = ""
+	case "location":
+		profile.Location = ""
+	case "techSkills":
+		profile.TechSkills = []string{}
+	case "softSkills":
+		profile.SoftSkills = []string{}
+	case "interests":
+		profile.Interests = []string{}
+	}
+}
+
+// RecordProfileVisit counts a visit to the profile for the owner's analytics
+func (s *ProfileService) RecordProfileVisit(visitorID string, profileID string) {
+	s.visits.RecordProfileVisit(visitorID, profileID)
 }
 
 // saveImage is a helper function to save images
@@ -155,3 +288,9 @@ func (s *ProfileService) ValidateProfileViewRequest(userID string, targetID stri
 	}
 	return isFollowing, nil
 }
+
+// MigrateLegacySkills moves skill lists still stored as comma-separated
+// strings into their own table and returns how many profiles it moved
+func (s *ProfileService) MigrateLegacySkills() (int, error) {
+	return s.repo.MigrateLegacySkills()
+}
@@ -0,0 +1,229 @@
+package profile
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+// Length limits for profile fields, in characters
+const (
+	maxUsernameLength  = 30
+	maxFullNameLength  = 100
+	maxBioLength       = 500
+	maxWorkLength      = 100
+	maxEducationLength = 100
+	maxEmailLength     = 254
+	maxWebsiteLength   = 200
+	maxLocationLength  = 100
+	maxSkillLength     = 40
+	maxSkillsPerList   = 30
+)
+
+var (
+	// e164Pattern matches a phone number in E.164 format, like +254712345678
+	e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+	// phoneSeparators matches the spacing people type into phone numbers
+	phoneSeparators = regexp.MustCompile(`[\s().-]+`)
+)
+
+// visibilityFields are the profile fields whose audience the owner can choose
+var visibilityFields = map[string]bool{
+	"bio": true, "work": true, "education": true, "email": true,
+	"phone": true, "website": true, "location": true,
+	"techSkills": true, "softSkills": true, "interests": true,
+}
+
+// audiences are the audiences a profile field can be shown to
+var audiences = map[string]bool{
+	models.AudienceEveryone:  true,
+	models.AudienceFollowers: true,
+	models.AudienceMutuals:   true,
+	models.AudienceNobody:    true,
+}
+
+// ProfileUpdate is a partial update of a user's profile. Nil fields are left
+// as they are; empty strings and lists clear the field.
+type ProfileUpdate struct {
+	Username   *string   `json:"username"`
+	FullName   *string   `json:"fullName"`
+	Bio        *string   `json:"bio"`
+	Work       *string   `json:"work"`
+	Education  *string   `json:"education"`
+	Email      *string   `json:"email"` // contact email, not the one used to log in
+	Phone      *string   `json:"phone"`
+	Website    *string   `json:"website"`
+	Location   *string   `json:"location"`
+	TechSkills *[]string `json:"techSkills"`
+	SoftSkills *[]string `json:"softSkills"`
+	Interests  *[]string `json:"interests"`
+	IsPrivate  *bool     `json:"isPrivate"`
+
+	// Paths of newly uploaded images, set by the handler
+	ProfileImage *string `json:"-"`
+	BannerImage  *string `json:"-"`
+
+	// Audience by field name, for the fields in visibilityFields
+	Visibility map[string]string `json:"visibility"`
+}
+
+// ValidationError holds the reason each invalid field was rejected, by field
+type ValidationError map[string]string
+
+func (e ValidationError) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field + ": " + e[field]
+	}
+	return "invalid profile update: " + strings.Join(messages, "; ")
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u *ProfileUpdate) IsEmpty() bool {
+	return u.Username == nil && u.FullName == nil && u.Bio == nil && u.Work == nil &&
+		u.Education == nil && u.Email == nil && u.Phone == nil && u.Website == nil &&
+		u.Location == nil && u.TechSkills == nil && u.SoftSkills == nil && u.Interests == nil &&
+		u.IsPrivate == nil && u.ProfileImage == nil && u.BannerImage == nil && len(u.Visibility) == 0
+}
+
+// Normalize trims the update's values into the form they're stored in:
+// surrounding spaces are dropped, phone numbers lose their separators,
+// websites default to https and skill lists lose blanks and duplicates
+func (u *ProfileUpdate) Normalize() {
+	for _, field := range []*string{
+		u.Username, u.FullName, u.Bio, u.Work, u.Education,
+		u.Email, u.Phone, u.Website, u.Location,
+	} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+
+	if u.Phone != nil {
+		*u.Phone = phoneSeparators.ReplaceAllString(*u.Phone, "")
+	}
+
+	if u.Website != nil && *u.Website != "" && !strings.Contains(*u.Website, "://") {
+		*u.Website = "https://" + *u.Website
+	}
+
+	for _, list := range []*[]string{u.TechSkills, u.SoftSkills, u.Interests} {
+		if list != nil {
+			*list = normalizeSkills(*list)
+		}
+	}
+}
+
+// Validate checks the update against each field's rules. It returns a
+// ValidationError listing every invalid field.
+func (u *ProfileUpdate) Validate() error {
+	errs := ValidationError{}
+
+	checkLength := func(field string, value *string, max int) {
+		if value != nil && utf8.RuneCountInString(*value) > max {
+			errs[field] = "must be at most " + strconv.Itoa(max) + " characters"
+		}
+	}
+	checkLength("username", u.Username, maxUsernameLength)
+	checkLength("fullName", u.FullName, maxFullNameLength)
+	checkLength("bio", u.Bio, maxBioLength)
+	checkLength("work", u.Work, maxWorkLength)
+	checkLength("education", u.Education, maxEducationLength)
+	checkLength("location", u.Location, maxLocationLength)
+
+	if u.Email != nil && *u.Email != "" {
+		if len(*u.Email) > maxEmailLength {
+			errs["email"] = "must be at most " + strconv.Itoa(maxEmailLength) + " characters"
+		} else if address, err := mail.ParseAddress(*u.Email); err != nil || address.Address != *u.Email {
+			errs["email"] = "must be a valid email address"
+		}
+	}
+
+	if u.Phone != nil && *u.Phone != "" && !e164Pattern.MatchString(*u.Phone) {
+		errs["phone"] = "must be in international format, like +254712345678"
+	}
+
+	if u.Website != nil && *u.Website != "" {
+		if len(*u.Website) > maxWebsiteLength {
+			errs["website"] = "must be at most " + strconv.Itoa(maxWebsiteLength) + " characters"
+		} else if !isWebURL(*u.Website) {
+			errs["website"] = "must be an http or https URL"
+		}
+	}
+
+	checkSkills := func(field string, list *[]string) {
+		if list == nil {
+			return
+		}
+		if len(*list) > maxSkillsPerList {
+			errs[field] = "can have at most " + strconv.Itoa(maxSkillsPerList) + " entries"
+			return
+		}
+		for _, skill := range *list {
+			if utf8.RuneCountInString(skill) > maxSkillLength {
+				errs[field] = "entries must be at most " + strconv.Itoa(maxSkillLength) + " characters"
+				return
+			}
+		}
+	}
+	checkSkills("techSkills", u.TechSkills)
+	checkSkills("softSkills", u.SoftSkills)
+	checkSkills("interests", u.Interests)
+
+	for field, audience := range u.Visibility {
+		if !visibilityFields[field] {
+			errs["visibility."+field] = "is not a field whose visibility can be set"
+		} else if !audiences[audience] {
+			errs["visibility."+field] = "must be everyone, followers, mutuals or nobody"
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// isWebURL checks that a value is an absolute http or https URL
+func isWebURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// normalizeSkills trims a skill list and drops blanks and case-insensitive
+// duplicates, keeping the first spelling
+func normalizeSkills(skills []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, skill)
+	}
+	return normalized
+}
+
+// splitSkills splits a legacy comma-separated skill list
+func splitSkills(value string) []string {
+	return normalizeSkills(strings.Split(value, ","))
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/pkg/httputil"
+	"github.com/Athooh/social-network/pkg/logger"
+)
+
+// Handler handles HTTP requests for reports and moderation
+type Handler struct {
+	service Service
+	log     *logger.Logger
+}
+
+// NewHandler creates a new report handler
+func NewHandler(service Service, log *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		log:     log,
+	}
+}
+
+// CreateReport handles reporting content or a profile
+func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed %s", r.Method))
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		TargetType string `json:"targetType"`
+		TargetID   string `json:"targetId"`
+		Reason     string `json:"reason"`
+		Details    string `json:"details"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	report, err := h.service.CreateReport(userID, request.TargetType, request.TargetID, request.Reason, request.Details)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusCreated, report)
+}
+
+// GetQueue handles listing reports for site and group moderators
+func (h *Handler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed %s", r.Method))
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	groupID := query.Get("groupId")
+
+	// Default to the open queue; "all" lists reports in every status
+	status := query.Get("status")
+	if status == "" {
+		status = "open"
+	} else if status == "all" {
+		status = ""
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	reports, err := h.service.GetQueue(userID, groupID, status, limit, offset)
+	if err != nil {
+		h.sendError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, reports)
+}
+
+// TakeAction handles a moderator action on a report
+func (h *Handler) TakeAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed %s", r.Method))
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var request struct {
+		ReportID string `json:"reportId"`
+		Action   string `json:"action"`
+		Note     string `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.ReportID == "" || request.Action == "" {
+		h.sendError(w, http.StatusBadRequest, "Report ID and action are required")
+		return
+	}
+
+	report, err := h.service.TakeAction(request.ReportID, userID, request.Action, request.Note)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, report)
+}
+
+// Helper method to send JSON responses
+func (h *Handler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	httputil.SendJSON(w, status, data)
+}
+
+// Helper method to send error responses
+func (h *Handler) sendError(w http.ResponseWriter, status int, message string) {
+	var isWarning bool = false
+	if status >= 500 {
+		isWarning = true
+	}
+	httputil.SendError(w, status, message, isWarning)
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"database/sql"
+	"time"
+
+	notifications "github.com/Athooh/social-network/internal/notifcations"
+	"github.com/Athooh/social-network/pkg/logger"
+	"github.com/Athooh/social-network/pkg/websocket"
+	"github.com/Athooh/social-network/pkg/websocket/events"
+)
+
+// NotificationService sends moderation notifications to reporters and authors
+type NotificationService struct {
+	repo             Repository
+	hub              *websocket.Hub
+	notificationSRVC notifications.Service
+	log              *logger.Logger
+}
+
+// NewNotificationService creates a new report notification service
+func NewNotificationService(repo Repository, hub *websocket.Hub, notificationSRVC notifications.Service, log *logger.Logger) *NotificationService {
+	return &NotificationService{
+		repo:             repo,
+		hub:              hub,
+		notificationSRVC: notificationSRVC,
+		log:              log,
+	}
+}
+
+// Notify stores a moderation notification for a user and pushes it over WebSocket
+func (s *NotificationService) Notify(userID, moderatorID, notificationType, message, groupID string) {
+	newNote := &notifications.NewNotification{
+		UserId:          userID,
+		SenderId:        sql.NullString{String: moderatorID, Valid: true},
+		NotficationType: notificationType,
+		Message:         message,
+		TargetGroupID:   sql.NullString{String: groupID, Valid: groupID != ""},
+	}
+
+	if err := s.notificationSRVC.CreateNotification(newNote); err != nil {
+		s.log.Error("Failed to create moderation notification: %v", err)
+		return
+	}
+
+	if s.hub == nil {
+		s.log.Warn("WebSocket hub is nil, cannot send moderation notification")
+		return
+	}
+
+	// Retrieve the newly created notification to get its ID and CreatedAt
+	notifications, err := s.notificationSRVC.GetNotifications(userID, 1, 0)
+	if err != nil || len(notifications) == 0 {
+		s.log.Error("Failed to retrieve newly created notification: %v", err)
+		return
+	}
+	dbNotification := notifications[0]
+
+	senderName, senderAvatar := "", ""
+	if moderator, err := s.repo.GetUserBasicByID(moderatorID); err == nil && moderator != nil {
+		senderName = moderator.FirstName + " " + moderator.LastName
+		senderAvatar = moderator.Avatar
+	}
+
+	event := events.Event{
+		Type: events.HeaderNotificationUpdate,
+		Payload: map[string]interface{}{
+			"id":            dbNotification.ID,
+			"type":          notificationType,
+			"senderId":      moderatorID,
+			"targetGroupId": groupID,
+			"senderName":    senderName,
+			"senderAvatar":  senderAvatar,
+			"message":       message,
+			"createdAt":     dbNotification.CreatedAt.Format(time.RFC3339),
+			"isRead":        dbNotification.IsRead,
+		},
+	}
+
+	s.hub.BroadcastToUser(userID, event)
+}
@@ -0,0 +1,425 @@
+package report
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+	"github.com/google/uuid"
+)
+
+// Target describes the reported content or profile
+type Target struct {
+	AuthorID    string
+	RecipientID string // receiver of a reported private message
+	PostID      int64  // post a reported comment belongs to
+	GroupID     string
+	Preview     string
+	IsHidden    bool
+}
+
+// contentTables maps hideable and deletable target types to their tables
+var contentTables = map[string]string{
+	models.ReportTargetPost:         "posts",
+	models.ReportTargetComment:      "comments",
+	models.ReportTargetGroupPost:    "group_posts",
+	models.ReportTargetMessage:      "private_messages",
+	models.ReportTargetGroupMessage: "group_chat_messages",
+}
+
+// Repository defines the interface for report data access
+type Repository interface {
+	// Report operations
+	CreateReport(report *models.Report) error
+	GetReportByID(id string) (*models.Report, error)
+	GetReports(groupID, status string, limit, offset int) ([]*models.Report, error)
+	GetOpenReportsForTarget(targetType, targetID string) ([]*models.Report, error)
+	HasReported(reporterID, targetType, targetID string) (bool, error)
+	CountOpenReports(targetType, targetID string) (int, error)
+	ResolveReports(targetType, targetID, status, action, resolvedBy string) error
+
+	// Target operations
+	GetTarget(targetType, targetID string) (*Target, error)
+	HideContent(targetType, targetID string, auto bool) error
+	RestoreAutoHidden(targetType, targetID string) error
+	DeleteContent(targetType, targetID string) error
+
+	// Permission helpers
+	IsSiteModerator(userID string) (bool, error)
+	IsGroupMember(groupID, userID string) (bool, error)
+	GetUserBasicByID(userID string) (*models.UserBasic, error)
+}
+
+// SQLiteRepository implements Repository interface for SQLite
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository creates a new SQLite repository
+func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// CreateReport creates a new report
+func (r *SQLiteRepository) CreateReport(report *models.Report) error {
+	if report.ID == "" {
+		report.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	report.CreatedAt = now
+	report.UpdatedAt = now
+	report.Status = models.ReportStatusOpen
+
+	query := `
+		INSERT INTO reports (
+			id, reporter_id, target_type, target_id, target_author_id, group_id,
+			reason, details, status, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		report.ID,
+		report.ReporterID,
+		report.TargetType,
+		report.TargetID,
+		report.TargetAuthorID,
+		report.GroupID,
+		report.Reason,
+		report.Details,
+		report.Status,
+		report.CreatedAt,
+		report.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	return nil
+}
+
+// reportColumns lists the columns scanned by scanReport
+const reportColumns = `
+	r.id, r.reporter_id, r.target_type, r.target_id, COALESCE(r.target_author_id, ''),
+	COALESCE(r.group_id, ''), r.reason, COALESCE(r.details, ''), r.status,
+	COALESCE(r.action, ''), COALESCE(r.resolved_by, ''), r.resolved_at, r.created_at, r.updated_at,
+	(SELECT COUNT(*) FROM reports o
+		WHERE o.target_type = r.target_type AND o.target_id = r.target_id AND o.status = 'open')
+`
+
+// scanReport scans a row selected with reportColumns
+func scanReport(scanner interface{ Scan(...interface{}) error }) (*models.Report, error) {
+	report := &models.Report{}
+	var resolvedAt sql.NullTime
+
+	err := scanner.Scan(
+		&report.ID,
+		&report.ReporterID,
+		&report.TargetType,
+		&report.TargetID,
+		&report.TargetAuthorID,
+		&report.GroupID,
+		&report.Reason,
+		&report.Details,
+		&report.Status,
+		&report.Action,
+		&report.ResolvedBy,
+		&resolvedAt,
+		&report.CreatedAt,
+		&report.UpdatedAt,
+		&report.ReportCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolvedAt.Valid {
+		report.ResolvedAt = resolvedAt.Time
+	}
+
+	return report, nil
+}
+
+// GetReportByID retrieves a report by ID
+func (r *SQLiteRepository) GetReportByID(id string) (*models.Report, error) {
+	query := "SELECT " + reportColumns + " FROM reports r WHERE r.id = ?"
+
+	report, err := scanReport(r.db.QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return report, nil
+}
+
+// GetReports retrieves reports, newest first. An empty groupID returns reports
+// for all content; an empty status returns reports in any status.
+func (r *SQLiteRepository) GetReports(groupID, status string, limit, offset int) ([]*models.Report, error) {
+	query := "SELECT " + reportColumns + `
+		FROM reports r
+		WHERE (? = '' OR r.group_id = ?)
+			AND (? = '' OR r.status = ?)
+		ORDER BY r.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, groupID, groupID, status, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// GetOpenReportsForTarget retrieves all open reports for a target
+func (r *SQLiteRepository) GetOpenReportsForTarget(targetType, targetID string) ([]*models.Report, error) {
+	query := "SELECT " + reportColumns + `
+		FROM reports r
+		WHERE r.target_type = ? AND r.target_id = ? AND r.status = 'open'
+	`
+
+	rows, err := r.db.Query(query, targetType, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports for target: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// HasReported checks whether a user already has an open report for a target
+func (r *SQLiteRepository) HasReported(reporterID, targetType, targetID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM reports
+			WHERE reporter_id = ? AND target_type = ? AND target_id = ? AND status = 'open'
+		)
+	`, reporterID, targetType, targetID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing report: %w", err)
+	}
+
+	return exists, nil
+}
+
+// CountOpenReports counts the distinct users with an open report for a target.
+// Flags raised by the content filters aren't counted.
+func (r *SQLiteRepository) CountOpenReports(targetType, targetID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(DISTINCT reporter_id) FROM reports
+		WHERE target_type = ? AND target_id = ? AND status = 'open' AND reporter_id != ?
+	`, targetType, targetID, models.SystemReporterID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+
+	return count, nil
+}
+
+// ResolveReports closes every open report for a target
+func (r *SQLiteRepository) ResolveReports(targetType, targetID, status, action, resolvedBy string) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE reports
+		SET status = ?, action = ?, resolved_by = ?, resolved_at = ?, updated_at = ?
+		WHERE target_type = ? AND target_id = ? AND status = 'open'
+	`, status, action, resolvedBy, now, now, targetType, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve reports: %w", err)
+	}
+
+	return nil
+}
+
+// GetTarget retrieves the reported content or profile, or nil if it no longer exists
+func (r *SQLiteRepository) GetTarget(targetType, targetID string) (*Target, error) {
+	target := &Target{}
+	var err error
+
+	switch targetType {
+	case models.ReportTargetPost:
+		err = r.db.QueryRow(
+			"SELECT user_id, content, is_hidden FROM posts WHERE id = ?", targetID,
+		).Scan(&target.AuthorID, &target.Preview, &target.IsHidden)
+	case models.ReportTargetGroupPost:
+		err = r.db.QueryRow(
+			"SELECT user_id, group_id, COALESCE(content, ''), is_hidden FROM group_posts WHERE id = ?", targetID,
+		).Scan(&target.AuthorID, &target.GroupID, &target.Preview, &target.IsHidden)
+	case models.ReportTargetComment:
+		err = r.db.QueryRow(`
+			SELECT c.user_id, c.post_id, c.content, c.is_hidden, COALESCE(gp.group_id, '')
+			FROM comments c
+			LEFT JOIN group_posts gp ON gp.id = c.post_id
+			WHERE c.id = ?
+		`, targetID).Scan(&target.AuthorID, &target.PostID, &target.Preview, &target.IsHidden, &target.GroupID)
+	case models.ReportTargetMessage:
+		err = r.db.QueryRow(
+			"SELECT sender_id, receiver_id, content, is_hidden FROM private_messages WHERE id = ?", targetID,
+		).Scan(&target.AuthorID, &target.RecipientID, &target.Preview, &target.IsHidden)
+	case models.ReportTargetGroupMessage:
+		err = r.db.QueryRow(
+			"SELECT user_id, group_id, content, is_hidden FROM group_chat_messages WHERE id = ?", targetID,
+		).Scan(&target.AuthorID, &target.GroupID, &target.Preview, &target.IsHidden)
+	case models.ReportTargetProfile:
+		err = r.db.QueryRow(
+			"SELECT id, first_name || ' ' || last_name FROM users WHERE id = ?", targetID,
+		).Scan(&target.AuthorID, &target.Preview)
+	default:
+		return nil, fmt.Errorf("unknown report target type: %s", targetType)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get report target: %w", err)
+	}
+
+	return target, nil
+}
+
+// HideContent hides reported content, marking whether reports hid it
+// automatically or a moderator did
+func (r *SQLiteRepository) HideContent(targetType, targetID string, auto bool) error {
+	table, ok := contentTables[targetType]
+	if !ok {
+		return fmt.Errorf("content of type %s cannot be hidden", targetType)
+	}
+
+	_, err := r.db.Exec(fmt.Sprintf("UPDATE %s SET is_hidden = TRUE, auto_hidden = ? WHERE id = ?", table), auto, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to hide content: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreAutoHidden unhides content if reports hid it automatically. Content
+// a moderator hid stays hidden.
+func (r *SQLiteRepository) RestoreAutoHidden(targetType, targetID string) error {
+	table, ok := contentTables[targetType]
+	if !ok {
+		return fmt.Errorf("content of type %s cannot be hidden", targetType)
+	}
+
+	_, err := r.db.Exec(fmt.Sprintf("UPDATE %s SET is_hidden = FALSE, auto_hidden = FALSE WHERE id = ? AND auto_hidden = TRUE", table), targetID)
+	if err != nil {
+		return fmt.Errorf("failed to restore content: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteContent deletes a reported comment or message. Posts are deleted by
+// the services that own them.
+func (r *SQLiteRepository) DeleteContent(targetType, targetID string) error {
+	table, ok := contentTables[targetType]
+	if !ok {
+		return fmt.Errorf("content of type %s cannot be deleted", targetType)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if targetType == models.ReportTargetComment {
+		var postID int64
+		if err := tx.QueryRow("SELECT post_id FROM comments WHERE id = ?", targetID).Scan(&postID); err != nil {
+			return fmt.Errorf("failed to get comment post: %w", err)
+		}
+		for _, postTable := range []string{"posts", "group_posts"} {
+			query := fmt.Sprintf("UPDATE %s SET comments_count = comments_count - 1 WHERE id = ? AND comments_count > 0", postTable)
+			if _, err := tx.Exec(query, postID); err != nil {
+				return fmt.Errorf("failed to update comment count: %w", err)
+			}
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", table), targetID); err != nil {
+		return fmt.Errorf("failed to delete content: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsSiteModerator checks whether a user is a site-wide moderator
+func (r *SQLiteRepository) IsSiteModerator(userID string) (bool, error) {
+	var isModerator bool
+	err := r.db.QueryRow("SELECT is_moderator FROM users WHERE id = ?", userID).Scan(&isModerator)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check moderator: %w", err)
+	}
+
+	return isModerator, nil
+}
+
+// IsGroupMember checks whether a user is an accepted member of a group
+func (r *SQLiteRepository) IsGroupMember(groupID, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM group_members
+			WHERE group_id = ? AND user_id = ? AND status = 'accepted'
+		)
+	`, groupID, userID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check group member: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetUserBasicByID gets basic user information by ID
+func (r *SQLiteRepository) GetUserBasicByID(userID string) (*models.UserBasic, error) {
+	user := &models.UserBasic{}
+	var avatar sql.NullString
+
+	err := r.db.QueryRow(
+		"SELECT id, first_name, last_name, avatar FROM users WHERE id = ?", userID,
+	).Scan(&user.ID, &user.FirstName, &user.LastName, &avatar)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	user.Avatar = avatar.String
+
+	return user, nil
+}
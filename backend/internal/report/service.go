@@ -0,0 +1,419 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
+
+const maxDetailsLength = 1000
+
+// validReasons lists the accepted report reason categories
+var validReasons = map[string]bool{
+	models.ReportReasonSpam:           true,
+	models.ReportReasonHarassment:     true,
+	models.ReportReasonHateSpeech:     true,
+	models.ReportReasonViolence:       true,
+	models.ReportReasonNudity:         true,
+	models.ReportReasonMisinformation: true,
+	models.ReportReasonOther:          true,
+}
+
+// targetLabels gives a readable name for each target type in notifications
+var targetLabels = map[string]string{
+	models.ReportTargetPost:         "post",
+	models.ReportTargetComment:      "comment",
+	models.ReportTargetGroupPost:    "group post",
+	models.ReportTargetMessage:      "message",
+	models.ReportTargetGroupMessage: "group chat message",
+	models.ReportTargetProfile:      "profile",
+}
+
+// Service defines the report service interface
+type Service interface {
+	CreateReport(reporterID, targetType, targetID, reason, details string) (*models.Report, error)
+	GetQueue(userID, groupID, status string, limit, offset int) ([]*models.Report, error)
+	TakeAction(reportID, moderatorID, action, note string) (*models.Report, error)
+	FlagContent(targetType, targetID, reason string) error
+}
+
+// PostModerator checks who can see posts and removes reported ones
+type PostModerator interface {
+	CanViewPost(postID int64, userID string) (bool, error)
+	RemovePost(postID int64) error
+}
+
+// GroupModerator checks what members may do in their groups and removes
+// reported group posts
+type GroupModerator interface {
+	CanPerform(groupID, userID, action string) (bool, error)
+	RemoveGroupPost(postID int64) error
+}
+
+// ReportService implements the Service interface
+type ReportService struct {
+	repo              Repository
+	log               *logger.Logger
+	notificationSvc   *NotificationService
+	posts             PostModerator
+	groups            GroupModerator
+	autoHideThreshold int
+}
+
+// NewService creates a new report service. Content is hidden automatically once
+// autoHideThreshold users have open reports for it; 0 disables auto-hiding.
+func NewService(repo Repository, log *logger.Logger, notificationSvc *NotificationService, posts PostModerator, groups GroupModerator, autoHideThreshold int) Service {
+	return &ReportService{
+		repo:              repo,
+		log:               log,
+		notificationSvc:   notificationSvc,
+		posts:             posts,
+		groups:            groups,
+		autoHideThreshold: autoHideThreshold,
+	}
+}
+
+// CreateReport files a report against content or a profile
+func (s *ReportService) CreateReport(reporterID, targetType, targetID, reason, details string) (*models.Report, error) {
+	if _, ok := targetLabels[targetType]; !ok {
+		return nil, errors.New("invalid report target type")
+	}
+	if targetID == "" {
+		return nil, errors.New("target ID is required")
+	}
+	if !validReasons[reason] {
+		return nil, errors.New("invalid report reason")
+	}
+
+	details = strings.TrimSpace(details)
+	if len(details) > maxDetailsLength {
+		return nil, errors.New("report details are too long")
+	}
+
+	target, err := s.repo.GetTarget(targetType, targetID)
+	if err != nil {
+		s.log.Error("Failed to get report target: %v", err)
+		return nil, err
+	}
+	if target == nil {
+		return nil, errors.New("reported content not found")
+	}
+
+	if target.AuthorID == reporterID {
+		return nil, errors.New("you cannot report your own content")
+	}
+
+	canView, err := s.canViewTarget(reporterID, targetType, targetID, target)
+	if err != nil {
+		s.log.Error("Failed to check report target visibility: %v", err)
+		return nil, err
+	}
+	if !canView {
+		return nil, errors.New("reported content not found")
+	}
+
+	alreadyReported, err := s.repo.HasReported(reporterID, targetType, targetID)
+	if err != nil {
+		s.log.Error("Failed to check existing report: %v", err)
+		return nil, err
+	}
+	if alreadyReported {
+		return nil, errors.New("you have already reported this")
+	}
+
+	report := &models.Report{
+		ReporterID:     reporterID,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		TargetAuthorID: target.AuthorID,
+		GroupID:        target.GroupID,
+		Reason:         reason,
+		Details:        details,
+	}
+
+	if err := s.repo.CreateReport(report); err != nil {
+		s.log.Error("Failed to create report: %v", err)
+		return nil, err
+	}
+
+	s.autoHide(targetType, targetID, target)
+
+	return report, nil
+}
+
+// canViewTarget checks that the reporter can see the content they're reporting
+func (s *ReportService) canViewTarget(reporterID, targetType, targetID string, target *Target) (bool, error) {
+	switch targetType {
+	case models.ReportTargetPost:
+		postID, err := strconv.ParseInt(targetID, 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		return s.posts.CanViewPost(postID, reporterID)
+	case models.ReportTargetComment:
+		// Comments can be seen by whoever can see their post
+		return s.posts.CanViewPost(target.PostID, reporterID)
+	case models.ReportTargetMessage:
+		// Only the recipient of a private message can report it
+		return target.RecipientID == reporterID, nil
+	case models.ReportTargetGroupPost, models.ReportTargetGroupMessage:
+		// Only members of a group can report its posts and chat messages
+		return s.repo.IsGroupMember(target.GroupID, reporterID)
+	}
+
+	return true, nil
+}
+
+// autoHide hides content once enough distinct users have reported it
+func (s *ReportService) autoHide(targetType, targetID string, target *Target) {
+	if s.autoHideThreshold <= 0 || target.IsHidden {
+		return
+	}
+	if _, ok := contentTables[targetType]; !ok {
+		return
+	}
+
+	count, err := s.repo.CountOpenReports(targetType, targetID)
+	if err != nil {
+		s.log.Error("Failed to count reports for auto-hide: %v", err)
+		return
+	}
+
+	if count < s.autoHideThreshold {
+		return
+	}
+
+	if err := s.repo.HideContent(targetType, targetID, true); err != nil {
+		s.log.Error("Failed to auto-hide reported content: %v", err)
+		return
+	}
+
+	s.log.Info("Auto-hid %s %s after %d reports", targetType, targetID, count)
+}
+
+// FlagContent queues content flagged by the content filters for moderation
+func (s *ReportService) FlagContent(targetType, targetID, reason string) error {
+	if _, ok := targetLabels[targetType]; !ok {
+		return fmt.Errorf("content of type %s cannot be queued for moderation", targetType)
+	}
+
+	target, err := s.repo.GetTarget(targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return errors.New("flagged content not found")
+	}
+
+	report := &models.Report{
+		ReporterID:     models.SystemReporterID,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		TargetAuthorID: target.AuthorID,
+		GroupID:        target.GroupID,
+		Reason:         models.ReportReasonAutoFlagged,
+		Details:        reason,
+	}
+
+	return s.repo.CreateReport(report)
+}
+
+// GetQueue lists reports for moderation. Without a group ID the caller must be a
+// site moderator; with one, members who may delete the group's posts see the
+// reports for its content.
+func (s *ReportService) GetQueue(userID, groupID, status string, limit, offset int) ([]*models.Report, error) {
+	if err := s.checkModerator(userID, groupID); err != nil {
+		return nil, err
+	}
+
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	reports, err := s.repo.GetReports(groupID, status, limit, offset)
+	if err != nil {
+		s.log.Error("Failed to get report queue: %v", err)
+		return nil, err
+	}
+
+	for _, report := range reports {
+		if reporter, err := s.repo.GetUserBasicByID(report.ReporterID); err == nil {
+			report.Reporter = reporter
+		}
+		if report.TargetAuthorID != "" {
+			if author, err := s.repo.GetUserBasicByID(report.TargetAuthorID); err == nil {
+				report.TargetAuthor = author
+			}
+		}
+		if target, err := s.repo.GetTarget(report.TargetType, report.TargetID); err == nil && target != nil {
+			report.ContentPreview = target.Preview
+			report.IsHidden = target.IsHidden
+		}
+	}
+
+	return reports, nil
+}
+
+// TakeAction applies a moderation action to a report's target, resolves every
+// open report for that target and notifies the reporters and the author
+func (s *ReportService) TakeAction(reportID, moderatorID, action, note string) (*models.Report, error) {
+	report, err := s.repo.GetReportByID(reportID)
+	if err != nil {
+		s.log.Error("Failed to get report: %v", err)
+		return nil, err
+	}
+	if report == nil {
+		return nil, errors.New("report not found")
+	}
+
+	if err := s.checkModerator(moderatorID, report.GroupID); err != nil {
+		return nil, err
+	}
+
+	if report.Status != models.ReportStatusOpen {
+		return nil, errors.New("report has already been resolved")
+	}
+
+	// Collect the reporters before the reports are closed
+	openReports, err := s.repo.GetOpenReportsForTarget(report.TargetType, report.TargetID)
+	if err != nil {
+		s.log.Error("Failed to get open reports: %v", err)
+		return nil, err
+	}
+
+	_, hideable := contentTables[report.TargetType]
+	status := models.ReportStatusResolved
+
+	switch action {
+	case models.ModerationActionDismiss:
+		status = models.ReportStatusDismissed
+		// Restore content that was auto-hidden by these reports
+		if hideable {
+			if err := s.repo.RestoreAutoHidden(report.TargetType, report.TargetID); err != nil {
+				s.log.Error("Failed to unhide dismissed content: %v", err)
+				return nil, err
+			}
+		}
+	case models.ModerationActionHide:
+		if !hideable {
+			return nil, errors.New("this content cannot be hidden")
+		}
+		if err := s.repo.HideContent(report.TargetType, report.TargetID, false); err != nil {
+			s.log.Error("Failed to hide content: %v", err)
+			return nil, err
+		}
+	case models.ModerationActionDelete:
+		if !hideable {
+			return nil, errors.New("this content cannot be deleted")
+		}
+		if err := s.deleteContent(report.TargetType, report.TargetID); err != nil {
+			s.log.Error("Failed to delete content: %v", err)
+			return nil, err
+		}
+	case models.ModerationActionWarn:
+		// The warning is delivered to the author as a notification below
+	default:
+		return nil, errors.New("invalid moderation action")
+	}
+
+	if err := s.repo.ResolveReports(report.TargetType, report.TargetID, status, action, moderatorID); err != nil {
+		s.log.Error("Failed to resolve reports: %v", err)
+		return nil, err
+	}
+
+	report.Status = status
+	report.Action = action
+	report.ResolvedBy = moderatorID
+
+	if s.notificationSvc != nil {
+		go s.notifyAction(report, openReports, moderatorID, action, note)
+	}
+
+	return report, nil
+}
+
+// deleteContent deletes reported content. Posts are removed by the services
+// that own them, which also delete their files and update their counts.
+func (s *ReportService) deleteContent(targetType, targetID string) error {
+	switch targetType {
+	case models.ReportTargetPost, models.ReportTargetGroupPost:
+		postID, err := strconv.ParseInt(targetID, 10, 64)
+		if err != nil {
+			return errors.New("invalid post ID")
+		}
+		if targetType == models.ReportTargetGroupPost {
+			return s.groups.RemoveGroupPost(postID)
+		}
+		return s.posts.RemovePost(postID)
+	}
+
+	return s.repo.DeleteContent(targetType, targetID)
+}
+
+// notifyAction notifies the reporters and, unless dismissed, the author
+func (s *ReportService) notifyAction(report *models.Report, openReports []*models.Report, moderatorID, action, note string) {
+	label := targetLabels[report.TargetType]
+
+	reporterMessage := fmt.Sprintf("Your report about a %s was reviewed and action was taken.", label)
+	if action == models.ModerationActionDismiss {
+		reporterMessage = fmt.Sprintf("Your report about a %s was reviewed. No action was needed.", label)
+	}
+
+	for _, openReport := range openReports {
+		if openReport.ReporterID == models.SystemReporterID {
+			continue
+		}
+		s.notificationSvc.Notify(openReport.ReporterID, moderatorID, "reportReviewed", reporterMessage, report.GroupID)
+	}
+
+	if action == models.ModerationActionDismiss || report.TargetAuthorID == "" {
+		return
+	}
+
+	var authorMessage string
+	switch action {
+	case models.ModerationActionHide:
+		authorMessage = fmt.Sprintf("Your %s was hidden for violating the community guidelines.", label)
+	case models.ModerationActionDelete:
+		authorMessage = fmt.Sprintf("Your %s was removed for violating the community guidelines.", label)
+	case models.ModerationActionWarn:
+		authorMessage = fmt.Sprintf("You received a warning about your %s.", label)
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		authorMessage += " " + note
+	}
+
+	s.notificationSvc.Notify(report.TargetAuthorID, moderatorID, "moderationAction", authorMessage, report.GroupID)
+}
+
+// checkModerator checks that the user may moderate reports in the given scope
+func (s *ReportService) checkModerator(userID, groupID string) error {
+	isModerator, err := s.repo.IsSiteModerator(userID)
+	if err != nil {
+		s.log.Error("Failed to check site moderator: %v", err)
+		return err
+	}
+	if isModerator {
+		return nil
+	}
+
+	if groupID != "" {
+		canModerate, err := s.groups.CanPerform(groupID, userID, models.GroupActionDeletePosts)
+		if err != nil {
+			s.log.Error("Failed to check group permission: %v", err)
+			return err
+		}
+		if canModerate {
+			return nil
+		}
+	}
+
+	return errors.New("you don't have permission to moderate these reports")
+}
@@ -3,14 +3,21 @@ package server
 import (
 	"net/http"
 
+	"github.com/Athooh/social-network/internal/analytics"
 	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/internal/bookmark"
 	"github.com/Athooh/social-network/internal/chat"
 	"github.com/Athooh/social-network/internal/event"
 	"github.com/Athooh/social-network/internal/follow"
 	"github.com/Athooh/social-network/internal/group"
+	"github.com/Athooh/social-network/internal/handle"
+	"github.com/Athooh/social-network/internal/mute"
 	notifications "github.com/Athooh/social-network/internal/notifcations"
 	"github.com/Athooh/social-network/internal/post"
+	"github.com/Athooh/social-network/internal/privacy"
 	"github.com/Athooh/social-network/internal/profile"
+	"github.com/Athooh/social-network/internal/report"
+	"github.com/Athooh/social-network/internal/user"
 	websocketHandler "github.com/Athooh/social-network/internal/websocket"
 	"github.com/Athooh/social-network/pkg/httputil"
 	"github.com/Athooh/social-network/pkg/logger"
@@ -62,6 +69,13 @@ type RouterConfig struct {
 	ChatHandler         *chat.Handler
 	ProfileHandler      *profile.Handler
 	NotificationHanlder *notifications.Handler
+	BookmarkHandler     *bookmark.Handler
+	ReportHandler       *report.Handler
+	AnalyticsHandler    *analytics.Handler
+	MuteHandler         *mute.Handler
+	PrivacyHandler      *privacy.Handler
+	StatusHandler       *user.StatusHandler
+	HandleHandler       *handle.Handler
 	AuthMiddleware      func(http.Handler) http.Handler
 	JWTMiddleware       func(http.Handler) http.Handler
 	Logger              *logger.Logger
@@ -105,10 +119,17 @@ func Router(config RouterConfig) http.Handler {
 
 	protectedUserGroup := NewRouteGroup("/api/users", authenticatedRouteMiddleware)
 	protectedUserGroup.HandleFunc("/me", config.AuthHandler.Me)
+	protectedUserGroup.HandleFunc("/me/analytics", config.AnalyticsHandler.GetMyAnalytics)
+	protectedUserGroup.HandleFunc("/me/privacy", config.PrivacyHandler.HandleSettings)
+	protectedUserGroup.HandleFunc("/presence", config.StatusHandler.HandlePresence)
+	protectedUserGroup.HandleFunc("/me/status", config.StatusHandler.HandleCustomStatus)
+	protectedUserGroup.HandleFunc("/me/handle", config.HandleHandler.HandleMyHandle)
+	protectedUserGroup.HandleFunc("/by-handle/", config.HandleHandler.GetProfileByHandle)
+	protectedUserGroup.HandleFunc("/mentions/resolve", config.HandleHandler.ResolveMentions)
 
 	protectedUserGroup.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
-		case http.MethodPut:
+		case http.MethodPut, http.MethodPatch:
 			config.ProfileHandler.UpdateProfile(w, r)
 		case http.MethodGet:
 			config.ProfileHandler.GetUserProfile(w, r)
@@ -121,12 +142,17 @@ func Router(config RouterConfig) http.Handler {
 	protectedFollowGroup.HandleFunc("/follow", config.FollowHandler.FollowUser)
 	protectedFollowGroup.HandleFunc("/unfollow", config.FollowHandler.UnfollowUser)
 	protectedFollowGroup.HandleFunc("/suggested-friends", config.FollowHandler.GetSuggestedFriends)
+	protectedFollowGroup.HandleFunc("/suggested-friends/dismiss", config.FollowHandler.DismissSuggestion)
 	protectedFollowGroup.HandleFunc("/accept", config.FollowHandler.AcceptFollowRequest)
 	protectedFollowGroup.HandleFunc("/decline", config.FollowHandler.DeclineFollowRequest)
+	protectedFollowGroup.HandleFunc("/cancel", config.FollowHandler.CancelFollowRequest)
+	protectedFollowGroup.HandleFunc("/accept-bulk", config.FollowHandler.BulkAcceptFollowRequests)
+	protectedFollowGroup.HandleFunc("/decline-bulk", config.FollowHandler.BulkDeclineFollowRequests)
 	protectedFollowGroup.HandleFunc("/pending-requests", config.FollowHandler.GetPendingFollowRequests)
 	protectedFollowGroup.HandleFunc("/followers", config.FollowHandler.GetFollowers)
 	protectedFollowGroup.HandleFunc("/following", config.FollowHandler.GetFollowing)
 	protectedFollowGroup.HandleFunc("/is-following", config.FollowHandler.IsFollowing)
+	protectedFollowGroup.HandleFunc("/favorite", config.FollowHandler.SetFavorite)
 
 	protectedPostGroup := NewRouteGroup("/api/posts", authenticatedRouteMiddleware)
 	protectedPostGroup.HandleFunc("", func(w http.ResponseWriter, r *http.Request) {
@@ -145,6 +171,9 @@ func Router(config RouterConfig) http.Handler {
 	protectedPostGroup.HandleFunc("/user/", config.PostHandler.GetUserPosts)
 	protectedPostGroup.HandleFunc("/photos/", config.PostHandler.GetUserPhotos)
 	protectedPostGroup.HandleFunc("/like/", config.PostHandler.LikePost)
+	protectedPostGroup.HandleFunc("/viewers/", config.PostHandler.SetPostViewers)
+	protectedPostGroup.HandleFunc("/audience-lists", config.PostHandler.HandleAudienceLists)
+	protectedPostGroup.HandleFunc("/audience-lists/members", config.PostHandler.HandleAudienceListMembers)
 
 	// Add group routes
 	protectedGroupGroup := NewRouteGroup("/api/groups", authenticatedRouteMiddleware)
@@ -168,8 +197,17 @@ func Router(config RouterConfig) http.Handler {
 	})
 	protectedGroupGroup.HandleFunc("/send-message", config.GroupHandler.SendChatMessage)
 	protectedGroupGroup.HandleFunc("/get-messages", config.GroupHandler.GetGroupChatMessages)
+	protectedGroupGroup.HandleFunc("/get-messages/unread", config.GroupHandler.GetUnreadChatMessages)
+	protectedGroupGroup.HandleFunc("/messages/read", config.GroupHandler.HandleChatRead)
+	protectedGroupGroup.HandleFunc("/messages", config.GroupHandler.HandleChatMessage)
+	protectedGroupGroup.HandleFunc("/messages/pins", config.GroupHandler.HandleChatPins)
 
 	protectedGroupGroup.HandleFunc("/user", config.GroupHandler.GetUserGroups)
+	protectedGroupGroup.HandleFunc("/restore", config.GroupHandler.RestoreGroup)
+	protectedGroupGroup.HandleFunc("/transfer-ownership", config.GroupHandler.TransferOwnership)
+	protectedGroupGroup.HandleFunc("/discover", config.GroupHandler.DiscoverGroups)
+	protectedGroupGroup.HandleFunc("/recommended", config.GroupHandler.GetRecommendedGroups)
+	protectedGroupGroup.HandleFunc("/categories", config.GroupHandler.GetGroupCategories)
 	protectedGroupGroup.HandleFunc("/members", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -178,7 +216,12 @@ func Router(config RouterConfig) http.Handler {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
+	protectedGroupGroup.HandleFunc("/bans", config.GroupHandler.HandleBans)
 	protectedGroupGroup.HandleFunc("/invite", config.GroupHandler.InviteToGroup)
+	protectedGroupGroup.HandleFunc("/invite-links", config.GroupHandler.HandleInviteLinks)
+	protectedGroupGroup.HandleFunc("/invite-links/uses", config.GroupHandler.GetInviteLinkUses)
+	protectedGroupGroup.HandleFunc("/invite-links/redeem", config.GroupHandler.RedeemInviteLink)
+	protectedGroupGroup.HandleFunc("/join-questions", config.GroupHandler.HandleJoinQuestions)
 	protectedGroupGroup.HandleFunc("/join", config.GroupHandler.JoinGroup)
 	protectedGroupGroup.HandleFunc("/leave", config.GroupHandler.LeaveGroup)
 	protectedGroupGroup.HandleFunc("/accept-invitation", config.GroupHandler.AcceptInvitation)
@@ -187,6 +230,10 @@ func Router(config RouterConfig) http.Handler {
 	protectedGroupGroup.HandleFunc("/reject-request", config.GroupHandler.RejectJoinRequest)
 	protectedGroupGroup.HandleFunc("/update-role", config.GroupHandler.UpdateMemberRole)
 	protectedGroupGroup.HandleFunc("/remove-member", config.GroupHandler.RemoveMember)
+	protectedGroupGroup.HandleFunc("/permissions", config.GroupHandler.HandlePermissions)
+	protectedGroupGroup.HandleFunc("/roles", config.GroupHandler.HandleRoles)
+	protectedGroupGroup.HandleFunc("/settings", config.GroupHandler.HandleSettings)
+	protectedGroupGroup.HandleFunc("/notification-settings", config.GroupHandler.HandleNotificationSettings)
 
 	protectedGroupGroup.HandleFunc("/posts", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
@@ -200,6 +247,11 @@ func Router(config RouterConfig) http.Handler {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
+	protectedGroupGroup.HandleFunc("/posts/pending", config.GroupHandler.GetPendingGroupPosts)
+	protectedGroupGroup.HandleFunc("/posts/review", config.GroupHandler.ReviewGroupPost)
+	protectedGroupGroup.HandleFunc("/posts/pin", config.GroupHandler.HandlePin)
+	protectedGroupGroup.HandleFunc("/posts/like", config.GroupHandler.LikeGroupPost)
+	protectedGroupGroup.HandleFunc("/posts/comments", config.GroupHandler.HandleGroupPostComments)
 
 	// Add Event routes
 	protectedGroupGroup.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
@@ -256,6 +308,22 @@ func Router(config RouterConfig) http.Handler {
 		}
 	})
 	protectedNotificationGroup.HandleFunc("/read", config.NotificationHanlder.MarkAllNotificationsAsRead)
+
+	// Bookmark routes
+	protectedBookmarkGroup := NewRouteGroup("/api/bookmarks", authenticatedRouteMiddleware)
+	protectedBookmarkGroup.HandleFunc("", config.BookmarkHandler.HandleBookmarks)
+	protectedBookmarkGroup.HandleFunc("/collections", config.BookmarkHandler.HandleCollections)
+
+	// Report and moderation routes
+	protectedReportGroup := NewRouteGroup("/api/reports", authenticatedRouteMiddleware)
+	protectedReportGroup.HandleFunc("", config.ReportHandler.CreateReport)
+	protectedReportGroup.HandleFunc("/queue", config.ReportHandler.GetQueue)
+	protectedReportGroup.HandleFunc("/action", config.ReportHandler.TakeAction)
+
+	// Mute and restrict routes
+	protectedMuteGroup := NewRouteGroup("/api/mutes", authenticatedRouteMiddleware)
+	protectedMuteGroup.HandleFunc("", config.MuteHandler.HandleMutes)
+
 	// Add WebSocket route
 	wsRoute := NewRouteGroup("/ws", wsMiddleware)
 	wsRoute.HandleFunc("", config.WSHandler.HandleConnection)
@@ -268,6 +336,9 @@ func Router(config RouterConfig) http.Handler {
 	protectedGroupGroup.Register(mux)
 	protectedNotificationGroup.Register(mux)
 	protectedUserGroup.Register(mux)
+	protectedBookmarkGroup.Register(mux)
+	protectedReportGroup.Register(mux)
+	protectedMuteGroup.Register(mux)
 	chatGroup.Register(mux)
 	wsRoute.Register(mux)
 
@@ -14,8 +14,9 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	server *http.Server
-	logger *logger.Logger
+	server     *http.Server
+	logger     *logger.Logger
+	onShutdown []func()
 }
 
 // Config holds the server configuration
@@ -56,6 +57,12 @@ func New(config Config, handler http.Handler, logger *logger.Logger) *Server {
 	}
 }
 
+// OnShutdown registers a function to run once the server has stopped serving
+// requests on shutdown, such as flushing buffered writes
+func (s *Server) OnShutdown(f func()) {
+	s.onShutdown = append(s.onShutdown, f)
+}
+
 // Start starts the server
 func (s *Server) Start() error {
 	// Channel for server errors
@@ -92,7 +99,14 @@ func (s *Server) Shutdown() error {
 	defer cancel()
 
 	// Shutdown the server
-	if err := s.server.Shutdown(ctx); err != nil {
+	err := s.server.Shutdown(ctx)
+
+	// Run the shutdown hooks even if some requests didn't finish in time
+	for _, f := range s.onShutdown {
+		f()
+	}
+
+	if err != nil {
 		s.logger.Error("Server shutdown error: %v", err)
 		return err
 	}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Athooh/social-network/internal/auth"
+	"github.com/Athooh/social-network/pkg/httputil"
+	"github.com/Athooh/social-network/pkg/logger"
+)
+
+// StatusHandler handles HTTP requests for user presence
+type StatusHandler struct {
+	service *StatusService
+	log     *logger.Logger
+}
+
+// NewStatusHandler creates a new user presence handler
+func NewStatusHandler(service *StatusService, log *logger.Logger) *StatusHandler {
+	return &StatusHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// HandlePresence handles getting a user's presence (the current user's unless
+// the "userId" query parameter is given) and setting the current user's
+// presence mode
+func (h *StatusHandler) HandlePresence(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		targetID := r.URL.Query().Get("userId")
+		if targetID == "" {
+			targetID = userID
+		}
+
+		presence, err := h.service.GetPresence(userID, targetID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, "Failed to get presence")
+			return
+		}
+		h.sendJSON(w, http.StatusOK, presence)
+
+	case http.MethodPut:
+		var req struct {
+			Presence string `json:"presence"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		if err := h.service.SetPresence(userID, req.Presence); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		presence, err := h.service.GetPresence(userID, userID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, "Failed to get presence")
+			return
+		}
+		h.sendJSON(w, http.StatusOK, presence)
+
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// HandleCustomStatus handles getting, setting and clearing the current user's
+// status message
+func (h *StatusHandler) HandleCustomStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok || userID == "" {
+		h.sendError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		presence, err := h.service.GetPresence(userID, userID)
+		if err != nil {
+			h.sendError(w, http.StatusInternalServerError, "Failed to get status")
+			return
+		}
+		h.sendJSON(w, http.StatusOK, map[string]interface{}{"customStatus": presence.CustomStatus})
+
+	case http.MethodPut:
+		var req CustomStatus
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		status, err := h.service.SetCustomStatus(userID, &req)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, map[string]interface{}{"customStatus": status})
+
+	case http.MethodDelete:
+		if _, err := h.service.SetCustomStatus(userID, &CustomStatus{}); err != nil {
+			h.sendError(w, http.StatusInternalServerError, "Failed to clear status")
+			return
+		}
+		h.sendJSON(w, http.StatusOK, map[string]interface{}{"customStatus": nil})
+
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method not allowed: %s", r.Method))
+	}
+}
+
+// Helper method to send JSON responses
+func (h *StatusHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	httputil.SendJSON(w, status, data)
+}
+
+// Helper method to send error responses
+func (h *StatusHandler) sendError(w http.ResponseWriter, status int, message string) {
+	var isWarning bool = false
+	if status >= 500 {
+		isWarning = true
+	}
+	httputil.SendError(w, status, message, isWarning)
+}
@@ -2,6 +2,9 @@ package user
 
 import (
 	"database/sql"
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
 )
 
 // SQLiteStatusRepository implements StatusRepository for SQLite
@@ -42,14 +45,24 @@ func (r *SQLiteStatusRepository) SetUserOffline(userID string) error {
 	return err
 }
 
-// GetUserStatus gets a user's online status
-func (r *SQLiteStatusRepository) GetUserStatus(userID string) (bool, error) {
+// GetUserStatus gets a user's online status as seen by the viewer. Invisible
+// users appear offline to everyone but themselves, as do users whose privacy
+// settings hide their online status from the viewer.
+func (r *SQLiteStatusRepository) GetUserStatus(viewerID, userID string) (bool, error) {
 	query := `
-		SELECT is_online FROM user_status
-		WHERE user_id = ?
+		SELECT us.is_online AND (us.user_id = ? OR us.presence != ? AND CASE COALESCE(
+			(SELECT ps.who_can_see_online FROM privacy_settings ps WHERE ps.user_id = us.user_id), 'everyone')
+			WHEN 'everyone' THEN 1
+			WHEN 'followers' THEN EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = ? AND x.following_id = us.user_id)
+			WHEN 'mutuals' THEN EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = ? AND x.following_id = us.user_id)
+				AND EXISTS(SELECT 1 FROM followers x WHERE x.follower_id = us.user_id AND x.following_id = ?)
+			ELSE 0
+			END)
+		FROM user_status us
+		WHERE us.user_id = ?
 	`
 	var isOnline bool
-	err := r.db.QueryRow(query, userID).Scan(&isOnline)
+	err := r.db.QueryRow(query, viewerID, models.PresenceInvisible, viewerID, viewerID, viewerID, userID).Scan(&isOnline)
 	if err == sql.ErrNoRows {
 		return false, nil // Default to offline if no record
 	}
@@ -57,7 +70,8 @@ func (r *SQLiteStatusRepository) GetUserStatus(userID string) (bool, error) {
 }
 
 // GetFollowersForStatusUpdate gets the list of users who should be notified of status changes
-// This includes both followers and users that the user is following (bidirectional relationship)
+// This includes both followers and users that the user is following (bidirectional relationship),
+// except users who have muted this user's status
 func (r *SQLiteStatusRepository) GetFollowersForStatusUpdate(userID string) ([]string, error) {
 	query := `
 		SELECT DISTINCT user_id FROM (
@@ -69,8 +83,11 @@ func (r *SQLiteStatusRepository) GetFollowersForStatusUpdate(userID string) ([]s
 			SELECT following_id as user_id FROM followers
 			WHERE follower_id = ?
 		)
+		WHERE user_id NOT IN (
+			SELECT user_id FROM user_mutes WHERE muted_user_id = ? AND type = ?
+		)
 	`
-	rows, err := r.db.Query(query, userID, userID)
+	rows, err := r.db.Query(query, userID, userID, userID, models.MuteTypeStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -116,3 +133,134 @@ func (r *SQLiteStatusRepository) GetAllOnlineUsers() ([]string, error) {
 
 	return userIDs, nil
 }
+
+// GetStatus gets a user's full status row, or nil if they never connected
+func (r *SQLiteStatusRepository) GetStatus(userID string) (*models.UserStatus, error) {
+	query := `
+		SELECT user_id, is_online, presence, last_activity, updated_at,
+			status_text, status_emoji, status_expires_at
+		FROM user_status
+		WHERE user_id = ?
+	`
+
+	status := &models.UserStatus{}
+	var expiresAt sql.NullTime
+	err := r.db.QueryRow(query, userID).Scan(
+		&status.UserID,
+		&status.IsOnline,
+		&status.Presence,
+		&status.LastActivity,
+		&status.UpdatedAt,
+		&status.StatusText,
+		&status.StatusEmoji,
+		&expiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		status.StatusExpiresAt = expiresAt.Time
+	}
+
+	return status, nil
+}
+
+// SetPresence sets the presence mode the user picked
+func (r *SQLiteStatusRepository) SetPresence(userID, presence string) error {
+	query := `
+		INSERT INTO user_status (user_id, presence, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(user_id) DO UPDATE SET
+		presence = excluded.presence,
+		updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.Exec(query, userID, presence)
+	return err
+}
+
+// TouchActivity records that an online user was just active
+func (r *SQLiteStatusRepository) TouchActivity(userID string) error {
+	query := `
+		UPDATE user_status
+		SET last_activity = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND is_online = TRUE
+	`
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+// SetCustomStatus sets the user's status message. Empty text and emoji clear
+// it; a nil expiry keeps it until it's changed.
+func (r *SQLiteStatusRepository) SetCustomStatus(userID, text, emoji string, expiresAt *time.Time) error {
+	query := `
+		INSERT INTO user_status (user_id, status_text, status_emoji, status_expires_at, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(user_id) DO UPDATE SET
+		status_text = excluded.status_text,
+		status_emoji = excluded.status_emoji,
+		status_expires_at = excluded.status_expires_at,
+		updated_at = CURRENT_TIMESTAMP
+	`
+
+	var expires interface{}
+	if expiresAt != nil {
+		expires = expiresAt.UTC()
+	}
+
+	_, err := r.db.Exec(query, userID, text, emoji, expires)
+	return err
+}
+
+// ClearExpiredCustomStatuses clears the status messages that expired before
+// now and returns the users they belonged to
+func (r *SQLiteStatusRepository) ClearExpiredCustomStatuses(now time.Time) ([]string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT user_id FROM user_status
+		WHERE status_expires_at IS NOT NULL AND status_expires_at <= ?
+	`, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE user_status
+		SET status_text = '', status_emoji = '', status_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE status_expires_at IS NOT NULL AND status_expires_at <= ?
+	`, now.UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
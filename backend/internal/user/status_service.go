@@ -1,28 +1,80 @@
 package user
 
 import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Athooh/social-network/internal/privacy"
 	"github.com/Athooh/social-network/pkg/logger"
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
 	"github.com/Athooh/social-network/pkg/session"
 	"github.com/Athooh/social-network/pkg/user"
 	"github.com/Athooh/social-network/pkg/websocket"
 	"github.com/Athooh/social-network/pkg/websocket/events"
 )
 
-// StatusService handles user online/offline status
+// activityWriteInterval limits how often a user's activity is written
+const activityWriteInterval = time.Minute
+
+// Custom status limits, in characters
+const (
+	maxStatusTextLength  = 100
+	maxStatusEmojiLength = 8
+)
+
+// CustomStatus is a user's status message
+type CustomStatus struct {
+	Text      string     `json:"text"`
+	Emoji     string     `json:"emoji"`
+	ExpiresAt *time.Time `json:"expiresAt"` // nil when the status doesn't expire
+}
+
+// Presence is a user's presence as seen by another user
+type Presence struct {
+	UserID   string     `json:"userId"`
+	Presence string     `json:"presence"`
+	IsOnline bool       `json:"isOnline"`
+	LastSeen *time.Time `json:"lastSeen"` // nil when hidden from the viewer
+
+	CustomStatus *CustomStatus `json:"customStatus"` // nil when the user has none
+}
+
+// StatusService handles user online/offline status and presence. Presence
+// changes are debounced per user and only broadcast when the presence the
+// user's followers see actually changes.
 type StatusService struct {
 	statusRepo  user.StatusRepository
 	sessionRepo session.SessionStore
+	privacy     privacy.Service
 	hub         *websocket.Hub
 	log         *logger.Logger
+	idleAfter   time.Duration
+	debounce    time.Duration
+
+	mu        sync.Mutex
+	pending   map[string]*time.Timer // user ID -> scheduled presence broadcast
+	published map[string]string      // user ID -> last broadcast presence, absent when offline
+	lastTouch map[string]time.Time   // user ID -> last activity write
 }
 
-// NewStatusService creates a new user status service
-func NewStatusService(statusRepo user.StatusRepository, sessionRepo session.SessionStore, hub *websocket.Hub, log *logger.Logger) *StatusService {
+// NewStatusService creates a new user status service. Online users without
+// activity for idleAfter are shown as idle; presence broadcasts wait for
+// debounce without further changes.
+func NewStatusService(statusRepo user.StatusRepository, sessionRepo session.SessionStore, privacySvc privacy.Service, hub *websocket.Hub, log *logger.Logger, idleAfter, debounce time.Duration) *StatusService {
 	return &StatusService{
 		statusRepo:  statusRepo,
 		sessionRepo: sessionRepo,
+		privacy:     privacySvc,
 		hub:         hub,
 		log:         log,
+		idleAfter:   idleAfter,
+		debounce:    debounce,
+		pending:     make(map[string]*time.Timer),
+		published:   make(map[string]string),
+		lastTouch:   make(map[string]time.Time),
 	}
 }
 
@@ -34,20 +86,326 @@ func (s *StatusService) SetUserOnline(userID string) error {
 		return err
 	}
 
+	s.mu.Lock()
+	s.lastTouch[userID] = time.Now()
+	s.mu.Unlock()
+
+	s.schedulePresenceUpdate(userID)
+	return nil
+}
+
+// SetUserOffline marks a user as offline and notifies followers
+func (s *StatusService) SetUserOffline(userID string) error {
+	// Update database
+	if err := s.statusRepo.SetUserOffline(userID); err != nil {
+		s.log.Error("Failed to set user offline status: %v", err)
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.lastTouch, userID)
+	s.mu.Unlock()
+
+	s.schedulePresenceUpdate(userID)
+
+	s.log.Info("User %s is offline", userID)
+
+	return nil
+}
+
+// RecordActivity records client activity, bringing idle users back online.
+// Writes are throttled per user unless the user is currently shown as idle.
+func (s *StatusService) RecordActivity(userID string) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	lastTouch, touched := s.lastTouch[userID]
+	wasIdle := s.published[userID] == models.PresenceIdle
+	if touched && now.Sub(lastTouch) < activityWriteInterval && !wasIdle {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastTouch[userID] = now
+	s.mu.Unlock()
+
+	if err := s.statusRepo.TouchActivity(userID); err != nil {
+		s.log.Error("Failed to record activity for user %s: %v", userID, err)
+		return err
+	}
+
+	if wasIdle {
+		s.schedulePresenceUpdate(userID)
+	}
+
+	return nil
+}
+
+// SetPresence sets the presence mode the user picked: online, dnd or invisible
+func (s *StatusService) SetPresence(userID, presence string) error {
+	switch presence {
+	case models.PresenceOnline, models.PresenceDND, models.PresenceInvisible:
+	default:
+		return errors.New("presence must be online, dnd or invisible")
+	}
+
+	if err := s.statusRepo.SetPresence(userID, presence); err != nil {
+		s.log.Error("Failed to set presence for user %s: %v", userID, err)
+		return err
+	}
+
+	s.schedulePresenceUpdate(userID)
+	return nil
+}
+
+// GetPresence gets a user's presence as seen by the viewer. Users the owner's
+// privacy settings hide it from see them as offline with no last seen time.
+func (s *StatusService) GetPresence(viewerID, userID string) (*Presence, error) {
+	result := &Presence{UserID: userID, Presence: models.PresenceOffline}
+
+	allowed, err := s.privacy.Allows(userID, viewerID, privacy.SettingSeeOnline)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return result, nil
+	}
+
+	status, err := s.statusRepo.GetStatus(userID)
+	if err != nil {
+		s.log.Error("Failed to get status for user %s: %v", userID, err)
+		return nil, err
+	}
+	if status == nil {
+		return result, nil
+	}
+
+	result.CustomStatus = customStatusOf(status)
+
+	isSelf := viewerID == userID
+	result.Presence = s.effectivePresence(status, isSelf)
+	result.IsOnline = result.Presence != models.PresenceOffline
+
+	// Invisible users don't give away when they were last around
+	if isSelf || status.Presence != models.PresenceInvisible {
+		lastSeen := status.LastActivity
+		result.LastSeen = &lastSeen
+	}
+
+	return result, nil
+}
+
+// SetCustomStatus sets the user's status message and tells their followers.
+// Empty text and emoji clear the status.
+func (s *StatusService) SetCustomStatus(userID string, status *CustomStatus) (*CustomStatus, error) {
+	text := strings.TrimSpace(status.Text)
+	emoji := strings.TrimSpace(status.Emoji)
+
+	if utf8.RuneCountInString(text) > maxStatusTextLength {
+		return nil, errors.New("status text is too long")
+	}
+	if utf8.RuneCountInString(emoji) > maxStatusEmojiLength {
+		return nil, errors.New("status emoji is too long")
+	}
+
+	expiresAt := status.ExpiresAt
+	if text == "" && emoji == "" {
+		expiresAt = nil
+	} else if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("status expiry must be in the future")
+	}
+
+	if err := s.statusRepo.SetCustomStatus(userID, text, emoji, expiresAt); err != nil {
+		s.log.Error("Failed to set custom status for user %s: %v", userID, err)
+		return nil, err
+	}
+
+	var result *CustomStatus
+	if text != "" || emoji != "" {
+		result = &CustomStatus{Text: text, Emoji: emoji, ExpiresAt: expiresAt}
+	}
+
+	if err := s.broadcastCustomStatus(userID, result); err != nil {
+		s.log.Error("Failed to broadcast custom status for user %s: %v", userID, err)
+	}
+
+	return result, nil
+}
+
+// ClearExpiredCustomStatuses clears the status messages past their expiry and
+// tells the owners' followers
+func (s *StatusService) ClearExpiredCustomStatuses() error {
+	userIDs, err := s.statusRepo.ClearExpiredCustomStatuses(time.Now())
+	if err != nil {
+		s.log.Error("Failed to clear expired custom statuses: %v", err)
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if err := s.broadcastCustomStatus(userID, nil); err != nil {
+			s.log.Error("Failed to broadcast custom status for user %s: %v", userID, err)
+		}
+	}
+
+	if len(userIDs) > 0 {
+		s.log.Debug("Cleared %d expired custom statuses", len(userIDs))
+	}
+
+	return nil
+}
+
+// RunCustomStatusSweeper clears expired status messages immediately and then
+// on every interval. It blocks, so start it in its own goroutine.
+func (s *StatusService) RunCustomStatusSweeper(interval time.Duration) {
+	s.ClearExpiredCustomStatuses()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.ClearExpiredCustomStatuses()
+	}
+}
+
+// RunIdleSweep moves users without recent activity to idle on every
+// interval. It blocks, so start it in its own goroutine.
+func (s *StatusService) RunIdleSweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		onlineUsers, err := s.statusRepo.GetAllOnlineUsers()
+		if err != nil {
+			s.log.Error("Failed to get online users for idle sweep: %v", err)
+			continue
+		}
+
+		for _, userID := range onlineUsers {
+			s.publishPresence(userID)
+		}
+	}
+}
+
+// customStatusOf gets the status message from a status row, or nil if it has
+// none or it already expired
+func customStatusOf(status *models.UserStatus) *CustomStatus {
+	if status.StatusText == "" && status.StatusEmoji == "" {
+		return nil
+	}
+
+	result := &CustomStatus{Text: status.StatusText, Emoji: status.StatusEmoji}
+	if !status.StatusExpiresAt.IsZero() {
+		if !status.StatusExpiresAt.After(time.Now()) {
+			return nil
+		}
+		expiresAt := status.StatusExpiresAt
+		result.ExpiresAt = &expiresAt
+	}
+
+	return result
+}
+
+// effectivePresence derives the presence shown for a status. Invisible users
+// only see themselves as invisible; everyone else sees them offline.
+func (s *StatusService) effectivePresence(status *models.UserStatus, isSelf bool) string {
+	if status == nil || !status.IsOnline {
+		return models.PresenceOffline
+	}
+
+	switch status.Presence {
+	case models.PresenceInvisible:
+		if isSelf {
+			return models.PresenceInvisible
+		}
+		return models.PresenceOffline
+	case models.PresenceDND:
+		return models.PresenceDND
+	}
+
+	if time.Since(status.LastActivity) >= s.idleAfter {
+		return models.PresenceIdle
+	}
+	return models.PresenceOnline
+}
+
+// schedulePresenceUpdate broadcasts the user's presence once it has been
+// stable for the debounce delay, so a flapping connection sends one update
+func (s *StatusService) schedulePresenceUpdate(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer, ok := s.pending[userID]; ok {
+		timer.Reset(s.debounce)
+		return
+	}
+
+	s.pending[userID] = time.AfterFunc(s.debounce, func() {
+		s.publishPresence(userID)
+	})
+}
+
+// publishPresence broadcasts the user's current presence if it differs from
+// the last one broadcast
+func (s *StatusService) publishPresence(userID string) {
+	s.mu.Lock()
+	delete(s.pending, userID)
+	s.mu.Unlock()
+
+	status, err := s.statusRepo.GetStatus(userID)
+	if err != nil {
+		s.log.Error("Failed to get status for user %s: %v", userID, err)
+		return
+	}
+	presence := s.effectivePresence(status, false)
+
+	s.mu.Lock()
+	previous, ok := s.published[userID]
+	if !ok {
+		previous = models.PresenceOffline
+	}
+	if presence == previous {
+		s.mu.Unlock()
+		return
+	}
+	if presence == models.PresenceOffline {
+		delete(s.published, userID)
+	} else {
+		s.published[userID] = presence
+	}
+	s.mu.Unlock()
+
+	if err := s.broadcastPresence(userID, status, presence); err != nil {
+		s.log.Error("Failed to broadcast presence for user %s: %v", userID, err)
+	}
+}
+
+// broadcastPresence sends the user's presence to the followers and followed
+// users allowed to see it
+func (s *StatusService) broadcastPresence(userID string, status *models.UserStatus, presence string) error {
 	// Get users to notify (both followers and following)
 	userIDs, err := s.statusRepo.GetFollowersForStatusUpdate(userID)
 	if err != nil {
-		s.log.Error("Failed to get users for status update: %v", err)
 		return err
 	}
 
+	// Only tell the users allowed to see this user's online status
+	userIDs, err = s.privacy.FilterAllowed(userID, userIDs, privacy.SettingSeeOnline)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"userId":   userID,
+		"isOnline": presence != models.PresenceOffline,
+		"presence": presence,
+	}
+	if status != nil && status.Presence != models.PresenceInvisible {
+		payload["lastSeen"] = status.LastActivity
+	}
+
 	// Create status update event
 	event := events.Event{
-		Type: "user_status_update",
-		Payload: map[string]interface{}{
-			"userId":   userID,
-			"isOnline": true,
-		},
+		Type:    events.UserStatusUpdate,
+		Payload: payload,
 	}
 
 	// Notify users (both followers and following)
@@ -58,43 +416,33 @@ func (s *StatusService) SetUserOnline(userID string) error {
 	return nil
 }
 
-// SetUserOffline marks a user as offline and notifies followers
-func (s *StatusService) SetUserOffline(userID string) error {
-	// Update database
-	if err := s.statusRepo.SetUserOffline(userID); err != nil {
-		s.log.Error("Failed to set user offline status: %v", err)
-		return err
-	}
-
-	// Get users to notify (both followers and following)
+// broadcastCustomStatus sends the user's status message, nil when cleared, to
+// the user's other tabs and to their followers and followed users
+func (s *StatusService) broadcastCustomStatus(userID string, status *CustomStatus) error {
 	userIDs, err := s.statusRepo.GetFollowersForStatusUpdate(userID)
 	if err != nil {
-		s.log.Error("Failed to get users for status update: %v", err)
 		return err
 	}
 
-	// Create status update event
 	event := events.Event{
-		Type: "user_status_update",
+		Type: events.CustomStatusUpdate,
 		Payload: map[string]interface{}{
-			"userId":   userID,
-			"isOnline": false,
+			"userId":       userID,
+			"customStatus": status,
 		},
 	}
 
-	// Notify users (both followers and following)
+	s.hub.BroadcastToUser(userID, event)
 	for _, userID := range userIDs {
 		s.hub.BroadcastToUser(userID, event)
 	}
 
-	s.log.Info("User %s is offline", userID)
-
 	return nil
 }
 
-// GetUserStatus gets a user's online status
-func (s *StatusService) GetUserStatus(userID string) (bool, error) {
-	return s.statusRepo.GetUserStatus(userID)
+// GetUserStatus gets a user's online status as seen by the viewer
+func (s *StatusService) GetUserStatus(viewerID, userID string) (bool, error) {
+	return s.statusRepo.GetUserStatus(viewerID, userID)
 }
 
 // CleanupUserStatuses checks all online users and marks them offline if they don't have a valid session
@@ -154,6 +154,31 @@ func DiscoverModelStructs() []interface{} {
 		models.ChatContact{},
 		models.Notification{},
 		models.UserProfile{},
+		models.AudienceList{},
+		models.AudienceListMember{},
+		models.BookmarkCollection{},
+		models.Bookmark{},
+		models.Report{},
+		models.PostImpression{},
+		models.ProfileVisit{},
+		models.UserDailyStat{},
+		models.SuggestionDismissal{},
+		models.UserMute{},
+		models.PrivacySetting{},
+		models.UserHandle{},
+		models.UserHandleChange{},
+		models.ProfileSkill{},
+		models.ProfileFieldAudience{},
+		models.GroupRole{},
+		models.GroupRolePermission{},
+		models.GroupTag{},
+		models.GroupInviteLink{},
+		models.GroupInviteLinkUse{},
+		models.GroupBan{},
+		models.GroupJoinQuestion{},
+		models.GroupJoinAnswer{},
+		models.GroupChatRead{},
+		models.GroupNotificationSetting{},
 		// Add new models here
 	}
 }
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// PostImpression records a viewer seeing a post. The ID combines the post, the
+// viewer and the impression window, so repeat views in one window are ignored.
+type PostImpression struct {
+	ID       string    `db:"id,pk"` // "<postID>:<viewerID>:<windowStart>"
+	PostID   int64     `db:"post_id,notnull" index:"idx_post_impressions_post_id"`
+	AuthorID string    `db:"author_id,notnull" index:"idx_post_impressions_author_id"`
+	ViewerID string    `db:"viewer_id,notnull"`
+	ViewedAt time.Time `db:"viewed_at,default=CURRENT_TIMESTAMP" index:"idx_post_impressions_viewed_at"`
+}
+
+// ProfileVisit records a user visiting another user's profile, deduplicated
+// per visitor and window like PostImpression
+type ProfileVisit struct {
+	ID        string    `db:"id,pk"` // "<profileID>:<visitorID>:<windowStart>"
+	ProfileID string    `db:"profile_id,notnull" index:"idx_profile_visits_profile_id"`
+	VisitorID string    `db:"visitor_id,notnull"`
+	VisitedAt time.Time `db:"visited_at,default=CURRENT_TIMESTAMP" index:"idx_profile_visits_visited_at"`
+}
+
+// UserDailyStat is the daily analytics rollup for a user, maintained by the
+// analytics background job
+type UserDailyStat struct {
+	ID            string    `json:"-" db:"id,pk"` // "<userID>:<day>"
+	UserID        string    `json:"-" db:"user_id,notnull" index:"idx_user_daily_stats_user_id"`
+	Day           string    `json:"date" db:"day,notnull"` // YYYY-MM-DD in UTC
+	Impressions   int64     `json:"impressions" db:"impressions,default=0"`
+	Likes         int64     `json:"likes" db:"likes,default=0"`
+	Comments      int64     `json:"comments" db:"comments,default=0"`
+	NewFollowers  int64     `json:"newFollowers" db:"new_followers,default=0"`
+	ProfileVisits int64     `json:"profileVisits" db:"profile_visits,default=0"`
+	UpdatedAt     time.Time `json:"-" db:"updated_at,default=CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// BookmarkCollection represents a private, named collection of saved posts
+type BookmarkCollection struct {
+	ID        string    `json:"id" db:"id,pk"`
+	UserID    string    `json:"userId" db:"user_id,notnull" index:"idx_bookmark_collections_user_id"`
+	Name      string    `json:"name" db:"name,notnull"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at,default=CURRENT_TIMESTAMP"`
+
+	// Populated fields (not stored in DB)
+	ItemCount int `json:"itemCount" db:"-"`
+}
+
+// Bookmark represents a post or group post saved by a user. Posts and group
+// posts share one ID space, so PostID identifies either.
+type Bookmark struct {
+	ID           int64     `json:"id" db:"id,pk,autoincrement"`
+	UserID       string    `json:"userId" db:"user_id,notnull" index:"idx_bookmarks_user_id"`
+	PostID       int64     `json:"postId" db:"post_id,notnull" index:"idx_bookmarks_post_id"`
+	CollectionID string    `json:"collectionId" db:"collection_id,default=''"` // empty when not in a collection
+	CreatedAt    time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+	// "<userID>:<postID>", which keeps a post saved only once per user
+	UserPostKey string `json:"-" db:"user_post_key" index:"unique"`
+
+	// Populated fields (not stored in DB)
+	Post        *Post `json:"post,omitempty" db:"-"`
+	Unavailable bool  `json:"unavailable" db:"-"` // tombstone: the saver can no longer view the post
+}
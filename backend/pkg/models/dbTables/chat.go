@@ -11,6 +11,8 @@ type PrivateMessage struct {
 	CreatedAt  time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
 	ReadAt     time.Time `json:"readAt,omitempty" db:"read_at"`
 	IsRead     bool      `json:"isRead" db:"is_read,default=FALSE"`
+	IsHidden   bool      `json:"-" db:"is_hidden,default=FALSE"`   // hidden by moderation
+	AutoHidden bool      `json:"-" db:"auto_hidden,default=FALSE"` // hidden by reports rather than a moderator
 
 	// Populated fields (not stored in DB)
 	Sender   *UserBasic `json:"sender,omitempty" db:"-"`
@@ -31,4 +33,6 @@ type ChatContact struct {
 
 	// Populated fields (not stored in DB)
 	LastMessageSenderID string `json:"lastMessageSenderId,omitempty" db:"-"`
+	StatusText          string `json:"statusText" db:"-"`
+	StatusEmoji         string `json:"statusEmoji" db:"-"`
 }
@@ -22,6 +22,18 @@ type Follower struct {
 	FollowingID string    `db:"following_id,notnull" index:"idx_followers_following_id" references:"users(id) ON DELETE CASCADE"`
 	CreatedAt   time.Time `db:"created_at,default=CURRENT_TIMESTAMP"`
 
+	// Favorites tier, set by the follower
+	IsFavorite   bool `db:"is_favorite,default=FALSE"`    // boosted at the top of the follower's feed
+	NotifyOnPost bool `db:"notify_on_post,default=FALSE"` // the follower is notified of each new post
+
 	// Add unique constraint for follower_id and following_id
 	_ struct{} `db:"unique:follower_id,following_id"`
 }
+
+// SuggestionDismissal records a user hiding someone from their friend suggestions
+type SuggestionDismissal struct {
+	ID              string    `db:"id,pk"` // "<userID>:<dismissedUserID>"
+	UserID          string    `db:"user_id,notnull" index:"idx_suggestion_dismissals_user_id" references:"users(id) ON DELETE CASCADE"`
+	DismissedUserID string    `db:"dismissed_user_id,notnull" references:"users(id) ON DELETE CASCADE"`
+	CreatedAt       time.Time `db:"created_at,default=CURRENT_TIMESTAMP"`
+}
@@ -14,6 +14,15 @@ type Group struct {
 	BannerPath     sql.NullString `db:"banner_path"`
 	ProfilePicPath sql.NullString `db:"profile_pic_path"`
 	IsPublic       bool           `db:"is_public,default=TRUE"`
+	// Hide who wrote the comments of members removed from the group
+	AnonymizeRemovedComments bool `db:"anonymize_removed_comments,default=FALSE"`
+	// Hold posts by members who can't approve posts until someone reviews them
+	RequirePostApproval bool `db:"require_post_approval,default=FALSE"`
+	Category     string `db:"category,default=''" index:"idx_groups_category"`
+	// Let non-members find a private group by its name
+	ListedByName bool   `db:"listed_by_name,default=FALSE"`
+	// Set when the group is deleted; it's purged once the restore window passes
+	ArchivedAt     time.Time      `db:"archived_at" index:"idx_groups_archived_at"`
 	CreatedAt      time.Time      `db:"created_at,default=CURRENT_TIMESTAMP"`
 	UpdatedAt      time.Time      `db:"updated_at,default=CURRENT_TIMESTAMP"`
 
@@ -23,6 +32,10 @@ type Group struct {
 	IsMember      bool           `db:"-"`
 	MemberStatus  string         `db:"-"`
 	Members 	[]*GroupMember   `db:"-"`
+	Tags           []string      `db:"-"`
+	LastActivityAt time.Time     `db:"-"` // latest post, chat message or creation
+	FollowedMembers int          `db:"-"` // members the viewer follows
+	UnreadCount    int           `db:"-"` // chat messages the viewer hasn't read
 }
 
 // GroupMember represents a member of a group
@@ -40,8 +53,16 @@ type GroupMember struct {
 	// Non-DB fields
 	User      *UserBasic `db:"-"`
 	Inviter   *UserBasic `db:"-"`
+	JoinAnswers []*GroupJoinAnswer `db:"-"` // answers to the group's join questions
 }
 
+// Review states of a group post
+const (
+	GroupPostPending  = "pending"
+	GroupPostApproved = "approved"
+	GroupPostRejected = "rejected"
+)
+
 // GroupPost represents a post in a group
 type GroupPost struct {
 	ID            int64          `db:"id,pk"`
@@ -54,11 +75,18 @@ type GroupPost struct {
 	CommentsCount int64          `db:"comments_count,default=0"`
 	CreatedAt     time.Time      `db:"created_at,default=CURRENT_TIMESTAMP"`
 	UpdatedAt     time.Time      `db:"updated_at,default=CURRENT_TIMESTAMP"`
+	IsHidden      bool           `db:"is_hidden,default=FALSE"` // hidden by moderation
+	AutoHidden    bool           `db:"auto_hidden,default=FALSE"` // hidden by reports rather than a moderator
+	Status        string         `db:"status,default='approved'" index:"idx_group_posts_status"` // pending, approved, rejected
+	PinnedAt      time.Time      `db:"pinned_at"`      // zero unless pinned
+	PinExpiresAt  time.Time      `db:"pin_expires_at"` // zero for pins that don't expire
+	PinnedBy      string         `db:"pinned_by"`
 
 	// Non-DB fields
 	User  *PostUserData `db:"-"`
 	Group *GroupBasic   `db:"-"`
 	Isliked bool          `db:"-"`
+	IsPinned bool         `db:"-"`
 }
 
 // GroupEvent represents an event in a group
@@ -103,10 +131,19 @@ type GroupChatMessage struct {
 	UserID    string    `db:"user_id,notnull"`
 	Content   string    `db:"content,notnull"`
 	CreatedAt time.Time `db:"created_at,default=CURRENT_TIMESTAMP" index:"idx_group_chat_messages_created_at"`
-
+	ReplyToID int64     `db:"reply_to_id,default=0"` // the message this one replies to, or 0
+	EditedAt  time.Time `db:"edited_at"`             // zero unless edited
+	PinnedAt  time.Time `db:"pinned_at" index:"idx_group_chat_messages_pinned_at"` // zero unless pinned
+	PinnedBy  string    `db:"pinned_by"`
+	IsHidden  bool      `db:"is_hidden,default=FALSE"` // hidden by moderation
+	// Hidden by reports rather than a moderator
+	AutoHidden bool `db:"auto_hidden,default=FALSE"`
 
 	// Non-DB fields
 	User *UserBasic `db:"-"`
+	ReplyTo  *GroupChatMessage `db:"-"` // the message replied to, unless it was deleted
+	IsEdited bool              `db:"-"`
+	IsPinned bool              `db:"-"`
 }
 
 // UserBasic contains basic user information for display
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// GroupBan keeps a user out of a group until it expires or is lifted. The ID
+// is "<groupID>:<userID>".
+type GroupBan struct {
+	ID        string    `json:"-" db:"id,pk"`
+	GroupID   string    `json:"groupId" db:"group_id,notnull" index:"idx_group_bans_group_id" references:"groups(id) ON DELETE CASCADE"`
+	UserID    string    `json:"userId" db:"user_id,notnull" index:"idx_group_bans_user_id" references:"users(id) ON DELETE CASCADE"`
+	BannedBy  string    `json:"bannedBy" db:"banned_by,notnull"`
+	Reason    string    `json:"reason" db:"reason"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty" db:"expires_at"` // zero for bans that don't expire
+	CreatedAt time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+
+	// Non-DB fields
+	User *UserBasic `json:"user,omitempty" db:"-"`
+}
+
+// GroupJoinQuestion is a question people asking to join a group answer. The
+// ID is "<groupID>:<position>".
+type GroupJoinQuestion struct {
+	ID        string    `json:"-" db:"id,pk"`
+	GroupID   string    `json:"groupId" db:"group_id,notnull" index:"idx_group_join_questions_group_id" references:"groups(id) ON DELETE CASCADE"`
+	Position  int       `json:"position" db:"position,notnull"`
+	Question  string    `json:"question" db:"question,notnull"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+}
+
+// GroupJoinAnswer is a pending member's answer to one of the group's join
+// questions, kept with the question as it was asked. The ID is
+// "<memberID>:<position>".
+type GroupJoinAnswer struct {
+	ID       string `json:"-" db:"id,pk"`
+	MemberID string `json:"-" db:"member_id,notnull" index:"idx_group_join_answers_member_id" references:"group_members(id) ON DELETE CASCADE"`
+	Position int    `json:"position" db:"position,notnull"`
+	Question string `json:"question" db:"question,notnull"`
+	Answer   string `json:"answer" db:"answer,notnull"`
+}
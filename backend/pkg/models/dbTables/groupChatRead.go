@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// GroupChatRead is how far a member has read a group's chat. The ID is
+// "<groupID>:<userID>".
+type GroupChatRead struct {
+	ID                string    `json:"-" db:"id,pk"`
+	GroupID           string    `json:"groupId" db:"group_id,notnull" index:"idx_group_chat_reads_group_id" references:"groups(id) ON DELETE CASCADE"`
+	UserID            string    `json:"userId" db:"user_id,notnull" index:"idx_group_chat_reads_user_id" references:"users(id) ON DELETE CASCADE"`
+	LastReadMessageID int64     `json:"lastReadMessageId" db:"last_read_message_id,notnull,default=0"`
+	ReadAt            time.Time `json:"readAt" db:"read_at,default=CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// GroupInviteLink is a shareable link that lets whoever has its token join a
+// group, or ask to join it if the link requires approval
+type GroupInviteLink struct {
+	ID               string    `json:"id" db:"id,pk"`
+	GroupID          string    `json:"groupId" db:"group_id,notnull" index:"idx_group_invite_links_group_id" references:"groups(id) ON DELETE CASCADE"`
+	Token            string    `json:"token" db:"token,notnull" index:"idx_group_invite_links_token,unique"`
+	CreatedBy        string    `json:"createdBy" db:"created_by,notnull"`
+	ExpiresAt        time.Time `json:"expiresAt,omitempty" db:"expires_at"` // zero for links that don't expire
+	MaxUses          int       `json:"maxUses" db:"max_uses,default=0"`     // 0 for no limit
+	Uses             int       `json:"uses" db:"uses,default=0"`
+	RequiresApproval bool      `json:"requiresApproval" db:"requires_approval,default=FALSE"`
+	RevokedAt        time.Time `json:"revokedAt,omitempty" db:"revoked_at"` // zero unless revoked
+	CreatedAt        time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+}
+
+// Outcomes of redeeming a group invite link
+const (
+	InviteLinkJoined    = "joined"
+	InviteLinkRequested = "requested" // a join request waiting for approval
+)
+
+// GroupInviteLinkUse records someone redeeming a group invite link
+type GroupInviteLinkUse struct {
+	ID        int64     `json:"id" db:"id,pk,autoincrement"`
+	LinkID    string    `json:"linkId" db:"link_id,notnull" index:"idx_group_invite_link_uses_link_id" references:"group_invite_links(id) ON DELETE CASCADE"`
+	UserID    string    `json:"userId" db:"user_id,notnull" references:"users(id) ON DELETE CASCADE"`
+	Outcome   string    `json:"outcome" db:"outcome,notnull"` // joined, requested
+	CreatedAt time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+
+	// Non-DB fields
+	User *UserBasic `json:"user,omitempty" db:"-"`
+}
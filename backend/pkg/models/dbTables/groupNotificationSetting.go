@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// How much of a group's activity a member is notified about
+const (
+	GroupNotifyAll      = "all"      // everything
+	GroupNotifyMentions = "mentions" // mentions and events only
+	GroupNotifyNone     = "none"     // nothing
+)
+
+// Kinds of group notifications, which decide whether a member's notification
+// settings let one through. Settings only decide who is notified or alerted;
+// pushes that keep members' view of the group in sync go to every member.
+const (
+	GroupNotificationActivity = "activity" // comments on their posts, join requests and post reviews
+	GroupNotificationChat     = "chat"     // alerts for new chat messages
+	GroupNotificationEvent    = "event"    // events created or updated in the group
+	GroupNotificationMention  = "mention"  // the member was mentioned
+)
+
+// GroupNotificationSetting is how a member wants to be notified about a group.
+// Members without one are notified about everything. The ID is
+// "<groupID>:<userID>".
+type GroupNotificationSetting struct {
+	ID          string    `json:"-" db:"id,pk"`
+	GroupID     string    `json:"groupId" db:"group_id,notnull" index:"idx_group_notification_settings_group_id" references:"groups(id) ON DELETE CASCADE"`
+	UserID      string    `json:"userId" db:"user_id,notnull" index:"idx_group_notification_settings_user_id" references:"users(id) ON DELETE CASCADE"`
+	Level       string    `json:"level" db:"level,notnull,default='all'"`
+	ChatEnabled bool      `json:"chatEnabled" db:"chat_enabled,default=TRUE"`
+	MutedUntil  time.Time `json:"mutedUntil" db:"muted_until"` // zero unless muted
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at,default=CURRENT_TIMESTAMP"`
+}
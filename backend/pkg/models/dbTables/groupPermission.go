@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Group actions whose permission is set per role
+const (
+	GroupActionPost           = "post"
+	GroupActionComment        = "comment"
+	GroupActionChat           = "chat"
+	GroupActionCreateEvent    = "create_event"
+	GroupActionManageEvents   = "manage_events" // edit and delete other members' events
+	GroupActionInvite         = "invite"
+	GroupActionInviteLinks    = "invite_links" // create and manage shareable invite links
+	GroupActionApproveJoin    = "approve_join"
+	GroupActionApprovePosts   = "approve_posts"   // review posts held for approval
+	GroupActionPin            = "pin"             // pin posts and chat messages
+	GroupActionDeletePosts    = "delete_posts"    // delete other members' posts
+	GroupActionDeleteMessages = "delete_messages" // delete other members' chat messages
+	GroupActionRemoveMember   = "remove_member"
+	GroupActionEditSettings   = "edit_settings"
+)
+
+// Built-in group roles. Groups can add their own named roles, which start out
+// with the permissions of GroupRoleMember.
+const (
+	GroupRoleAdmin     = "admin"
+	GroupRoleModerator = "moderator"
+	GroupRoleMember    = "member"
+)
+
+// GroupRole is a custom role a group's creator added. The ID is
+// "<groupID>:<lowercase name>", which keeps role names unique within a group
+// regardless of case.
+type GroupRole struct {
+	ID        string    `json:"-" db:"id,pk"`
+	GroupID   string    `json:"groupId" db:"group_id,notnull" index:"idx_group_roles_group_id" references:"groups(id) ON DELETE CASCADE"`
+	Name      string    `json:"name" db:"name,notnull"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+}
+
+// GroupRolePermission overrides whether a role may perform an action in a
+// group. The ID is "<groupID>:<lowercase role>:<action>". Actions without a
+// row use the role's defaults.
+type GroupRolePermission struct {
+	ID        string    `json:"-" db:"id,pk"`
+	GroupID   string    `json:"groupId" db:"group_id,notnull" index:"idx_group_role_permissions_group_id" references:"groups(id) ON DELETE CASCADE"`
+	Role      string    `json:"role" db:"role,notnull"`
+	Action    string    `json:"action" db:"action,notnull"`
+	Allowed   bool      `json:"allowed" db:"allowed,default=FALSE"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at,default=CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// GroupTag is a free-form tag a group is labeled with. The ID is
+// "<groupID>:<tag>"; tags are stored in lowercase.
+type GroupTag struct {
+	ID        string    `json:"-" db:"id,pk"`
+	GroupID   string    `json:"groupId" db:"group_id,notnull" index:"idx_group_tags_group_id" references:"groups(id) ON DELETE CASCADE"`
+	Tag       string    `json:"tag" db:"tag,notnull" index:"idx_group_tags_tag"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserHandle is a handle a user holds or used to hold. The ID is the handle in
+// lowercase, which keeps handles unique regardless of case. Released handles
+// keep redirecting to their old owner for a grace period.
+type UserHandle struct {
+	ID         string    `json:"-" db:"id,pk"`
+	UserID     string    `json:"userId" db:"user_id,notnull" index:"idx_user_handles_user_id" references:"users(id) ON DELETE CASCADE"`
+	Handle     string    `json:"handle" db:"handle,notnull"` // as the user typed it
+	IsCurrent  bool      `json:"-" db:"is_current,default=TRUE"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+	ReleasedAt time.Time `json:"-" db:"released_at"` // null while current
+}
+
+// UserHandleChange records a user switching to a different handle. Changes are
+// counted against the change limit and never deleted, even when the user goes
+// back to a handle they held before.
+type UserHandleChange struct {
+	ID        int64     `json:"-" db:"id,pk,autoincrement"`
+	UserID    string    `json:"userId" db:"user_id,notnull" index:"idx_user_handle_changes_user_id" references:"users(id) ON DELETE CASCADE"`
+	ChangedAt time.Time `json:"changedAt" db:"changed_at,default=CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Mute types. Each one is a separate relationship a user can have with
+// someone they keep following.
+const (
+	MuteTypePosts         = "posts"         // hide their posts from the feed
+	MuteTypeStatus        = "status"        // hide their status updates
+	MuteTypeNotifications = "notifications" // silence notifications they trigger
+	MuteTypeRestrict      = "restrict"      // their comments on your posts are only visible to them and you
+)
+
+// UserMute records a user muting or restricting another user. Mutes are
+// private to the user who created them.
+type UserMute struct {
+	ID          string    `json:"-" db:"id,pk"` // "<userID>:<mutedUserID>:<type>"
+	UserID      string    `json:"-" db:"user_id,notnull" index:"idx_user_mutes_user_id" references:"users(id) ON DELETE CASCADE"`
+	MutedUserID string    `json:"userId" db:"muted_user_id,notnull" index:"idx_user_mutes_muted_user_id" references:"users(id) ON DELETE CASCADE"`
+	Type        string    `json:"type" db:"type,notnull"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+}
@@ -14,17 +14,23 @@ const (
 
 // Post represents a user post in the database
 type Post struct {
-	ID            int64          `db:"id,pk"`
-	UserID        string         `db:"user_id,notnull" index:"idx_post_user_id"`
-	Content       string         `db:"content,notnull"`
-	ImagePath     sql.NullString `db:"image_path"`
-	VideoPath     sql.NullString `db:"video_path"`
-	Privacy       string         `db:"privacy,notnull"`
-	LikesCount    int64          `db:"likes_count,default=0"`
-	CommentsCount int64          `db:"comments_count,default=0"`
-	CreatedAt     time.Time      `db:"created_at,default=CURRENT_TIMESTAMP"`
-	UpdatedAt     time.Time      `db:"updated_at,notnull"`
-	UserData      *PostUserData  `db:"-"`
+	ID             int64          `db:"id,pk"`
+	UserID         string         `db:"user_id,notnull" index:"idx_post_user_id"`
+	Content        string         `db:"content,notnull"`
+	ImagePath      sql.NullString `db:"image_path"`
+	VideoPath      sql.NullString `db:"video_path"`
+	Privacy        string         `db:"privacy,notnull"`
+	LikesCount     int64          `db:"likes_count,default=0"`
+	CommentsCount  int64          `db:"comments_count,default=0"`
+	ViewsCount     int64          `db:"views_count,default=0"` // deduplicated impressions
+	CreatedAt      time.Time      `db:"created_at,default=CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time      `db:"updated_at,notnull"`
+	AudienceListID int64          `db:"audience_list_id,default=0"` // 0 when the post has no audience list
+	IsHidden       bool           `db:"is_hidden,default=FALSE"`    // hidden by moderation
+	AutoHidden     bool           `db:"auto_hidden,default=FALSE"`  // hidden by reports rather than a moderator
+	UserData       *PostUserData  `db:"-"`
+	Boosted        bool           `db:"-"` // pinned to the top of the feed as a favorite's post
+	GroupID        string         `db:"-"` // set when GetPostByID finds a group post
 }
 
 // PostViewer represents which users can view a private post
@@ -34,16 +40,42 @@ type PostViewer struct {
 	UserID string `db:"user_id,notnull" index:"idx_post_viewer_user_id"`
 }
 
+// AudienceList represents a named list of followers (e.g. "Close friends")
+// that a user can share private posts with
+type AudienceList struct {
+	ID             int64     `db:"id,pk,autoincrement" json:"id"`
+	UserID         string    `db:"user_id,notnull" index:"idx_audience_list_user_id" json:"userId"`
+	Name           string    `db:"name,notnull" json:"name"`
+	IsCloseFriends bool      `db:"is_close_friends,default=0" json:"isCloseFriends"`
+	CreatedAt      time.Time `db:"created_at,default=CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt      time.Time `db:"updated_at,default=CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	// Non-DB fields
+	MemberCount int `db:"-" json:"memberCount"`
+}
+
+// AudienceListMember represents a follower that belongs to an audience list
+type AudienceListMember struct {
+	ID        int64     `db:"id,pk,autoincrement"`
+	ListID    int64     `db:"list_id,notnull" index:"idx_audience_list_member_list_id"`
+	UserID    string    `db:"user_id,notnull" index:"idx_audience_list_member_user_id"`
+	CreatedAt time.Time `db:"created_at,default=CURRENT_TIMESTAMP"`
+}
+
 // Comment represents a comment on a post
 type Comment struct {
-	ID        int64          `db:"id,pk,autoincrement"`
-	PostID    int64          `db:"post_id,notnull" index:"idx_comment_post_id"`
-	UserID    string         `db:"user_id,notnull" index:"idx_comment_user_id"`
-	Content   string         `db:"content,notnull"`
-	ImagePath sql.NullString `db:"image_path"`
-	CreatedAt time.Time      `db:"created_at,default=CURRENT_TIMESTAMP"`
-	UpdatedAt time.Time      `db:"updated_at,notnull"`
-	UserData  *PostUserData  `db:"-"`
+	ID         int64          `db:"id,pk,autoincrement"`
+	PostID     int64          `db:"post_id,notnull" index:"idx_comment_post_id"`
+	UserID     string         `db:"user_id,notnull" index:"idx_comment_user_id"`
+	Content    string         `db:"content,notnull"`
+	ImagePath  sql.NullString `db:"image_path"`
+	CreatedAt  time.Time      `db:"created_at,default=CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `db:"updated_at,notnull"`
+	IsHidden   bool           `db:"is_hidden,default=FALSE"`   // hidden by moderation
+	AutoHidden bool           `db:"auto_hidden,default=FALSE"` // hidden by reports rather than a moderator
+	// The author was removed from the post's group, which hides who they are
+	IsAnonymized bool          `db:"is_anonymized,default=FALSE"`
+	UserData     *PostUserData `db:"-"`
 }
 
 // PostLike represents a like on a post
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Privacy audiences, from widest to narrowest. "followers" are the people who
+// follow the user; "mutuals" also have to be followed back.
+const (
+	AudienceEveryone  = "everyone"
+	AudienceFollowers = "followers"
+	AudienceMutuals   = "mutuals"
+	AudienceNobody    = "nobody"
+)
+
+// PrivacySetting holds who may interact with a user in each way. Users without
+// a row use the defaults, which allow everyone.
+type PrivacySetting struct {
+	UserID             string    `json:"-" db:"user_id,pk" references:"users(id) ON DELETE CASCADE"`
+	WhoCanMessage      string    `json:"whoCanMessage" db:"who_can_message,default='everyone'"`
+	WhoCanMention      string    `json:"whoCanMention" db:"who_can_mention,default='everyone'"`
+	WhoCanInvite       string    `json:"whoCanInvite" db:"who_can_invite,default='everyone'"` // to groups and events
+	WhoCanSeeFollowers string    `json:"whoCanSeeFollowers" db:"who_can_see_followers,default='everyone'"`
+	WhoCanFindMe       string    `json:"whoCanFindMe" db:"who_can_find_me,default='everyone'"` // suggestions and search
+	WhoCanSeeOnline    string    `json:"whoCanSeeOnline" db:"who_can_see_online,default='everyone'"`
+	UpdatedAt          time.Time `json:"updatedAt" db:"updated_at,default=CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Kinds of profile skill lists
+const (
+	SkillKindTech     = "tech"
+	SkillKindSoft     = "soft"
+	SkillKindInterest = "interest"
+)
+
+// ProfileSkill is one entry of a user's tech skills, soft skills or interests.
+// The ID is "<userID>:<kind>:<lowercase name>", which keeps each list free of
+// duplicates regardless of case.
+type ProfileSkill struct {
+	ID        string    `json:"-" db:"id,pk"`
+	UserID    string    `json:"-" db:"user_id,notnull" index:"idx_profile_skills_user_id" references:"users(id) ON DELETE CASCADE"`
+	Kind      string    `json:"kind" db:"kind,notnull"`
+	Name      string    `json:"name" db:"name,notnull"`
+	Position  int       `json:"position" db:"position,default=0"` // order within the list
+	CreatedAt time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+}
+
+// ProfileFieldAudience holds who can see one field of a user's profile. The ID
+// is "<userID>:<field>". Fields without a row are shown to everyone who can
+// see the profile.
+type ProfileFieldAudience struct {
+	ID        string    `json:"-" db:"id,pk"`
+	UserID    string    `json:"-" db:"user_id,notnull" index:"idx_profile_field_audiences_user_id" references:"users(id) ON DELETE CASCADE"`
+	Field     string    `json:"field" db:"field,notnull"`
+	Audience  string    `json:"audience" db:"audience,notnull"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at,default=CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Report target types
+const (
+	ReportTargetPost         = "post"
+	ReportTargetComment      = "comment"
+	ReportTargetGroupPost    = "group_post"
+	ReportTargetMessage      = "message"
+	ReportTargetGroupMessage = "group_message"
+	ReportTargetProfile      = "profile"
+)
+
+// Report reason categories
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonHateSpeech     = "hate_speech"
+	ReportReasonViolence       = "violence"
+	ReportReasonNudity         = "nudity"
+	ReportReasonMisinformation = "misinformation"
+	ReportReasonOther          = "other"
+	ReportReasonAutoFlagged    = "auto_flagged" // raised by the content filters, not by a user
+)
+
+// SystemReporterID is the reporter of reports raised automatically
+const SystemReporterID = "system"
+
+// Report statuses
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusDismissed = "dismissed"
+	ReportStatusResolved  = "resolved"
+)
+
+// Moderation actions
+const (
+	ModerationActionDismiss = "dismiss"
+	ModerationActionHide    = "hide"
+	ModerationActionDelete  = "delete"
+	ModerationActionWarn    = "warn"
+)
+
+// Report represents a user's report of abusive content or a profile
+type Report struct {
+	ID             string    `json:"id" db:"id,pk"`
+	ReporterID     string    `json:"reporterId" db:"reporter_id,notnull" index:"idx_reports_reporter_id"`
+	TargetType     string    `json:"targetType" db:"target_type,notnull"` // post, comment, group_post, message, profile
+	TargetID       string    `json:"targetId" db:"target_id,notnull" index:"idx_reports_target_id"`
+	TargetAuthorID string    `json:"targetAuthorId" db:"target_author_id"`
+	GroupID        string    `json:"groupId" db:"group_id" index:"idx_reports_group_id"` // set for content inside a group
+	Reason         string    `json:"reason" db:"reason,notnull"`
+	Details        string    `json:"details" db:"details"`
+	Status         string    `json:"status" db:"status,notnull,default='open'" index:"idx_reports_status"`
+	Action         string    `json:"action,omitempty" db:"action"`
+	ResolvedBy     string    `json:"resolvedBy,omitempty" db:"resolved_by"`
+	ResolvedAt     time.Time `json:"resolvedAt,omitempty" db:"resolved_at"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at,default=CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at,default=CURRENT_TIMESTAMP"`
+
+	// Populated fields (not stored in DB)
+	Reporter       *UserBasic `json:"reporter,omitempty" db:"-"`
+	TargetAuthor   *UserBasic `json:"targetAuthor,omitempty" db:"-"`
+	ReportCount    int        `json:"reportCount" db:"-"`
+	ContentPreview string     `json:"contentPreview,omitempty" db:"-"`
+	IsHidden       bool       `json:"isHidden" db:"-"`
+}
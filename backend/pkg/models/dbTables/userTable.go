@@ -14,6 +14,7 @@ type User struct {
 	Nickname    string    `db:"nickname"`
 	AboutMe     string    `db:"about_me"`
 	IsPublic    bool      `db:"is_public,default=TRUE"`
+	IsModerator bool      `db:"is_moderator,default=FALSE"` // site-wide moderator, granted directly in the database
 	CreatedAt   time.Time `db:"created_at,default=CURRENT_TIMESTAMP"`
 	UpdatedAt   time.Time `db:"updated_at,default=CURRENT_TIMESTAMP"`
 }
@@ -29,12 +30,28 @@ type UserStat struct {
 	UpdatedAt      time.Time `db:"updated_at,default=CURRENT_TIMESTAMP"`
 }
 
+// Presence states. Users pick online, dnd or invisible as their presence mode;
+// idle and offline are derived from their connections and activity.
+const (
+	PresenceOnline    = "online"
+	PresenceIdle      = "idle"
+	PresenceDND       = "dnd"
+	PresenceInvisible = "invisible"
+	PresenceOffline   = "offline"
+)
+
 // UserStatus represents a user's online status
 type UserStatus struct {
 	UserID       string    `db:"user_id,pk" index:"unique" references:"users(id) ON DELETE CASCADE"`
 	IsOnline     bool      `db:"is_online,default=FALSE"`
+	Presence     string    `db:"presence,default='online'"` // the presence mode the user picked
 	LastActivity time.Time `db:"last_activity,default=CURRENT_TIMESTAMP"`
 	UpdatedAt    time.Time `db:"updated_at,default=CURRENT_TIMESTAMP"`
+
+	// Custom status message, cleared by a sweeper once it expires
+	StatusText      string    `db:"status_text,default=''"`
+	StatusEmoji     string    `db:"status_emoji,default=''"`
+	StatusExpiresAt time.Time `db:"status_expires_at"` // null when the status doesn't expire
 }
 
 // UserProfile represents the extended profile information for a user
@@ -52,9 +69,9 @@ type UserProfile struct {
 	Phone        string    `db:"phone"`
 	Website      string    `db:"website"`
 	Location     string    `db:"location"`
-	TechSkills   string    `db:"tech_skills"` // Comma-separated list
-	SoftSkills   string    `db:"soft_skills"` // Comma-separated list
-	Interests    string    `db:"interests"`   // Comma-separated list
+	TechSkills   string    `db:"tech_skills"` // Legacy comma-separated list, moved to profile_skills
+	SoftSkills   string    `db:"soft_skills"` // Legacy comma-separated list, moved to profile_skills
+	Interests    string    `db:"interests"`   // Legacy comma-separated list, moved to profile_skills
 	IsPrivate    bool      `db:"is_private,default=FALSE"`
 	CreatedAt    time.Time `db:"created_at,default=CURRENT_TIMESTAMP"`
 	UpdatedAt    time.Time `db:"updated_at,default=CURRENT_TIMESTAMP"`
@@ -1,6 +1,10 @@
 package user
 
-import "time"
+import (
+	"time"
+
+	models "github.com/Athooh/social-network/pkg/models/dbTables"
+)
 
 // Repository defines the user repository interface
 type Repository interface {
@@ -14,9 +18,14 @@ type Repository interface {
 type StatusRepository interface {
 	SetUserOnline(userID string) error
 	SetUserOffline(userID string) error
-	GetUserStatus(userID string) (bool, error)
+	GetUserStatus(viewerID, userID string) (bool, error)
 	GetFollowersForStatusUpdate(userID string) ([]string, error)
 	GetAllOnlineUsers() ([]string, error)
+	GetStatus(userID string) (*models.UserStatus, error)
+	SetPresence(userID, presence string) error
+	TouchActivity(userID string) error
+	SetCustomStatus(userID, text, emoji string, expiresAt *time.Time) error
+	ClearExpiredCustomStatuses(now time.Time) ([]string, error)
 }
 
 // User represents a user in the system
@@ -88,7 +88,9 @@ func (r *SQLiteRepository) GetByID(id string) (*User, error) {
 			-- Profile fields
 			p.username, p.full_name, p.bio, p.work, p.education, 
 			p.email AS contact_email, p.phone, p.website, p.location,
-			p.tech_skills, p.soft_skills, p.interests,
+			(SELECT GROUP_CONCAT(name) FROM profile_skills WHERE user_id = u.id AND kind = 'tech'),
+			(SELECT GROUP_CONCAT(name) FROM profile_skills WHERE user_id = u.id AND kind = 'soft'),
+			(SELECT GROUP_CONCAT(name) FROM profile_skills WHERE user_id = u.id AND kind = 'interest'),
 			p.banner_image, p.profile_image, p.is_private
 		FROM users u
 		LEFT JOIN user_stats us ON u.id = us.user_id
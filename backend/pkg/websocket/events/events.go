@@ -13,6 +13,7 @@ const (
 	FollowRequestAccepted EventType = "follow_request_accepted"
 	CommentCountUpdate    EventType = "comment_count_update"
 	UserStatusUpdate      EventType = "user_status_update"
+	CustomStatusUpdate    EventType = "user_custom_status_update"
 	GroupEventCreated     EventType = "group_event_created"
 	GroupEventUpdated     EventType = "group_event_updated"
 	GroupEventDeleted     EventType = "group_event_deleted"
@@ -24,7 +25,11 @@ const (
 	UserTyping     EventType = "user_typing"
 
 	// group events
-	GroupMessage EventType = "group_message"
+	GroupMessage        EventType = "group_message"
+	GroupMessageEdited  EventType = "group_message_edited"
+	GroupMessageDeleted EventType = "group_message_deleted"
+	GroupMessagePinned  EventType = "group_message_pinned"
+	GroupMessagesRead   EventType = "group_messages_read"
 
 	// header notifications
 	HeaderNotificationUpdate EventType = "notification_Update"
@@ -4,4 +4,5 @@ package websocket
 type StatusUpdater interface {
 	SetUserOnline(userID string) error
 	SetUserOffline(userID string) error
+	RecordActivity(userID string) error
 }
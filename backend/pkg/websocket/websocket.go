@@ -277,6 +277,11 @@ func (c *Client) ReadPump() {
 			continue
 		}
 
+		if msg.Type == "activity" {
+			c.Hub.RecordActivity(c.UserID)
+			continue
+		}
+
 		if msg.Type == "user_away" {
 
 			// Mark this client as inactive
@@ -350,6 +355,8 @@ func (c *Client) ReadPump() {
 				go func(userID string) {
 					c.Hub.SetUserOnline(userID)
 				}(userID)
+			} else {
+				c.Hub.RecordActivity(c.UserID)
 			}
 
 			continue
@@ -518,6 +525,20 @@ func (h *Hub) SetUserOffline(userID string) {
 	}
 }
 
+// RecordActivity tells the status service the user is active, so they aren't
+// shown as idle
+func (h *Hub) RecordActivity(userID string) {
+	if h.statusUpdater == nil {
+		return
+	}
+
+	go func() {
+		if err := h.statusUpdater.RecordActivity(userID); err != nil {
+			h.log.Error("Failed to record user activity in status service: %v", err)
+		}
+	}()
+}
+
 // BroadcastUserStatus sends a user's online status to specified recipients
 func (h *Hub) BroadcastUserStatus(userID string, isOnline bool, recipientIDs []string) {
 	for _, recipientID := range recipientIDs {
@@ -3,11 +3,12 @@
 import { useState, useEffect } from "react";
 import styles from '@/styles/Posts.module.css';
 import { usePostService } from '@/services/postService';
+import { useGroupService } from '@/services/groupService';
 import { showToast } from '@/components/ui/ToastContainer';
 import { useAuth } from '@/context/authcontext';
 import { formatRelativeTime } from '@/utils/dateUtils';
 import { BASE_URL } from '@/utils/constants';
-import { useWebSocket } from '@/services/websocketService';
+import { useWebSocket, EVENT_TYPES } from '@/services/websocketService';
 import ConfirmationModal from '@/components/ui/ConfirmationModal';
 import Image from 'next/image';
 import { useRouter } from 'next/navigation';
@@ -15,14 +16,13 @@ import { useRouter } from 'next/navigation';
 
 
 export default function GroupPost({ currentUser, post, onPostUpdated, isDetailView = false }) {
+  const { deletePost } = usePostService();
   const {
-    likePost,
-    addComment,
-    getPostComments,
-    deletePost,
-    deleteComment,
-    updatePostLikes,
-  } = usePostService();
+    likeGroupPost,
+    addGroupPostComment,
+    getGroupPostComments,
+    deleteGroupPostComment,
+  } = useGroupService();
   const { subscribe } = useWebSocket();
   const [isLiked, setIsLiked] = useState(post.Isliked);
   const [showComments, setShowComments] = useState(isDetailView);
@@ -39,6 +39,7 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
   });
   const [comments, setComments] = useState([]);
   const [likesCount, setLikesCount] = useState(post.LikesCount || 0);
+  const [commentsCount, setCommentsCount] = useState(post.CommentsCount || 0);
   const router = useRouter();
   
   let userdata = null;
@@ -72,6 +73,29 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
   };
   
 
+  // Keep the like and comment counts live as other members react
+  useEffect(() => {
+    if (!post.ID) return;
+
+    const unsubscribeLikes = subscribe(EVENT_TYPES.POST_LIKED, (payload) => {
+      if (Number(payload.postId) !== Number(post.ID)) return;
+      setLikesCount(payload.likesCount);
+      if (payload.userId === currentUser?.id) {
+        setIsLiked(payload.isLiked);
+      }
+    });
+    const unsubscribeComments = subscribe(EVENT_TYPES.COMMENT_COUNT_UPDATE, (payload) => {
+      if (Number(payload.postId) === Number(post.ID)) {
+        setCommentsCount(payload.count);
+      }
+    });
+
+    return () => {
+      if (typeof unsubscribeLikes === "function") unsubscribeLikes();
+      if (typeof unsubscribeComments === "function") unsubscribeComments();
+    };
+  }, [post.ID, subscribe, currentUser?.id]);
+
   useEffect(() => {
     if (showComments || isDetailView) {
       fetchComments();
@@ -82,7 +106,7 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
     if (loadingComments) return;
     setLoadingComments(true);
     try {
-      const commentsData = await getPostComments(formattedPost.id);
+      const commentsData = await getGroupPostComments(formattedPost.id);
       if (commentsData) {
         setComments(commentsData.map(comment => ({
           ...comment,
@@ -121,13 +145,13 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
     showConfirmation({
       title: "Delete Comment",
       message: "Are you sure you want to delete this comment?",
-      onConfirm: () => confirmDeleteComment(comment.id, postid),
+      onConfirm: () => confirmDeleteComment(comment.id),
     });
   };
 
-  const confirmDeleteComment = async (commentId,postid) => {
+  const confirmDeleteComment = async (commentId) => {
     try {
-      await deleteComment(commentId,postid);
+      await deleteGroupPostComment(commentId);
       setComments(comments.filter((comment) => comment.id !== commentId));
       if (onPostUpdated) onPostUpdated();
     } catch (error) {
@@ -150,7 +174,7 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
 
   const handleLike = async () => {
     try {
-      const response = await likePost(formattedPost.id);
+      const response = await likeGroupPost(formattedPost.id);
       setIsLiked(response.isLiked);
       setLikesCount(response.likesCount);
       if (onPostUpdated) onPostUpdated();
@@ -168,7 +192,7 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
     }
 
     try {
-      const newComment = await addComment(formattedPost.id, commentText, commentImage);
+      const newComment = await addGroupPostComment(formattedPost.id, commentText, commentImage);
       setComments(prev => [...prev, {
         ...newComment,
         authorName: currentUser ? `${currentUser.firstName} ${currentUser.lastName}` : "You",
@@ -237,6 +261,12 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
               <span>{formattedPost.timestamp}</span>
               <span className={styles.dot}>•</span>
               <span>{post.groupName}</span>
+              {post.IsPinned && (
+                <>
+                  <span className={styles.dot}>•</span>
+                  <span><i className="fas fa-thumbtack"></i> Pinned</span>
+                </>
+              )}
             </div>
           </div>
         </div>
@@ -280,10 +310,10 @@ export default function GroupPost({ currentUser, post, onPostUpdated, isDetailVi
             <i className="fas fa-thumbs-up" style={{ color: "#2078f4" }}></i>
             <i className="fas fa-heart" style={{ color: "#f33e58" }}></i>
           </span>
-          <span>{formattedPost.likes} likes</span>
+          <span>{likesCount} likes</span>
         </div>
         <div className={styles.engagement}>
-          <span>{formattedPost.commentCount} comments</span>
+          <span>{commentsCount} comments</span>
         </div>
       </div>
 
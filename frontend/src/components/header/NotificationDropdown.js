@@ -191,6 +191,21 @@ export default function NotificationDropdown() {
             </span>
           </div>
         );
+      case "favoritePost":
+        return (
+          <div className={styles.notification}>
+            <div className={styles.avatarContainer}>
+              <img
+                src={notification.avatar}
+                alt={notification.sender}
+                className={styles.avatar}
+              />
+            </div>
+            <span className={styles.text}>
+              <strong>{notification.sender}</strong> shared a new post
+            </span>
+          </div>
+        );
       case "friendRequest":
         return (
           <div className={styles.notification}>
@@ -51,12 +51,6 @@ const EditProfileModal = ({
     "Cybersecurity",
   ];
 
-  // Helper function to safely split comma-separated strings
-  const safeSplit = (str) => {
-    if (!str) return [];
-    return str.split(",").filter((item) => item.trim() !== "");
-  };
-
   const [formData, setFormData] = useState({
     bannerImage: null,
     profileImage: null,
@@ -75,6 +69,8 @@ const EditProfileModal = ({
     isPrivate: false,
   });
 
+  // Values the form was opened with, so only changed fields are sent
+  const [initialData, setInitialData] = useState(null);
   const [isSubmitting, setIsSubmitting] = useState(false);
   const [error, setError] = useState(null);
   const [bannerPreview, setBannerPreview] = useState("");
@@ -83,7 +79,7 @@ const EditProfileModal = ({
   // Initialize form data when profileData changes
   useEffect(() => {
     if (profileData) {
-      setFormData({
+      const initial = {
         bannerImage: null, // File objects will be set on change
         profileImage: null,
         username: profileData.username || "",
@@ -91,15 +87,17 @@ const EditProfileModal = ({
         bio: profileData.bio || "",
         work: profileData.work || "",
         education: profileData.education || "",
-        email: profileData.email || "",
+        email: profileData.contactEmail || "",
         phone: profileData.phone || "",
         website: profileData.website || "",
         location: profileData.location || "",
-        techSkills: safeSplit(profileData.techSkills),
-        softSkills: safeSplit(profileData.softSkills),
-        interests: safeSplit(profileData.interests),
+        techSkills: profileData.techSkills || [],
+        softSkills: profileData.softSkills || [],
+        interests: profileData.interests || [],
         isPrivate: profileData.isPrivate || false,
-      });
+      };
+      setFormData(initial);
+      setInitialData(initial);
 
       setBannerPreview(profileData.bannerUrl || "");
       setProfilePreview(profileData.profileUrl || "");
@@ -176,22 +174,32 @@ const EditProfileModal = ({
     try {
       const formDataToSend = new FormData();
 
-      // Add text fields
-      formDataToSend.append("username", formData.username);
-      formDataToSend.append("fullName", formData.fullName);
-      formDataToSend.append("bio", formData.bio);
-      formDataToSend.append("work", formData.work);
-      formDataToSend.append("education", formData.education);
-      formDataToSend.append("email", formData.email);
-      formDataToSend.append("phone", formData.phone);
-      formDataToSend.append("website", formData.website);
-      formDataToSend.append("location", formData.location);
-      formDataToSend.append("isPrivate", formData.isPrivate);
-
-      // Add skills and interests as comma-separated strings
-      formDataToSend.append("techSkills", formData.techSkills.join(","));
-      formDataToSend.append("softSkills", formData.softSkills.join(","));
-      formDataToSend.append("interests", formData.interests.join(","));
+      // The update is partial, so only send the fields that changed
+      const textFields = [
+        "username",
+        "fullName",
+        "bio",
+        "work",
+        "education",
+        "email",
+        "phone",
+        "website",
+        "location",
+        "isPrivate",
+      ];
+      textFields.forEach((field) => {
+        if (formData[field] !== initialData?.[field]) {
+          formDataToSend.append(field, formData[field]);
+        }
+      });
+
+      // Skills and interests are sent as JSON arrays
+      ["techSkills", "softSkills", "interests"].forEach((field) => {
+        const list = JSON.stringify(formData[field]);
+        if (list !== JSON.stringify(initialData?.[field])) {
+          formDataToSend.append(field, list);
+        }
+      });
 
       // Add image files only if they exist
       if (formData.bannerImage instanceof File) {
@@ -203,13 +211,19 @@ const EditProfileModal = ({
       }
       // Use authenticatedFetch instead of direct fetch
       const response = await authenticatedFetch("users/profile", {
-        method: "PUT",
+        method: "PATCH",
         body: formDataToSend,
       });
 
       if (!response.ok) {
         const errorData = await response.json();
-        throw new Error(errorData.message || "Failed to update profile");
+        // Validation errors list the reason for each invalid field
+        const fieldErrors = Object.entries(errorData.fields || {})
+          .map(([field, message]) => `${field} ${message}`)
+          .join(", ");
+        throw new Error(
+          fieldErrors || errorData.error || "Failed to update profile"
+        );
       }
 
       const updatedProfile = await response.json();
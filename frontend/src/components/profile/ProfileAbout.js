@@ -37,16 +37,10 @@ const ProfileAbout = ({
   const privacyStatus = userData?.isPublic ? "Public" : "Private";
   const privacyIcon = userData?.isPublic ? faGlobe : faLock;
 
-  // Parse skills into arrays if they exist
-  const techSkills = userData?.techSkills
-    ? userData.techSkills.split(",").map((skill) => skill.trim())
-    : [];
-  const softSkills = userData?.softSkills
-    ? userData.softSkills.split(",").map((skill) => skill.trim())
-    : [];
-  const interestsList = userData?.interests
-    ? userData.interests.split(",").map((interest) => interest.trim())
-    : [];
+  // Skills and interests come as arrays
+  const techSkills = userData?.techSkills || [];
+  const softSkills = userData?.softSkills || [];
+  const interestsList = userData?.interests || [];
 
   return (
     <div className={styles.aboutContainer}>
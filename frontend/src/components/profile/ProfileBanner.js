@@ -155,6 +155,7 @@ const ProfileBanner = ({
           isOpen={isEditModalOpen}
           onClose={() => setIsEditModalOpen(false)}
           profileData={{
+            ...userData,
             bannerUrl,
             profileUrl,
             fullName,
@@ -35,8 +35,8 @@ const ProfileConnections = ({ userData }) => {
       setIsLoading(true);
       try {
         // Use authenticatedFetch from AuthContext
-        const followingResponse = await authenticatedFetch(`follow/following?userId=${userData.id}`);
-        const followersResponse = await authenticatedFetch(`follow/followers?userId=${userData.id}`);
+        const followingResponse = await authenticatedFetch(`follow/following?userId=${userData.id}&limit=100`);
+        const followersResponse = await authenticatedFetch(`follow/followers?userId=${userData.id}&limit=100`);
 
         if (!followingResponse.ok || !followersResponse.ok) {
           throw new Error("Failed to fetch data");
@@ -45,8 +45,8 @@ const ProfileConnections = ({ userData }) => {
         const followingData = await followingResponse.json();
         const followersData = await followersResponse.json();
 
-        setFollowing(followingData);
-        setFollowers(followersData);
+        setFollowing(followingData.items || []);
+        setFollowers(followersData.items || []);
         setError(null);
       } catch (err) {
         console.error("Error fetching connections data:", err);
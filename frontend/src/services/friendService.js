@@ -103,7 +103,7 @@ export const useFriendService = () => {
   const fetchContacts = useCallback(async () => {
     setIsLoadingContacts(true);
     try {
-      const response = await authenticatedFetch("follow/following", {
+      const response = await authenticatedFetch("follow/following?limit=100", {
         method: "GET",
       });
 
@@ -116,11 +116,11 @@ export const useFriendService = () => {
 
       const data = await response.json();
 
-      if (!data) {
+      if (!data || !data.items) {
         return [];
       }
       // Transform the data to match our component's expected format
-      const formattedContacts = data.map((contact) => ({
+      const formattedContacts = data.items.map((contact) => ({
         id: contact.ID,
         name: contact.UserName,
         image: contact.UserAvatar
@@ -259,7 +259,7 @@ export const useFriendService = () => {
   // Add this function to the useFriendService hook
   const fetchUserFollowers = async () => {
     try {
-      const response = await authenticatedFetch("follow/followers", {
+      const response = await authenticatedFetch("follow/followers?limit=100", {
         method: "GET",
       });
 
@@ -272,14 +272,14 @@ export const useFriendService = () => {
 
       const data = await response.json();
 
-      if (!data) {
+      if (!data || !data.items) {
         return [];
       }
 
       console.log("Fetched followers:", data);
 
       // Transform the data to match our component's expected format
-      const formattedFollowers = data.map((follower) => ({
+      const formattedFollowers = data.items.map((follower) => ({
         id: follower.FollowerID,
         name: follower.UserName,
         image: follower.UserAvatar
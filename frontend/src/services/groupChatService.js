@@ -5,6 +5,9 @@ import { useAuth } from "@/context/authcontext";
 // Assuming EVENT_TYPES in websocketService.js includes group-specific events
 const GROUP_EVENT_TYPES = {
   GROUP_MESSAGE: "group_message",
+  GROUP_MESSAGE_EDITED: "group_message_edited",
+  GROUP_MESSAGE_DELETED: "group_message_deleted",
+  GROUP_MESSAGE_PINNED: "group_message_pinned",
   GROUP_MESSAGES_READ: "group_messages_read",
   // GROUP_USER_TYPING: "group_user_typing",
   GROUP_USER_JOINED: "group_user_joined",
@@ -50,9 +53,9 @@ export const useGroupChatService = (groupId) => {
     [authenticatedFetch, groupId]
   );
 
-  // Send a message to the group
+  // Send a message to the group, optionally replying to one of its messages
   const sendMessage = useCallback(
-    async (content) => {
+    async (content, replyToId = 0) => {
       try {
         const response = await authenticatedFetch("groups/send-message", {
           method: "POST",
@@ -62,6 +65,7 @@ export const useGroupChatService = (groupId) => {
           body: JSON.stringify({
             groupId,
             content,
+            replyToId,
           }),
         });
 
@@ -77,10 +81,154 @@ export const useGroupChatService = (groupId) => {
     [authenticatedFetch, groupId]
   );
 
+  // Edit one of the user's own messages
+  const editMessage = useCallback(
+    async (messageId, content) => {
+      try {
+        const response = await authenticatedFetch("groups/messages", {
+          method: "PUT",
+          headers: {
+            "Content-Type": "application/json",
+          },
+          body: JSON.stringify({
+            messageId,
+            content,
+          }),
+        });
+
+        if (!response.ok) throw new Error("Failed to edit group message");
+        const message = await response.json();
+
+        setMessages((prev) => prev.map((msg) => (msg.id === messageId ? { ...msg, ...message } : msg)));
+        return message;
+      } catch (error) {
+        console.error("Error editing group message:", error);
+        throw error;
+      }
+    },
+    [authenticatedFetch]
+  );
+
+  // Delete a message
+  const deleteMessage = useCallback(
+    async (messageId) => {
+      try {
+        const response = await authenticatedFetch(`groups/messages?messageId=${messageId}`, {
+          method: "DELETE",
+        });
+
+        if (!response.ok) throw new Error("Failed to delete group message");
+
+        setMessages((prev) => prev.filter((msg) => msg.id !== messageId));
+        return true;
+      } catch (error) {
+        console.error("Error deleting group message:", error);
+        throw error;
+      }
+    },
+    [authenticatedFetch]
+  );
+
+  // Pin or unpin a message
+  const pinMessage = useCallback(
+    async (messageId, pinned = true) => {
+      try {
+        const response = pinned
+          ? await authenticatedFetch("groups/messages/pins", {
+              method: "POST",
+              headers: {
+                "Content-Type": "application/json",
+              },
+              body: JSON.stringify({ messageId }),
+            })
+          : await authenticatedFetch(`groups/messages/pins?messageId=${messageId}`, {
+              method: "DELETE",
+            });
+
+        if (!response.ok) throw new Error("Failed to update pinned message");
+
+        return true;
+      } catch (error) {
+        console.error("Error pinning group message:", error);
+        throw error;
+      }
+    },
+    [authenticatedFetch]
+  );
+
+  // Load the group's pinned messages
+  const loadPinnedMessages = useCallback(async () => {
+    try {
+      const response = await authenticatedFetch(`groups/messages/pins?groupId=${groupId}`);
+      if (!response.ok) throw new Error("Failed to load pinned messages");
+
+      return await response.json();
+    } catch (error) {
+      console.error("Error loading pinned group messages:", error);
+      return [];
+    }
+  }, [authenticatedFetch, groupId]);
 
+  // Mark the group's messages as read, up to the latest one by default
+  const markMessagesAsRead = useCallback(
+    async (messageId = 0) => {
+      try {
+        const response = await authenticatedFetch("groups/messages/read", {
+          method: "POST",
+          headers: {
+            "Content-Type": "application/json",
+          },
+          body: JSON.stringify({
+            groupId,
+            messageId,
+          }),
+        });
+
+        if (!response.ok) throw new Error("Failed to mark group messages as read");
+
+        return true;
+      } catch (error) {
+        console.error("Error marking group messages as read:", error);
+        return false;
+      }
+    },
+    [authenticatedFetch, groupId]
+  );
+
+  // Load how far each member has read the group's messages
+  const loadReadCursors = useCallback(async () => {
+    try {
+      const response = await authenticatedFetch(`groups/messages/read?groupId=${groupId}`);
+      if (!response.ok) throw new Error("Failed to load read receipts");
+
+      return await response.json();
+    } catch (error) {
+      console.error("Error loading group read receipts:", error);
+      return [];
+    }
+  }, [authenticatedFetch, groupId]);
+
+  // Load the page of messages starting just before the first unread one
+  const jumpToFirstUnread = useCallback(
+    async (limit = 50) => {
+      try {
+        const response = await authenticatedFetch(
+          `groups/get-messages/unread?groupId=${groupId}&limit=${limit}`
+        );
+        if (!response.ok) throw new Error("Failed to load unread group messages");
 
+        const page = await response.json();
+        setMessages(page.messages || []);
+        setUnreadCounts(page.unreadCount);
+        return page;
+      } catch (error) {
+        console.error("Error loading unread group messages:", error);
+        return null;
+      }
+    },
+    [authenticatedFetch, groupId]
+  );
 
-  
   // Initialize WebSocket subscriptions
   const initializeWebSocketSubscriptions = useCallback(() => {
     if (!currentUser?.id || isInitialized || !groupId) return;
@@ -132,6 +280,35 @@ export const useGroupChatService = (groupId) => {
       }
     });
     
+    // Handle edited group messages
+    const editedUnsubscribe = subscribe(GROUP_EVENT_TYPES.GROUP_MESSAGE_EDITED, (payload) => {
+      if (!payload || payload.GroupID !== groupId) return;
+
+      setMessages((prev) =>
+        prev.map((msg) =>
+          msg.id === payload.id
+            ? { ...msg, Content: payload.Content, IsEdited: true, EditedAt: payload.EditedAt }
+            : msg
+        )
+      );
+    });
+
+    // Handle deleted group messages
+    const deletedUnsubscribe = subscribe(GROUP_EVENT_TYPES.GROUP_MESSAGE_DELETED, (payload) => {
+      if (!payload || payload.GroupID !== groupId) return;
+
+      setMessages((prev) => prev.filter((msg) => msg.id !== payload.id));
+    });
+
+    // Handle pinned and unpinned group messages
+    const pinnedUnsubscribe = subscribe(GROUP_EVENT_TYPES.GROUP_MESSAGE_PINNED, (payload) => {
+      if (!payload || payload.GroupID !== groupId) return;
+
+      setMessages((prev) =>
+        prev.map((msg) => (msg.id === payload.id ? { ...msg, IsPinned: payload.pinned } : msg))
+      );
+    });
+
     // Handle group messages read
     const readUnsubscribe = subscribe(GROUP_EVENT_TYPES.GROUP_MESSAGES_READ, (payload) => {
       if (!payload || payload.groupId !== groupId) return;
@@ -197,6 +374,9 @@ export const useGroupChatService = (groupId) => {
 
     return () => {
       messageUnsubscribe();
+      editedUnsubscribe();
+      deletedUnsubscribe();
+      pinnedUnsubscribe();
       readUnsubscribe();
       typingUnsubscribe();
       joinUnsubscribe();
@@ -252,7 +432,13 @@ export const useGroupChatService = (groupId) => {
   return {
     loadMessages,
     sendMessage,
-    // markMessagesAsRead,
+    editMessage,
+    deleteMessage,
+    pinMessage,
+    loadPinnedMessages,
+    markMessagesAsRead,
+    loadReadCursors,
+    jumpToFirstUnread,
     // loadActiveUsers,
     messages,
     setMessages, 
@@ -161,6 +161,46 @@ export const useGroupService = () => {
         }
     }
 
+    const restoreGroup = async (groupId) => {
+        try {
+            const response = await authenticatedFetch("groups/restore", {
+                method: "POST",
+                body: JSON.stringify({ groupId }),
+            });
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to restore group");
+            }
+
+            showToast("Group restored", "success");
+            return true;
+        } catch (error) {
+            console.error("Error restoring group:", error);
+            showToast(error.message || "Error restoring group", "error");
+            return false;
+        }
+    };
+
+    const transferOwnership = async (groupId, userId) => {
+        try {
+            const response = await authenticatedFetch("groups/transfer-ownership", {
+                method: "POST",
+                body: JSON.stringify({ groupId, userId }),
+            });
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to transfer ownership");
+            }
+
+            showToast("Ownership transferred", "success");
+            return true;
+        } catch (error) {
+            console.error("Error transferring group ownership:", error);
+            showToast(error.message || "Error transferring ownership", "error");
+            return false;
+        }
+    };
+
     const deleteGroup = async (groupId) => {
         try {
             const response = await authenticatedFetch(`groups?id=${groupId}`, {
@@ -202,14 +242,14 @@ export const useGroupService = () => {
         }
     };
 
-    const joinGroup = async (groupId) => {
+    const joinGroup = async (groupId, answers = []) => {
         try {
             const response = await authenticatedFetch("groups/join", {
                 method: "POST",
                 headers: {
                     "Content-Type": "application/json",
                 },
-                body: JSON.stringify({ groupId }),
+                body: JSON.stringify({ groupId, answers }),
             });
 
             if (!response.ok) {
@@ -288,7 +328,11 @@ export const useGroupService = () => {
             }
 
             const data = await response.json();
-            showToast("Post created successfully!", "success");
+            if (data.Status === "pending") {
+                showToast("Post submitted for review by the group's moderators", "info");
+            } else {
+                showToast("Post created successfully!", "success");
+            }
             return data;
         } catch (error) {
             console.error("Error creating post:", error);
@@ -297,6 +341,505 @@ export const useGroupService = () => {
         }
     };
 
+    const discoverGroups = async ({ q = "", category = "", tag = "", sort = "", limit = 10, offset = 0 } = {}) => {
+        try {
+            const params = new URLSearchParams({ q, category, tag, sort, limit, offset });
+            const response = await authenticatedFetch(`groups/discover?${params}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to discover groups"
+                );
+            }
+
+            return (await response.json()) || [];
+        } catch (error) {
+            console.error("Error discovering groups:", error);
+            showToast(error.message || "Error discovering groups", "error");
+            return [];
+        }
+    };
+
+    const getRecommendedGroups = async (limit = 10) => {
+        try {
+            const response = await authenticatedFetch(`groups/recommended?limit=${limit}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to fetch recommended groups"
+                );
+            }
+
+            return (await response.json()) || [];
+        } catch (error) {
+            console.error("Error fetching recommended groups:", error);
+            return [];
+        }
+    };
+
+    const getGroupCategories = async () => {
+        try {
+            const response = await authenticatedFetch("groups/categories", {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                throw new Error("Failed to fetch group categories");
+            }
+
+            return (await response.json()) || [];
+        } catch (error) {
+            console.error("Error fetching group categories:", error);
+            return [];
+        }
+    };
+
+    const createInviteLink = async (groupId, { expiresAt = null, maxUses = 0, requiresApproval = false } = {}) => {
+        try {
+            const response = await authenticatedFetch("groups/invite-links", {
+                method: "POST",
+                body: JSON.stringify({ groupId, expiresAt, maxUses, requiresApproval }),
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to create invite link"
+                );
+            }
+
+            showToast("Invite link created", "success");
+            return await response.json();
+        } catch (error) {
+            console.error("Error creating invite link:", error);
+            showToast(error.message || "Error creating invite link", "error");
+            return null;
+        }
+    };
+
+    const getInviteLinks = async (groupId) => {
+        try {
+            const response = await authenticatedFetch(`groups/invite-links?groupId=${groupId}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to fetch invite links"
+                );
+            }
+
+            return (await response.json()) || [];
+        } catch (error) {
+            console.error("Error fetching invite links:", error);
+            showToast(error.message || "Error fetching invite links", "error");
+            return [];
+        }
+    };
+
+    const revokeInviteLink = async (linkId) => {
+        try {
+            const response = await authenticatedFetch(`groups/invite-links?linkId=${linkId}`, {
+                method: "DELETE",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to revoke invite link"
+                );
+            }
+
+            showToast("Invite link revoked", "success");
+            return true;
+        } catch (error) {
+            console.error("Error revoking invite link:", error);
+            showToast(error.message || "Error revoking invite link", "error");
+            return false;
+        }
+    };
+
+    const getInviteLinkUses = async (linkId) => {
+        try {
+            const response = await authenticatedFetch(`groups/invite-links/uses?linkId=${linkId}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to fetch invite link history"
+                );
+            }
+
+            return (await response.json()) || [];
+        } catch (error) {
+            console.error("Error fetching invite link uses:", error);
+            showToast(error.message || "Error fetching invite link history", "error");
+            return [];
+        }
+    };
+
+    const redeemInviteLink = async (token, answers = []) => {
+        try {
+            const response = await authenticatedFetch("groups/invite-links/redeem", {
+                method: "POST",
+                body: JSON.stringify({ token, answers }),
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to use invite link"
+                );
+            }
+
+            const redemption = await response.json();
+            showToast(
+                redemption.outcome === "requested" ? "Join request sent" : "You joined the group",
+                "success"
+            );
+            return redemption;
+        } catch (error) {
+            console.error("Error redeeming invite link:", error);
+            showToast(error.message || "Error using invite link", "error");
+            return null;
+        }
+    };
+
+    const banMember = async (groupId, userId, reason = "", expiresAt = null) => {
+        try {
+            const response = await authenticatedFetch("groups/bans", {
+                method: "POST",
+                body: JSON.stringify({ groupId, userId, reason, expiresAt }),
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to ban member");
+            }
+
+            showToast("Member banned", "success");
+            return true;
+        } catch (error) {
+            console.error("Error banning member:", error);
+            showToast(error.message || "Error banning member", "error");
+            return false;
+        }
+    };
+
+    const unbanMember = async (groupId, userId) => {
+        try {
+            const response = await authenticatedFetch(
+                `groups/bans?groupId=${groupId}&userId=${userId}`,
+                {
+                    method: "DELETE",
+                }
+            );
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to unban member");
+            }
+
+            showToast("Member unbanned", "success");
+            return true;
+        } catch (error) {
+            console.error("Error unbanning member:", error);
+            showToast(error.message || "Error unbanning member", "error");
+            return false;
+        }
+    };
+
+    const getGroupBans = async (groupId) => {
+        try {
+            const response = await authenticatedFetch(`groups/bans?groupId=${groupId}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to fetch bans");
+            }
+
+            return await response.json();
+        } catch (error) {
+            console.error("Error fetching group bans:", error);
+            showToast(error.message || "Error fetching bans", "error");
+            return [];
+        }
+    };
+
+    const getJoinQuestions = async (groupId) => {
+        try {
+            const response = await authenticatedFetch(`groups/join-questions?groupId=${groupId}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to fetch join questions");
+            }
+
+            return await response.json();
+        } catch (error) {
+            console.error("Error fetching join questions:", error);
+            showToast(error.message || "Error fetching join questions", "error");
+            return [];
+        }
+    };
+
+    const setJoinQuestions = async (groupId, questions) => {
+        try {
+            const response = await authenticatedFetch("groups/join-questions", {
+                method: "PUT",
+                body: JSON.stringify({ groupId, questions }),
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to save join questions");
+            }
+
+            showToast("Join questions saved", "success");
+            return await response.json();
+        } catch (error) {
+            console.error("Error saving join questions:", error);
+            showToast(error.message || "Error saving join questions", "error");
+            return null;
+        }
+    };
+
+    const getGroupNotificationSettings = async (groupId) => {
+        try {
+            const response = await authenticatedFetch(`groups/notification-settings?groupId=${groupId}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to fetch notification settings");
+            }
+
+            return await response.json();
+        } catch (error) {
+            console.error("Error fetching notification settings:", error);
+            showToast(error.message || "Error fetching notification settings", "error");
+            return null;
+        }
+    };
+
+    // level is "all", "mentions" or "none"; mutedUntil is null to unmute
+    const updateGroupNotificationSettings = async (groupId, { level, chatEnabled, mutedUntil = null }) => {
+        try {
+            const response = await authenticatedFetch("groups/notification-settings", {
+                method: "PUT",
+                body: JSON.stringify({ groupId, level, chatEnabled, mutedUntil }),
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(errorData.message || "Failed to save notification settings");
+            }
+
+            showToast("Notification settings saved", "success");
+            return await response.json();
+        } catch (error) {
+            console.error("Error saving notification settings:", error);
+            showToast(error.message || "Error saving notification settings", "error");
+            return null;
+        }
+    };
+
+    const getPendingGroupPosts = async (groupId) => {
+        try {
+            const response = await authenticatedFetch(`groups/posts/pending?groupId=${groupId}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to fetch pending posts"
+                );
+            }
+
+            return (await response.json()) || [];
+        } catch (error) {
+            console.error("Error fetching pending group posts:", error);
+            showToast(error.message || "Error fetching pending posts", "error");
+            return [];
+        }
+    };
+
+    const reviewGroupPost = async (postId, approve) => {
+        try {
+            const response = await authenticatedFetch("groups/posts/review", {
+                method: "POST",
+                body: JSON.stringify({ postId, approve }),
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to review post"
+                );
+            }
+
+            showToast(approve ? "Post approved" : "Post rejected", "success");
+            return true;
+        } catch (error) {
+            console.error("Error reviewing group post:", error);
+            showToast(error.message || "Error reviewing post", "error");
+            return false;
+        }
+    };
+
+    const pinGroupPost = async (postId, expiresAt = null) => {
+        try {
+            const response = await authenticatedFetch("groups/posts/pin", {
+                method: "POST",
+                body: JSON.stringify({ postId, expiresAt }),
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to pin post"
+                );
+            }
+
+            showToast("Post pinned", "success");
+            return true;
+        } catch (error) {
+            console.error("Error pinning group post:", error);
+            showToast(error.message || "Error pinning post", "error");
+            return false;
+        }
+    };
+
+    const unpinGroupPost = async (postId) => {
+        try {
+            const response = await authenticatedFetch(`groups/posts/pin?postId=${postId}`, {
+                method: "DELETE",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to unpin post"
+                );
+            }
+
+            showToast("Post unpinned", "success");
+            return true;
+        } catch (error) {
+            console.error("Error unpinning group post:", error);
+            showToast(error.message || "Error unpinning post", "error");
+            return false;
+        }
+    };
+
+    const likeGroupPost = async (postId) => {
+        try {
+            const response = await authenticatedFetch(`groups/posts/like?postId=${postId}`, {
+                method: "POST",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to like post"
+                );
+            }
+
+            return await response.json();
+        } catch (error) {
+            console.error("Error liking group post:", error);
+            showToast(error.message || "Error liking post", "error");
+            throw error;
+        }
+    };
+
+    const addGroupPostComment = async (postId, content, image) => {
+        try {
+            const formData = new FormData();
+            formData.append("postId", postId);
+            formData.append("content", content);
+            if (image) {
+                formData.append("image", image);
+            }
+
+            const response = await authenticatedFetch("groups/posts/comments", {
+                method: "POST",
+                body: formData,
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to add comment"
+                );
+            }
+
+            return await response.json();
+        } catch (error) {
+            console.error("Error adding group post comment:", error);
+            showToast(error.message || "Error adding comment", "error");
+            throw error;
+        }
+    };
+
+    const getGroupPostComments = async (postId) => {
+        try {
+            const response = await authenticatedFetch(`groups/posts/comments?postId=${postId}`, {
+                method: "GET",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to fetch comments"
+                );
+            }
+
+            return await response.json();
+        } catch (error) {
+            console.error("Error fetching group post comments:", error);
+            showToast(error.message || "Error fetching comments", "error");
+            throw error;
+        }
+    };
+
+    const deleteGroupPostComment = async (commentId) => {
+        try {
+            const response = await authenticatedFetch(`groups/posts/comments?commentId=${commentId}`, {
+                method: "DELETE",
+            });
+
+            if (!response.ok) {
+                const errorData = await response.json().catch(() => ({}));
+                throw new Error(
+                    errorData.message || errorData.error || "Failed to delete comment"
+                );
+            }
+
+            showToast("Comment deleted successfully!", "success");
+            return true;
+        } catch (error) {
+            console.error("Error deleting group post comment:", error);
+            showToast(error.message || "Error deleting comment", "error");
+            throw error;
+        }
+    };
+
     const createEvent = async (groupId, eventData) => {
         try {
             const formData = new FormData();
@@ -520,9 +1063,34 @@ export const useGroupService = () => {
         getgroup,
         getgrouponly,
         createPost,
+        discoverGroups,
+        getRecommendedGroups,
+        getGroupCategories,
+        createInviteLink,
+        getInviteLinks,
+        revokeInviteLink,
+        getInviteLinkUses,
+        redeemInviteLink,
+        banMember,
+        unbanMember,
+        getGroupBans,
+        getJoinQuestions,
+        setJoinQuestions,
+        getGroupNotificationSettings,
+        updateGroupNotificationSettings,
+        getPendingGroupPosts,
+        reviewGroupPost,
+        pinGroupPost,
+        unpinGroupPost,
+        likeGroupPost,
+        addGroupPostComment,
+        getGroupPostComments,
+        deleteGroupPostComment,
         getusergroups,
         getallgroups,
         deleteGroup,
+        restoreGroup,
+        transferOwnership,
         leaveGroup,
         getgroupposts,
         joinGroup,
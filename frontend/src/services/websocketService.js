@@ -9,8 +9,10 @@ import { showToast } from "@/components/ui/ToastContainer";
 export const EVENT_TYPES = {
   POST_CREATED: "post_created",
   POST_LIKED: "post_liked",
+  COMMENT_COUNT_UPDATE: "comment_count_update",
   USER_STATS_UPDATED: "user_stats_updated",
   USER_STATUS_UPDATE: "user_status_update",
+  USER_CUSTOM_STATUS_UPDATE: "user_custom_status_update",
   // Chat events
   PRIVATE_MESSAGE: "private_message",
   MESSAGES_READ: "messages_read",
@@ -35,6 +37,9 @@ const STORAGE_KEY = "ws_connection_info";
 // Add debounce variables for status messages
 let statusMessageTimeout = null;
 const STATUS_DEBOUNCE_DELAY = 500; // 500ms debounce
+// Report user activity at most once a minute so the server can detect idle users
+let lastActivitySent = 0;
+const ACTIVITY_THROTTLE_DELAY = 60000;
 let isOnline = navigator.onLine;
 let lastPongTime = Date.now();
 
@@ -246,10 +251,28 @@ if (isAuthenticated && token && !loading && !globalSocket) {
       }
     };
 
+    // Tell the server the user is active so they aren't shown as idle
+    const handleUserActivity = () => {
+      const now = Date.now();
+      if (now - lastActivitySent < ACTIVITY_THROTTLE_DELAY) {
+        return;
+      }
+      if (globalSocket && globalSocket.readyState === WebSocket.OPEN) {
+        globalSocket.send(JSON.stringify({ type: "activity" }));
+        lastActivitySent = now;
+      }
+    };
+
     document.addEventListener("visibilitychange", handleVisibilityChange);
+    window.addEventListener("mousemove", handleUserActivity);
+    window.addEventListener("keydown", handleUserActivity);
+    window.addEventListener("touchstart", handleUserActivity);
 
     return () => {
       document.removeEventListener("visibilitychange", handleVisibilityChange);
+      window.removeEventListener("mousemove", handleUserActivity);
+      window.removeEventListener("keydown", handleUserActivity);
+      window.removeEventListener("touchstart", handleUserActivity);
       window.removeEventListener('online', handleNetworkChange);
       window.removeEventListener('offline', handleNetworkChange);
       // Clear any pending status message timeout on unmount